@@ -0,0 +1,78 @@
+package capture
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+)
+
+// GPSPolicy controls what happens to location data during anonymization.
+type GPSPolicy string
+
+const (
+	// GPSStrip removes location data entirely.
+	GPSStrip GPSPolicy = "strip"
+	// GPSFuzz offsets positions by a deterministic amount within
+	// FuzzRadiusM, hiding the exact home location while keeping routes
+	// roughly intact.
+	GPSFuzz GPSPolicy = "fuzz"
+)
+
+// AnonymizeOptions configures Anonymize.
+type AnonymizeOptions struct {
+	// Salt keys the VIN pseudonym. The same salt maps a VIN to the same
+	// pseudonym across sessions, so related captures stay correlatable
+	// without revealing the vehicle.
+	Salt string
+	// GPS selects the location policy; default is GPSStrip.
+	GPS GPSPolicy
+	// FuzzRadiusM is the maximum offset in metres for GPSFuzz.
+	FuzzRadiusM float64
+}
+
+// PseudonymizeVIN maps a VIN to a stable anonymous identifier under salt.
+func PseudonymizeVIN(vin, salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(vin))
+	return "ANON-" + strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:6]))
+}
+
+// FuzzLocation deterministically offsets a coordinate by up to radiusM
+// metres, keyed so the same input always moves the same way.
+func FuzzLocation(lat, lon, radiusM float64, key string) (float64, float64) {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("gps-fuzz"))
+	sum := mac.Sum(nil)
+	// Two uniform values in [0,1) from the digest.
+	u1 := float64(binary.BigEndian.Uint32(sum[0:4])) / float64(1<<32)
+	u2 := float64(binary.BigEndian.Uint32(sum[4:8])) / float64(1<<32)
+	dist := radiusM * math.Sqrt(u1) // uniform over the disc
+	angle := 2 * math.Pi * u2
+	const metresPerDegLat = 111_320.0
+	dLat := dist * math.Cos(angle) / metresPerDegLat
+	dLon := dist * math.Sin(angle) / (metresPerDegLat * math.Cos(lat*math.Pi/180))
+	return lat + dLat, lon + dLon
+}
+
+// Anonymize rewrites the session in place so it can be shared publicly:
+// the VIN (and the VIN embedded in the session ID) is replaced with a
+// salted pseudonym, and the GPS track follows the location policy —
+// stripped by default, or offset as one piece by FuzzLocation so the
+// route keeps its shape but not its place. The fuzz key includes the
+// real VIN, so different vehicles shift different ways under one salt.
+func (s *Session) Anonymize(opts AnonymizeOptions) {
+	pseudo := PseudonymizeVIN(s.VIN, opts.Salt)
+	s.ID = strings.ReplaceAll(s.ID, s.VIN, pseudo)
+	key := opts.Salt + s.VIN
+	s.VIN = pseudo
+	if opts.GPS == GPSFuzz {
+		for i, p := range s.GPS {
+			s.GPS[i].Latitude, s.GPS[i].Longitude = FuzzLocation(p.Latitude, p.Longitude, opts.FuzzRadiusM, key)
+		}
+		return
+	}
+	s.GPS = nil
+}
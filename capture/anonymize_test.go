@@ -0,0 +1,77 @@
+package capture
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPseudonymizeVINIsStablePerSalt(t *testing.T) {
+	a := PseudonymizeVIN("KMFWB37HXTESTFIX0", "salt1")
+	b := PseudonymizeVIN("KMFWB37HXTESTFIX0", "salt1")
+	c := PseudonymizeVIN("KMFWB37HXTESTFIX0", "salt2")
+	if a != b {
+		t.Errorf("same salt produced different pseudonyms: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Errorf("different salts produced the same pseudonym: %s", a)
+	}
+}
+
+func TestAnonymizeRemovesVIN(t *testing.T) {
+	s := NewSession("KMFWB37HXTESTFIX0")
+	s.Anonymize(AnonymizeOptions{Salt: "share"})
+	if s.VIN == "KMFWB37HXTESTFIX0" {
+		t.Fatal("VIN survived anonymization")
+	}
+	if got := s.ID; got != "" && containsVIN(got) {
+		t.Errorf("session ID still contains the VIN: %s", got)
+	}
+}
+
+func containsVIN(s string) bool {
+	return len(s) >= 17 && (s[:17] == "KMFWB37HXTESTFIX0" || s == "KMFWB37HXTESTFIX0")
+}
+
+func TestAnonymizeGPSPolicy(t *testing.T) {
+	track := []GPSPoint{
+		{Latitude: -33.8688, Longitude: 151.2093},
+		{Latitude: -33.8700, Longitude: 151.2100},
+	}
+
+	s := NewSession("KMFWB37HXTESTFIX0")
+	s.GPS = append([]GPSPoint(nil), track...)
+	s.Anonymize(AnonymizeOptions{Salt: "share"})
+	if s.GPS != nil {
+		t.Errorf("default policy kept the GPS track: %v", s.GPS)
+	}
+
+	s = NewSession("KMFWB37HXTESTFIX0")
+	s.GPS = append([]GPSPoint(nil), track...)
+	s.Anonymize(AnonymizeOptions{Salt: "share", GPS: GPSFuzz, FuzzRadiusM: 500})
+	if len(s.GPS) != 2 {
+		t.Fatalf("fuzz dropped points: %v", s.GPS)
+	}
+	if s.GPS[0] == track[0] {
+		t.Error("fuzz left the first point in place")
+	}
+	// The whole track moves as one piece, so the route keeps its shape.
+	if d := (s.GPS[1].Latitude - s.GPS[0].Latitude) - (track[1].Latitude - track[0].Latitude); math.Abs(d) > 1e-9 {
+		t.Errorf("fuzz warped the track: lat delta changed by %v", d)
+	}
+}
+
+func TestFuzzLocationStaysWithinRadius(t *testing.T) {
+	lat, lon := -33.8688, 151.2093
+	fLat, fLon := FuzzLocation(lat, lon, 500, "key")
+	const metresPerDegLat = 111_320.0
+	dLat := (fLat - lat) * metresPerDegLat
+	dLon := (fLon - lon) * metresPerDegLat * math.Cos(lat*math.Pi/180)
+	if dist := math.Hypot(dLat, dLon); dist > 500 {
+		t.Errorf("fuzzed position %v metres away, want <= 500", dist)
+	}
+	// Deterministic for the same key.
+	fLat2, fLon2 := FuzzLocation(lat, lon, 500, "key")
+	if fLat != fLat2 || fLon != fLon2 {
+		t.Error("fuzz is not deterministic for the same key")
+	}
+}
@@ -0,0 +1,167 @@
+package capture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Vector BLF import, read-only: the binary trace format CANoe logs to
+// by default. The file is a header followed by log-container objects,
+// usually zlib-compressed, each holding a run of smaller objects; only
+// the classic CAN message objects are extracted — everything else
+// (statistics, environment variables, FD messages) is skipped. Writing
+// BLF is not supported; export as ASC for Vector tooling instead.
+
+const (
+	blfObjLogContainer = 10
+	blfObjCANMessage   = 1
+	blfObjCANMessage2  = 86
+
+	// blfBaseHeaderLen is the fixed object header every object starts
+	// with: signature, header size, header version, object size, type.
+	blfBaseHeaderLen = 16
+	// blfContainerDataOff is where a log container's payload starts,
+	// after the base header plus compression method and size fields.
+	blfContainerDataOff = 32
+
+	blfZlibCompression = 2
+
+	// Object flag bits selecting the timestamp unit.
+	blfFlagTimeTenMics = 0x1
+	blfFlagTimeNanos   = 0x2
+
+	// CAN message flag bit for remote frames, which carry no data.
+	blfMsgRTR = 0x80
+)
+
+// parseBLF decodes a BLF file into s.
+func parseBLF(data []byte, s *Session) error {
+	if len(data) < 144 {
+		return fmt.Errorf("truncated BLF header")
+	}
+	headerSize := binary.LittleEndian.Uint32(data[4:])
+	if uint64(headerSize) > uint64(len(data)) {
+		return fmt.Errorf("bad BLF header size %d", headerSize)
+	}
+	// Measurement start time, a SYSTEMTIME at offset 40. BLF records it
+	// in the logging PC's local time with no zone; UTC is the least
+	// wrong assumption available.
+	st := data[40:56]
+	start := time.Date(
+		int(binary.LittleEndian.Uint16(st[0:])),
+		time.Month(binary.LittleEndian.Uint16(st[2:])),
+		int(binary.LittleEndian.Uint16(st[6:])),
+		int(binary.LittleEndian.Uint16(st[8:])),
+		int(binary.LittleEndian.Uint16(st[10:])),
+		int(binary.LittleEndian.Uint16(st[12:])),
+		int(binary.LittleEndian.Uint16(st[14:]))*int(time.Millisecond),
+		time.UTC,
+	)
+	startNanos := start.UnixNano()
+	if start.Year() == 0 {
+		startNanos = 0 // header never filled in; keep offsets relative
+	}
+	if err := parseBLFObjects(data[headerSize:], startNanos, s); err != nil {
+		return err
+	}
+	if s.StartTime == 0 && len(s.Frames) > 0 {
+		s.StartTime = s.Frames[0].Timestamp
+	}
+	return nil
+}
+
+// parseBLFObjects walks a run of LOBJ objects — the top level of the
+// file or the inside of a decompressed container.
+func parseBLFObjects(data []byte, startNanos int64, s *Session) error {
+	for len(data) >= blfBaseHeaderLen {
+		if string(data[:4]) != "LOBJ" {
+			return fmt.Errorf("bad object signature % X", data[:4])
+		}
+		headerSize := binary.LittleEndian.Uint16(data[4:])
+		objSize := binary.LittleEndian.Uint32(data[8:])
+		objType := binary.LittleEndian.Uint32(data[12:])
+		if objSize < blfBaseHeaderLen || uint64(objSize) > uint64(len(data)) {
+			return fmt.Errorf("bad object size %d", objSize)
+		}
+		obj := data[:objSize]
+		switch objType {
+		case blfObjLogContainer:
+			if err := parseBLFContainer(obj, startNanos, s); err != nil {
+				return err
+			}
+		case blfObjCANMessage, blfObjCANMessage2:
+			parseBLFMessage(obj, int(headerSize), startNanos, s)
+		}
+		// Objects are padded to 32-bit alignment.
+		next := (objSize + 3) &^ 3
+		if uint64(next) > uint64(len(data)) {
+			break
+		}
+		data = data[next:]
+	}
+	return nil
+}
+
+// parseBLFContainer unwraps one log container, inflating it when
+// zlib-compressed, and parses the objects inside.
+func parseBLFContainer(obj []byte, startNanos int64, s *Session) error {
+	if len(obj) < blfContainerDataOff {
+		return fmt.Errorf("truncated log container")
+	}
+	method := binary.LittleEndian.Uint16(obj[blfBaseHeaderLen:])
+	payload := obj[blfContainerDataOff:]
+	if method == blfZlibCompression {
+		zr, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("log container: %w", err)
+		}
+		defer zr.Close()
+		if payload, err = io.ReadAll(zr); err != nil {
+			return fmt.Errorf("log container: %w", err)
+		}
+	}
+	return parseBLFObjects(payload, startNanos, s)
+}
+
+// parseBLFMessage extracts one classic CAN message object. Malformed or
+// remote messages are dropped rather than failing the file — a single
+// torn object shouldn't cost the trace.
+func parseBLFMessage(obj []byte, headerSize int, startNanos int64, s *Session) {
+	if headerSize < blfBaseHeaderLen || len(obj) < headerSize+16 {
+		return
+	}
+	objFlags := binary.LittleEndian.Uint32(obj[16:])
+	ts := int64(binary.LittleEndian.Uint64(obj[24:]))
+	if objFlags&blfFlagTimeNanos == 0 {
+		ts *= 10000 // 10 µs units otherwise
+	}
+	msg := obj[headerSize:]
+	channel := binary.LittleEndian.Uint16(msg[0:])
+	msgFlags := msg[2]
+	dlc := int(msg[3])
+	if msgFlags&blfMsgRTR != 0 || dlc > 8 || len(msg) < 8+dlc {
+		return
+	}
+	var f CANFrame
+	// BLF arbitration IDs carry the extended bit in the same position
+	// as SocketCAN's EFF flag.
+	f.ID = binary.LittleEndian.Uint32(msg[4:])
+	f.SetPayload(msg[8 : 8+dlc])
+	f.Timestamp = startNanos + ts
+	if channel > 1 {
+		f.Bus = fmt.Sprintf("can%d", channel-1)
+	}
+	if s.StartTime == 0 || f.Timestamp < s.StartTime {
+		s.StartTime = f.Timestamp
+	}
+	s.AddFrame(f)
+}
+
+// isBLF sniffs the BLF file signature.
+func isBLF(data []byte) bool {
+	return len(data) > 4 && string(data[:4]) == "LOGG"
+}
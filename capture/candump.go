@@ -0,0 +1,158 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// candump log interchange: the `candump -l` format can-utils writes and
+// canplayer reads back, one frame per line:
+//
+//	(1436509052.249713) can0 7E8#03410C1F40
+//	(1436509052.250101) can0 18DAF110#0462F19547      29-bit
+//	(1436509052.251000) can0 123##1DEADBEEF           CAN-FD, flags nibble
+//
+// Importing gives a session with no VIN or zone metadata — the log
+// doesn't carry any — and exporting drops ours, so the format is for
+// exchanging traffic with can-utils, not for archival.
+
+// parseCandump decodes a candump log into s. Remote frames are skipped
+// (CANFrame has no remote flag and they carry no data); unparseable
+// lines fail the import rather than silently thinning the traffic.
+func parseCandump(r io.Reader, s *Session) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		f, skip, err := parseCandumpLine(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if skip {
+			continue
+		}
+		if s.StartTime == 0 || f.Timestamp < s.StartTime {
+			s.StartTime = f.Timestamp
+		}
+		s.AddFrame(f)
+	}
+	return sc.Err()
+}
+
+// parseCandumpLine decodes one "(ts) iface id#data" line; skip is true
+// for remote frames.
+func parseCandumpLine(text string) (f CANFrame, skip bool, err error) {
+	fields := strings.Fields(text)
+	if len(fields) != 3 {
+		return f, false, fmt.Errorf("want \"(ts) iface id#data\", got %q", text)
+	}
+	ts := strings.Trim(fields[0], "()")
+	sec, usec, ok := strings.Cut(ts, ".")
+	if !ok {
+		return f, false, fmt.Errorf("bad timestamp %q", fields[0])
+	}
+	secN, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return f, false, fmt.Errorf("bad timestamp %q", fields[0])
+	}
+	usecN, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil {
+		return f, false, fmt.Errorf("bad timestamp %q", fields[0])
+	}
+	f.Timestamp = secN*1e9 + usecN*1000
+	f.Bus = fields[1]
+
+	idStr, rest, ok := strings.Cut(fields[2], "#")
+	if !ok {
+		return f, false, fmt.Errorf("no # separator in %q", fields[2])
+	}
+	id, err := strconv.ParseUint(idStr, 16, 32)
+	if err != nil {
+		return f, false, fmt.Errorf("bad identifier %q", idStr)
+	}
+	f.ID = uint32(id)
+	// Eight hex digits means extended framing — unless the error flag is
+	// already in the value, which is how candump renders error frames.
+	if len(idStr) == 8 && f.ID&ErrFlag == 0 {
+		f.ID |= EFFFlag
+	}
+	if strings.HasPrefix(rest, "R") {
+		return f, true, nil // remote frame
+	}
+	if fd := strings.TrimPrefix(rest, "#"); fd != rest {
+		// CAN-FD: a flags nibble then the payload.
+		if len(fd) < 1 {
+			return f, false, fmt.Errorf("FD frame missing flags nibble in %q", fields[2])
+		}
+		flags, err := strconv.ParseUint(fd[:1], 16, 8)
+		if err != nil {
+			return f, false, fmt.Errorf("bad FD flags in %q", fields[2])
+		}
+		f.FD = true
+		f.BRS = flags&0x1 != 0
+		f.ESI = flags&0x2 != 0
+		rest = fd[1:]
+	}
+	data, err := hex.DecodeString(rest)
+	if err != nil {
+		return f, false, fmt.Errorf("bad payload in %q", fields[2])
+	}
+	f.SetPayload(data)
+	return f, false, nil
+}
+
+// appendCandump renders the session's frames in candump log form.
+// Error frames are omitted — replaying them through canplayer is
+// meaningless — and frames without a bus name go out as "can0", the
+// interface canplayer transmits on by default.
+func (s *Session) appendCandump(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range s.Frames {
+		if f.IsError() {
+			continue
+		}
+		bus := f.Bus
+		if bus == "" {
+			bus = "can0"
+		}
+		sec := f.Timestamp / 1e9
+		usec := (f.Timestamp % 1e9) / 1000
+		var id string
+		if f.IsExtended() {
+			id = fmt.Sprintf("%08X", f.Arbitration())
+		} else {
+			id = fmt.Sprintf("%03X", f.Arbitration())
+		}
+		sep := "#"
+		if f.FD {
+			flags := 0
+			if f.BRS {
+				flags |= 0x1
+			}
+			if f.ESI {
+				flags |= 0x2
+			}
+			sep = fmt.Sprintf("##%X", flags)
+		}
+		fmt.Fprintf(bw, "(%d.%06d) %s %s%s%s\n",
+			sec, usec, bus, id, sep, strings.ToUpper(hex.EncodeToString(f.Payload())))
+	}
+	return bw.Flush()
+}
+
+// isCandump sniffs the candump log signature: the file starts with a
+// parenthesised timestamp.
+func isCandump(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '('
+}
@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const candumpSample = `(1436509052.249713) can0 7E8#03410C1F40
+(1436509052.250101) can1 18DAF110#0462F19547
+(1436509052.251000) can0 123##1DEADBEEF
+(1436509052.252000) can0 7DF#R
+(1436509052.253000) can0 20000088#0000000000000000
+`
+
+func TestLoadCandump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.log")
+	if err := os.WriteFile(path, []byte(candumpSample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Frames) != 4 {
+		t.Fatalf("loaded %d frames, want 4 (remote skipped)", len(s.Frames))
+	}
+	f := s.Frames[0]
+	if f.Arbitration() != 0x7E8 || f.Length != 5 || f.Bus != "can0" {
+		t.Errorf("frame 0 = %+v", f)
+	}
+	if f.Timestamp != 1436509052249713000 {
+		t.Errorf("frame 0 timestamp = %d", f.Timestamp)
+	}
+	if !s.Frames[1].IsExtended() || s.Frames[1].Arbitration() != 0x18DAF110 {
+		t.Errorf("frame 1 = %+v", s.Frames[1])
+	}
+	if !s.Frames[2].FD || !s.Frames[2].BRS || s.Frames[2].ESI {
+		t.Errorf("frame 2 FD flags = %+v", s.Frames[2])
+	}
+	if !s.Frames[3].IsError() || s.Frames[3].IsExtended() {
+		t.Errorf("frame 3 = %+v", s.Frames[3])
+	}
+	if s.StartTime != s.Frames[0].Timestamp || s.EndTime != s.Frames[3].Timestamp {
+		t.Errorf("session bounds = %d..%d", s.StartTime, s.EndTime)
+	}
+}
+
+func TestCandumpRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "dump.log")
+	if err := os.WriteFile(in, []byte(candumpSample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "replay.log")
+	if err := s.SaveEncoded(out, EncodingCandump); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// Remote frame was skipped on import, error frame on export.
+	if len(lines) != 3 {
+		t.Fatalf("exported %d lines, want 3:\n%s", len(lines), data)
+	}
+	if lines[0] != "(1436509052.249713) can0 7E8#03410C1F40" {
+		t.Errorf("line 0 = %q", lines[0])
+	}
+	if lines[1] != "(1436509052.250101) can1 18DAF110#0462F19547" {
+		t.Errorf("line 1 = %q", lines[1])
+	}
+	if lines[2] != "(1436509052.251000) can0 123##1DEADBEEF" {
+		t.Errorf("line 2 = %q", lines[2])
+	}
+}
+
+func TestCandumpRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.log")
+	if err := os.WriteFile(path, []byte("(123.456) can0 notanid#00\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSession(path); err == nil {
+		t.Error("loaded a candump log with a bad identifier")
+	}
+}
@@ -0,0 +1,111 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Encoding selects the on-disk (and wire) encoding for sessions. CBOR
+// handles frame payload bytes natively where JSON falls back to base64,
+// and is considerably smaller for long recordings.
+type Encoding string
+
+const (
+	EncodingJSON Encoding = "json"
+	EncodingCBOR Encoding = "cbor"
+	// EncodingCandump is the can-utils log format — for exchanging
+	// traffic with candump/canplayer. It carries frames only; session
+	// metadata (VIN, zone, tags) is dropped on write.
+	EncodingCandump Encoding = "candump"
+	// EncodingASC is the Vector ASC trace format — for exchanging
+	// traffic with CANoe/CANalyzer. Frames only, classic CAN only.
+	EncodingASC Encoding = "asc"
+)
+
+// ParseEncoding validates an encoding name from config.
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(name) {
+	case EncodingJSON, EncodingCBOR, EncodingCandump, EncodingASC:
+		return Encoding(name), nil
+	case "":
+		return EncodingJSON, nil
+	}
+	return "", fmt.Errorf("capture: unknown encoding %q", name)
+}
+
+// SaveEncoded writes the session to path using the given encoding.
+func (s *Session) SaveEncoded(path string, enc Encoding) error {
+	var data []byte
+	var err error
+	switch enc {
+	case EncodingCBOR:
+		data, err = cbor.Marshal(s)
+	case EncodingJSON, "":
+		data, err = json.MarshalIndent(s, "", "  ")
+	case EncodingCandump:
+		var buf bytes.Buffer
+		err = s.appendCandump(&buf)
+		data = buf.Bytes()
+	case EncodingASC:
+		var buf bytes.Buffer
+		err = s.appendASC(&buf)
+		data = buf.Bytes()
+	default:
+		return fmt.Errorf("capture: unknown encoding %q", enc)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// decode detects the encoding from the payload: JSON documents start
+// with whitespace or '{', candump logs with a parenthesised timestamp,
+// ASC traces with a "date" line, BLF files with their magic, anything
+// else is treated as CBOR.
+func decode(data []byte, s *Session) error {
+	if isEvent(data) {
+		ev, err := decodeEvent(data)
+		if err != nil {
+			return err
+		}
+		*s = *ev.Session()
+		return nil
+	}
+	// The interchange formats carry no metadata to migrate.
+	switch {
+	case isCandump(data):
+		s.Version = SessionFormatVersion
+		return parseCandump(bytes.NewReader(data), s)
+	case isASC(data):
+		s.Version = SessionFormatVersion
+		return parseASC(bytes.NewReader(data), s)
+	case isBLF(data):
+		s.Version = SessionFormatVersion
+		return parseBLF(data, s)
+	}
+	for _, b := range data {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '{':
+			return json.Unmarshal(data, s)
+		default:
+			return cbor.Unmarshal(data, s)
+		}
+	}
+	return fmt.Errorf("capture: empty session file")
+}
+
+// Convert reads a session in either encoding and rewrites it in enc.
+func Convert(inPath, outPath string, enc Encoding) error {
+	s, err := LoadSession(inPath)
+	if err != nil {
+		return err
+	}
+	return s.SaveEncoded(outPath, enc)
+}
@@ -0,0 +1,107 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EventFormatVersion is the event capture format version written by
+// this build.
+//
+// History:
+//
+//	1 — no version field; frames in the old pipeline layout
+//	    (RFC 3339 time, raw payload bytes)
+//	2 — frames use the session CANFrame schema
+const EventFormatVersion = 2
+
+// eventFrameV1 is the frame layout v1 event files carry.
+type eventFrameV1 struct {
+	Time time.Time `json:"time"`
+	Bus  string    `json:"bus"`
+	ID   uint32    `json:"id"`
+	Data []byte    `json:"data"`
+	FD   bool      `json:"fd"`
+	BRS  bool      `json:"brs"`
+	ESI  bool      `json:"esi"`
+}
+
+// LoadEvent reads an event capture file, upgrading v1 frames to the
+// current schema. Files from a newer build are refused rather than
+// silently misread.
+func LoadEvent(path string) (*Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := decodeEvent(data)
+	if err != nil {
+		return nil, fmt.Errorf("capture: parse %s: %w", path, err)
+	}
+	return ev, nil
+}
+
+// decodeEvent parses an event file in any understood version.
+func decodeEvent(data []byte) (*Event, error) {
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Version > EventFormatVersion {
+		return nil, fmt.Errorf("event format v%d is newer than this build understands (v%d)",
+			probe.Version, EventFormatVersion)
+	}
+	var ev Event
+	if probe.Version >= 2 {
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		return &ev, nil
+	}
+	// v1: decode the frames in their old layout and convert.
+	var old struct {
+		Event
+		Frames []eventFrameV1 `json:"frames"`
+	}
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+	ev = old.Event
+	for _, of := range old.Frames {
+		f := CANFrame{ID: of.ID, Bus: of.Bus, FD: of.FD, BRS: of.BRS, ESI: of.ESI}
+		f.SetPayload(of.Data)
+		f.Timestamp = of.Time.UnixNano()
+		ev.Frames = append(ev.Frames, f)
+	}
+	ev.Version = EventFormatVersion
+	return &ev, nil
+}
+
+// Session converts the event's bus traffic into an ordinary session, so
+// the analyzer and exporters consume event captures like any other
+// recording. Telemetry samples don't carry over — they live in the
+// datastore already.
+func (ev *Event) Session() *Session {
+	return &Session{
+		Version:   SessionFormatVersion,
+		ID:        fmt.Sprintf("%s-%d", ev.VIN, ev.TriggeredAt.UnixNano()),
+		VIN:       ev.VIN,
+		StartTime: ev.Start.UnixNano(),
+		EndTime:   ev.End.UnixNano(),
+		Timezone:  "UTC",
+		Tags:      append([]string{"event"}, ev.Reasons...),
+		Frames:    ev.Frames,
+	}
+}
+
+// isEvent sniffs an event capture file: a JSON document carrying the
+// trigger timestamp no session file has.
+func isEvent(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{' && bytes.Contains(data, []byte(`"triggered_at"`))
+}
@@ -0,0 +1,188 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A power cut mid-recording used to cost the whole session: frames
+// lived in memory until Save, and a partial file was unreadable. The
+// journal writes every frame to disk as it arrives — one JSON line per
+// frame under a metadata header — and fsyncs at checkpoints, so a crash
+// loses at most the frames since the last checkpoint. On a clean Close
+// the journal is finalized into a normal session file and removed;
+// RecoverJournals salvages whatever a crash left behind on restart.
+
+const (
+	// journalSuffix marks an in-progress recording next to where its
+	// finished session file will land.
+	journalSuffix = ".journal"
+	// checkpointFrames is how many appended frames may accumulate
+	// between fsyncs. At iLoad bus rates this bounds loss to well under
+	// a second while keeping the SD card out of the hot path.
+	checkpointFrames = 256
+)
+
+// Journal streams a session to disk while it is being recorded. Frames
+// are kept in the Session as usual; the journal is the crash copy.
+type Journal struct {
+	session  *Session
+	path     string // final session file path
+	f        *os.File
+	w        *bufio.Writer
+	unsynced int
+}
+
+// CreateJournal opens a journal for s next to the eventual session file
+// at path. The metadata header is synced to disk before returning, so
+// even an immediate crash leaves a recoverable file.
+func CreateJournal(s *Session, path string) (*Journal, error) {
+	f, err := os.OpenFile(path+journalSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{session: s, path: path, f: f, w: bufio.NewWriter(f)}
+	header := *s
+	header.Frames = nil
+	if err := j.writeLine(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := j.sync(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// AddFrame records one frame in the session and appends it to the
+// journal, checkpointing every checkpointFrames frames.
+func (j *Journal) AddFrame(f CANFrame) error {
+	j.session.AddFrame(f)
+	if err := j.writeLine(f); err != nil {
+		return err
+	}
+	j.unsynced++
+	if j.unsynced >= checkpointFrames {
+		return j.sync()
+	}
+	return nil
+}
+
+// Checkpoint forces an fsync outside the frame-count cadence, e.g. on a
+// trigger event or before the daemon drops to low-power idle.
+func (j *Journal) Checkpoint() error { return j.sync() }
+
+// Close finalizes the recording: the session is written to its real
+// path in enc and the journal removed. The session file is written via
+// a rename so a crash mid-finalize leaves the journal intact for
+// recovery rather than a half-written file.
+func (j *Journal) Close(enc Encoding) error {
+	if err := j.sync(); err != nil {
+		j.f.Close()
+		return err
+	}
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+	tmp := j.path + ".tmp"
+	if err := j.session.SaveEncoded(tmp, enc); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+	return os.Remove(j.path + journalSuffix)
+}
+
+func (j *Journal) writeLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	return j.w.WriteByte('\n')
+}
+
+func (j *Journal) sync() error {
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	j.unsynced = 0
+	return j.f.Sync()
+}
+
+// RecoverJournals scans dir for journals left behind by a crash,
+// rebuilds each session from its intact lines — a torn final line is
+// discarded — and finalizes it where the session file would have gone,
+// tagged "recovered". Returns the paths of the recovered session files.
+func RecoverJournals(dir string, enc Encoding) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+journalSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var recovered []string
+	for _, jpath := range matches {
+		s, err := readJournal(jpath)
+		if err != nil {
+			return recovered, fmt.Errorf("capture: recover %s: %w", jpath, err)
+		}
+		s.Tags = append(s.Tags, "recovered")
+		path := strings.TrimSuffix(jpath, journalSuffix)
+		if err := s.SaveEncoded(path, enc); err != nil {
+			return recovered, err
+		}
+		if err := os.Remove(jpath); err != nil {
+			return recovered, err
+		}
+		recovered = append(recovered, path)
+	}
+	return recovered, nil
+}
+
+// readJournal parses the header and every complete frame line. Decode
+// errors after the header end the frame stream — the usual crash
+// signature is a truncated last line — rather than failing recovery.
+func readJournal(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readSessionLines(f)
+}
+
+// readSessionLines parses the header-plus-frame-lines layout shared by
+// journals and stream segments, tolerating a torn final line.
+func readSessionLines(r io.Reader) (*Session, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	if !sc.Scan() {
+		return nil, fmt.Errorf("missing header: %w", sc.Err())
+	}
+	var s Session
+	if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+		return nil, fmt.Errorf("bad header: %w", err)
+	}
+	for sc.Scan() {
+		var fr CANFrame
+		if err := json.Unmarshal(sc.Bytes(), &fr); err != nil {
+			break
+		}
+		s.AddFrame(fr)
+	}
+	if err := sc.Err(); err != nil && err != bufio.ErrTooLong {
+		return nil, err
+	}
+	if err := migrateSession(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func journalFrames(t *testing.T, j *Journal, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		f := CANFrame{ID: 0x316, Length: 8, Timestamp: int64(i + 1)}
+		if err := j.AddFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestJournalCloseFinalizesSession(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trip.json")
+	j, err := CreateJournal(NewSession("TESTVIN"), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journalFrames(t, j, 10)
+	if err := j.Close(EncodingJSON); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Frames) != 10 || s.VIN != "TESTVIN" {
+		t.Errorf("finalized session has %d frames, VIN %q", len(s.Frames), s.VIN)
+	}
+	if _, err := os.Stat(path + journalSuffix); !os.IsNotExist(err) {
+		t.Error("journal not removed after clean close")
+	}
+}
+
+func TestRecoverJournalsSalvagesCrash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trip.json")
+	j, err := CreateJournal(NewSession("TESTVIN"), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journalFrames(t, j, 5)
+	if err := j.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate the power cut: truncate mid-line and never Close.
+	data, err := os.ReadFile(path + journalSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+journalSuffix, data[:len(data)-7], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := RecoverJournals(dir, EncodingJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 1 || recovered[0] != path {
+		t.Fatalf("recovered = %v, want [%s]", recovered, path)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The torn last line is discarded; everything synced before it survives.
+	if len(s.Frames) != 4 {
+		t.Errorf("salvaged %d frames, want 4", len(s.Frames))
+	}
+	if len(s.Tags) == 0 || s.Tags[len(s.Tags)-1] != "recovered" {
+		t.Errorf("session tags = %v, want trailing \"recovered\"", s.Tags)
+	}
+	if _, err := os.Stat(path + journalSuffix); !os.IsNotExist(err) {
+		t.Error("journal not removed after recovery")
+	}
+}
+
+func TestRecoverJournalsIgnoresCleanDir(t *testing.T) {
+	recovered, err := RecoverJournals(t.TempDir(), EncodingJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 0 {
+		t.Errorf("recovered %v from an empty directory", recovered)
+	}
+}
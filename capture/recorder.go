@@ -0,0 +1,200 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/clock"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// harshBrakeDecel is the deceleration, in m/s², that triggers an event
+// capture on its own. Matches the rollup job's harsh-event threshold so
+// every harsh event in the weekly numbers has a capture behind it.
+const harshBrakeDecel = 3.0
+
+// defaultPostWindow is how long recording continues after a trigger.
+const defaultPostWindow = 30 * time.Second
+
+// Event is one persisted dashcam-style capture: the pre-trigger buffer
+// plus the post-trigger tail. Frames use the same CANFrame schema as
+// session files, so event captures feed the analyzer directly.
+type Event struct {
+	// Version is the event format version; see EventFormatVersion.
+	// Zero means a v1 file with pipeline-native frames.
+	Version     int                       `json:"version,omitempty"`
+	VIN         string                    `json:"vin"`
+	Reasons     []string                  `json:"reasons"`
+	TriggeredAt time.Time                 `json:"triggered_at"`
+	Start       time.Time                 `json:"start"`
+	End         time.Time                 `json:"end"`
+	Telemetry   []datastore.TelemetryData `json:"telemetry"`
+	Frames      []CANFrame                `json:"frames,omitempty"`
+}
+
+// Recorder feeds a Ring and cuts Events from it when triggered. Feed it
+// everything the live pipeline sees via Observe and ObserveFrame; fire
+// Trigger from watchers or the manual API. Persisted events are written
+// to dir and registered in the store's capture index.
+type Recorder struct {
+	ring  *Ring
+	dir   string
+	post  time.Duration
+	store datastore.Store
+
+	mu      sync.Mutex
+	rules   []Rule
+	pending *Event
+	timer   *time.Timer
+	prev    *datastore.TelemetryData // for harsh-brake and rule evaluation
+}
+
+// NewRecorder builds a recorder persisting events to dir. Zero post uses
+// the default 30 seconds.
+func NewRecorder(ring *Ring, dir string, post time.Duration, store datastore.Store) *Recorder {
+	if post <= 0 {
+		post = defaultPostWindow
+	}
+	return &Recorder{ring: ring, dir: dir, post: post, store: store}
+}
+
+// Observe feeds one telemetry sample through the ring, any pending
+// event, and the built-in harsh-brake trigger.
+func (r *Recorder) Observe(t datastore.TelemetryData) {
+	r.ring.AddTelemetry(t)
+	r.mu.Lock()
+	if r.pending != nil {
+		r.pending.Telemetry = append(r.pending.Telemetry, t)
+	}
+	prev := r.prev
+	r.prev = &t
+	r.mu.Unlock()
+
+	if prev != nil && t.VIN == prev.VIN {
+		dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt > 0 && dt < 10 {
+			decel := (prev.Speed - t.Speed) / 3.6 / dt
+			if decel >= harshBrakeDecel {
+				r.Trigger(t.VIN, "harsh_brake")
+			}
+		}
+	} else {
+		prev = nil // don't evaluate rules across a VIN change
+	}
+	r.evalRules(prev, &t)
+}
+
+// ObserveFrame feeds one bus frame through the ring and any pending event.
+func (r *Recorder) ObserveFrame(f CANFrame) {
+	r.ring.AddFrame(f)
+	r.mu.Lock()
+	if r.pending != nil {
+		r.pending.Frames = append(r.pending.Frames, f)
+	}
+	r.mu.Unlock()
+}
+
+// Trigger starts an event capture: the ring's contents are snapshotted
+// and recording continues for the post window. Triggering while an event
+// is already open extends it and records the extra reason.
+func (r *Recorder) Trigger(vin, reason string) {
+	now := clock.Now().UTC()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending != nil {
+		for _, have := range r.pending.Reasons {
+			if have == reason {
+				r.timer.Reset(r.post)
+				return
+			}
+		}
+		r.pending.Reasons = append(r.pending.Reasons, reason)
+		r.timer.Reset(r.post)
+		return
+	}
+	frames, samples := r.ring.Snapshot()
+	start := now
+	if len(samples) > 0 && samples[0].Timestamp.Before(start) {
+		start = samples[0].Timestamp
+	}
+	if len(frames) > 0 && time.Unix(0, frames[0].Timestamp).Before(start) {
+		start = time.Unix(0, frames[0].Timestamp).UTC()
+	}
+	r.pending = &Event{
+		Version:     EventFormatVersion,
+		VIN:         vin,
+		Reasons:     []string{reason},
+		TriggeredAt: now,
+		Start:       start,
+		Telemetry:   samples,
+		Frames:      frames,
+	}
+	r.timer = time.AfterFunc(r.post, r.flush)
+}
+
+// Flush persists any open event immediately instead of waiting out its
+// post window. Called on shutdown and when the engine is declared off,
+// so a capture in flight at key-off isn't lost.
+func (r *Recorder) Flush() {
+	r.mu.Lock()
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.mu.Unlock()
+	r.flush()
+}
+
+// flush persists the pending event once its post window has elapsed.
+func (r *Recorder) flush() {
+	r.mu.Lock()
+	ev := r.pending
+	r.pending = nil
+	r.timer = nil
+	r.mu.Unlock()
+	if ev == nil {
+		return
+	}
+	ev.End = clock.Now().UTC()
+	if err := r.persist(ev); err != nil {
+		log.Printf("capture: persist event: %v", err)
+	}
+}
+
+func (r *Recorder) persist(ev *Event) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("event-%s-%s.json",
+		ev.TriggeredAt.Format("20060102-150405"), strings.Join(ev.Reasons, "+"))
+	path := filepath.Join(r.dir, name)
+	data, err := json.MarshalIndent(ev, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	if r.store != nil {
+		rec := &datastore.CaptureRecord{
+			VIN:      ev.VIN,
+			Path:     path,
+			Start:    ev.Start,
+			End:      ev.End,
+			Frames:   len(ev.Frames),
+			Encoding: "json",
+			Tags:     append([]string{"event"}, ev.Reasons...),
+		}
+		if err := r.store.RegisterCapture(rec); err != nil {
+			return fmt.Errorf("register capture: %w", err)
+		}
+	}
+	log.Printf("capture: event saved to %s (%d samples, %d frames)",
+		path, len(ev.Telemetry), len(ev.Frames))
+	return nil
+}
@@ -0,0 +1,182 @@
+package capture
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestRingEvictsOutsideWindow(t *testing.T) {
+	r := NewRing(10 * time.Second)
+	base := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		r.AddTelemetry(datastore.TelemetryData{Timestamp: base.Add(time.Duration(i) * time.Second)})
+	}
+	_, samples := r.Snapshot()
+	if len(samples) != 11 {
+		t.Fatalf("got %d samples in a 10s window, want 11", len(samples))
+	}
+	if got := samples[0].Timestamp; got.Before(base.Add(19 * time.Second)) {
+		t.Errorf("oldest retained sample %v predates the window", got)
+	}
+}
+
+func TestRecorderCapturesPreAndPost(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(NewRing(time.Minute), dir, 50*time.Millisecond, nil)
+
+	base := time.Now().UTC().Add(-10 * time.Second)
+	for i := 0; i < 10; i++ {
+		rec.Observe(datastore.TelemetryData{
+			VIN: "TESTVIN", Timestamp: base.Add(time.Duration(i) * time.Second), Speed: 60,
+		})
+	}
+	rec.Trigger("TESTVIN", "manual")
+	// Post-trigger samples arrive while the event is open.
+	rec.Observe(datastore.TelemetryData{VIN: "TESTVIN", Timestamp: time.Now().UTC(), Speed: 55})
+	time.Sleep(150 * time.Millisecond)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("got %d event files (err %v), want 1", len(entries), err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev Event
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(ev.Telemetry) != 11 {
+		t.Errorf("event holds %d samples, want 10 pre + 1 post", len(ev.Telemetry))
+	}
+	if len(ev.Reasons) != 1 || ev.Reasons[0] != "manual" {
+		t.Errorf("reasons = %v, want [manual]", ev.Reasons)
+	}
+}
+
+func TestRecorderHarshBrakeTrigger(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(NewRing(time.Minute), dir, 50*time.Millisecond, nil)
+
+	now := time.Now().UTC()
+	// 80 -> 40 km/h in one second is ~11 m/s² — well past the threshold.
+	rec.Observe(datastore.TelemetryData{VIN: "TESTVIN", Timestamp: now, Speed: 80})
+	rec.Observe(datastore.TelemetryData{VIN: "TESTVIN", Timestamp: now.Add(time.Second), Speed: 40})
+	time.Sleep(150 * time.Millisecond)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("got %d event files (err %v), want 1", len(entries), err)
+	}
+	var ev Event
+	data, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(ev.Reasons) != 1 || ev.Reasons[0] != "harsh_brake" {
+		t.Errorf("reasons = %v, want [harsh_brake]", ev.Reasons)
+	}
+}
+
+func TestCompileRuleRejectsBadConditions(t *testing.T) {
+	for _, cond := range []string{"", "coolant_temp >", "coolant_temp ~ 110", "bogus > 1", "coolant_temp > hot"} {
+		if _, err := CompileRule("r", cond); err == nil {
+			t.Errorf("CompileRule(%q) accepted a bad condition", cond)
+		}
+	}
+}
+
+func TestConfiguredRuleIsEdgeTriggered(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(NewRing(time.Minute), dir, 50*time.Millisecond, nil)
+	rule, err := CompileRule("overheat", "coolant_temp > 110")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.SetRules([]Rule{rule})
+
+	now := time.Now().UTC()
+	// Condition holds for several samples: one event, not one per sample.
+	for i := 0; i < 5; i++ {
+		rec.Observe(datastore.TelemetryData{
+			VIN: "TESTVIN", Timestamp: now.Add(time.Duration(i) * time.Second), CoolantTemp: 115,
+		})
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("got %d event files (err %v), want 1", len(entries), err)
+	}
+	var ev Event
+	data, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(ev.Reasons) != 1 || ev.Reasons[0] != "overheat" {
+		t.Errorf("reasons = %v, want [overheat]", ev.Reasons)
+	}
+}
+
+func TestLoadEventMigratesV1Frames(t *testing.T) {
+	// A v1 event file: no version field, frames in the old pipeline
+	// layout with RFC 3339 times and base64 payloads.
+	const v1 = `{
+	  "vin": "TESTVIN",
+	  "reasons": ["harsh_brake"],
+	  "triggered_at": "2025-06-01T10:00:05Z",
+	  "start": "2025-06-01T10:00:00Z",
+	  "end": "2025-06-01T10:00:35Z",
+	  "telemetry": [],
+	  "frames": [
+	    {"time": "2025-06-01T10:00:01Z", "bus": "can0", "id": 790, "data": "C7gB"}
+	  ]
+	}`
+	path := filepath.Join(t.TempDir(), "event-old.json")
+	if err := os.WriteFile(path, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ev, err := LoadEvent(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Version != EventFormatVersion || len(ev.Frames) != 1 {
+		t.Fatalf("event = v%d with %d frames", ev.Version, len(ev.Frames))
+	}
+	f := ev.Frames[0]
+	if f.ID != 790 || f.Length != 3 || f.Bus != "can0" {
+		t.Errorf("migrated frame = %+v", f)
+	}
+	if f.Timestamp != time.Date(2025, 6, 1, 10, 0, 1, 0, time.UTC).UnixNano() {
+		t.Errorf("migrated timestamp = %d", f.Timestamp)
+	}
+}
+
+func TestLoadSessionReadsEventFiles(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(NewRing(time.Minute), dir, 50*time.Millisecond, nil)
+	rec.ObserveFrame(CANFrame{ID: 0x316, Length: 8, Timestamp: time.Now().UnixNano()})
+	rec.Trigger("TESTVIN", "manual")
+	rec.Flush()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("got %d event files (err %v), want 1", len(entries), err)
+	}
+	s, err := LoadSession(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.VIN != "TESTVIN" || len(s.Frames) != 1 || s.Frames[0].ID != 0x316 {
+		t.Errorf("session = %q with %d frames", s.VIN, len(s.Frames))
+	}
+	if len(s.Tags) != 2 || s.Tags[0] != "event" || s.Tags[1] != "manual" {
+		t.Errorf("tags = %v", s.Tags)
+	}
+}
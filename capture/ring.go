@@ -0,0 +1,74 @@
+package capture
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Ring keeps the most recent window of frames and telemetry in memory —
+// the rolling pre-trigger buffer behind event captures, like a dashcam
+// that is always recording but only keeps the crash. It is safe for
+// concurrent use.
+type Ring struct {
+	mu      sync.Mutex
+	window  time.Duration
+	frames  []CANFrame
+	samples []datastore.TelemetryData
+}
+
+// defaultRingWindow bounds memory at roughly 30s × 1kHz frames.
+const defaultRingWindow = 30 * time.Second
+
+// NewRing builds a ring holding the last window of traffic; zero uses
+// the default 30 seconds.
+func NewRing(window time.Duration) *Ring {
+	if window <= 0 {
+		window = defaultRingWindow
+	}
+	return &Ring{window: window}
+}
+
+// AddFrame records a bus frame, evicting anything older than the window.
+func (r *Ring) AddFrame(f CANFrame) {
+	r.mu.Lock()
+	r.frames = append(r.frames, f)
+	r.prune(time.Unix(0, f.Timestamp))
+	r.mu.Unlock()
+}
+
+// AddTelemetry records a telemetry sample, evicting anything older than
+// the window.
+func (r *Ring) AddTelemetry(t datastore.TelemetryData) {
+	r.mu.Lock()
+	r.samples = append(r.samples, t)
+	r.prune(t.Timestamp)
+	r.mu.Unlock()
+}
+
+// prune drops entries older than now-window. Callers hold r.mu.
+func (r *Ring) prune(now time.Time) {
+	cutoff := now.Add(-r.window).UnixNano()
+	i := 0
+	for i < len(r.frames) && r.frames[i].Timestamp < cutoff {
+		i++
+	}
+	r.frames = r.frames[i:]
+	i = 0
+	for i < len(r.samples) && r.samples[i].Timestamp.UnixNano() < cutoff {
+		i++
+	}
+	r.samples = r.samples[i:]
+}
+
+// Snapshot copies the current buffer contents, oldest first.
+func (r *Ring) Snapshot() ([]CANFrame, []datastore.TelemetryData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	frames := make([]CANFrame, len(r.frames))
+	copy(frames, r.frames)
+	samples := make([]datastore.TelemetryData, len(r.samples))
+	copy(samples, r.samples)
+	return frames, samples
+}
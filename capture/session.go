@@ -0,0 +1,195 @@
+// Package capture records raw CAN traffic into session files that the
+// analyzer and replay tooling consume, and implements the live
+// recording pipeline behind them: a rolling pre-trigger buffer of
+// recent traffic and telemetry, cut into an on-disk event capture when
+// something interesting happens.
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// ErrFlag marks a frame as a SocketCAN error frame rather than bus
+// traffic; the remaining ID bits carry the error class.
+const ErrFlag uint32 = 0x20000000
+
+// EFFFlag marks a frame as using the 29-bit extended identifier format
+// (SocketCAN CAN_EFF_FLAG); without it the ID is the classic 11-bit
+// kind.
+const EFFFlag uint32 = 0x80000000
+
+// CANFrame is one CAN frame as captured off the bus — classic or FD.
+// Classic frames keep their payload in Data; FD frames carry the full
+// payload (up to 64 bytes) in FDData, with the first 8 bytes mirrored
+// into Data so pre-FD tooling still sees the leading bytes. Use
+// Payload to read the data without caring which flavour it is.
+type CANFrame struct {
+	ID        uint32  `json:"id"`
+	Length    uint8   `json:"length"`
+	Data      [8]byte `json:"data"`
+	Timestamp int64   `json:"timestamp"` // unix nanoseconds, capture clock
+	// Bus names the interface the frame was seen on; empty in
+	// single-bus captures.
+	Bus string `json:"bus,omitempty"`
+	// FD marks a CAN-FD frame. BRS is the bit-rate-switch flag (data
+	// phase ran at the faster data bitrate); ESI is the transmitter's
+	// error-state indicator.
+	FD  bool `json:"fd,omitempty"`
+	BRS bool `json:"brs,omitempty"`
+	ESI bool `json:"esi,omitempty"`
+	// FDData is the full payload of an FD frame; empty on classic
+	// frames.
+	FDData []byte `json:"fd_data,omitempty"`
+}
+
+// IsError reports whether the frame is an error frame. Error frames are
+// recorded in sessions — they matter when diagnosing wiring faults — but
+// are excluded from signal analysis.
+func (f CANFrame) IsError() bool { return f.ID&ErrFlag != 0 }
+
+// IsExtended reports whether the frame uses a 29-bit extended
+// identifier.
+func (f CANFrame) IsExtended() bool { return f.ID&EFFFlag != 0 }
+
+// Arbitration returns the identifier with the flag bits stripped — the
+// number that actually won arbitration on the wire.
+func (f CANFrame) Arbitration() uint32 { return f.ID &^ (EFFFlag | ErrFlag) }
+
+// Payload returns the frame's data bytes regardless of flavour.
+func (f CANFrame) Payload() []byte {
+	if f.FD {
+		return f.FDData
+	}
+	n := int(f.Length)
+	if n > len(f.Data) {
+		n = len(f.Data)
+	}
+	return f.Data[:n]
+}
+
+// SetPayload stores data into the frame, marking it FD when it needs
+// more than the classic 8 bytes. Payloads over 64 bytes are truncated —
+// nothing legal on a CAN bus is longer.
+func (f *CANFrame) SetPayload(data []byte) {
+	if len(data) > 64 {
+		data = data[:64]
+	}
+	f.Length = uint8(len(data))
+	copy(f.Data[:], data)
+	if len(data) > len(f.Data) || f.FD {
+		f.FD = true
+		f.FDData = append([]byte(nil), data...)
+	} else {
+		f.FDData = nil
+	}
+}
+
+// Session is a recorded stretch of bus traffic for one vehicle.
+//
+// Times are stored as UTC unix nanoseconds; Timezone and UTCOffset record
+// the device's zone when recording started so trips render as the local
+// times the driver actually experienced, with explicit offsets.
+type Session struct {
+	// Version is the session format version; see SessionFormatVersion.
+	// Zero means an unversioned v1 file.
+	Version   int    `json:"version,omitempty"`
+	ID        string `json:"id"`
+	VIN       string `json:"vin"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Timezone  string `json:"timezone"`   // IANA name, e.g. "Australia/Sydney"
+	UTCOffset int    `json:"utc_offset"` // seconds east of UTC at StartTime
+	// Tags are free-form labels ("turbo-issue", "post-service") carried
+	// with the file and mirrored into the datastore's capture registry.
+	Tags   []string   `json:"tags,omitempty"`
+	Frames []CANFrame `json:"frames"`
+	// GPS is the route the recording covered, when a receiver was
+	// attached: one fix per annotated sample. Frame-only encodings
+	// (candump, ASC) drop it on export.
+	GPS []GPSPoint `json:"gps,omitempty"`
+}
+
+// GPSPoint is one positional fix along a recording, timestamped on the
+// capture clock like frames.
+type GPSPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timestamp int64   `json:"timestamp"` // unix nanoseconds
+}
+
+// NewSession starts a session for the given VIN, stamping the device's
+// current time zone into the metadata. Times come from the corrected
+// capture clock so session bounds line up with frame timestamps.
+func NewSession(vin string) *Session {
+	now := clock.Now()
+	_, offset := now.Zone()
+	name := now.Location().String()
+	return &Session{
+		Version:   SessionFormatVersion,
+		ID:        fmt.Sprintf("%s-%d", vin, now.UnixNano()),
+		VIN:       vin,
+		StartTime: now.UnixNano(),
+		Timezone:  name,
+		UTCOffset: offset,
+	}
+}
+
+// AddFrame appends a frame to the session.
+func (s *Session) AddFrame(f CANFrame) {
+	s.Frames = append(s.Frames, f)
+	if f.Timestamp > s.EndTime {
+		s.EndTime = f.Timestamp
+	}
+}
+
+// Location returns the recording device's time zone, falling back to the
+// fixed offset stored in the session when the IANA name is unknown here.
+func (s *Session) Location() *time.Location {
+	if s.Timezone != "" {
+		if loc, err := time.LoadLocation(s.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone("device", s.UTCOffset)
+}
+
+// LocalStart returns the session start in the device's zone.
+func (s *Session) LocalStart() time.Time {
+	return time.Unix(0, s.StartTime).In(s.Location())
+}
+
+// LocalEnd returns the session end in the device's zone.
+func (s *Session) LocalEnd() time.Time {
+	return time.Unix(0, s.EndTime).In(s.Location())
+}
+
+// Save writes the session as JSON to path.
+func (s *Session) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadSession reads a session file written by Save or SaveEncoded,
+// detecting JSON or CBOR automatically.
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := decode(data, &s); err != nil {
+		return nil, fmt.Errorf("capture: parse %s: %w", path, err)
+	}
+	if err := migrateSession(&s); err != nil {
+		return nil, fmt.Errorf("capture: %s: %w", path, err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetPayloadClassicAndFD(t *testing.T) {
+	var f CANFrame
+	f.SetPayload([]byte{0x01, 0x02, 0x03})
+	if f.FD || f.Length != 3 || f.FDData != nil {
+		t.Fatalf("classic payload mis-stored: %+v", f)
+	}
+	if !bytes.Equal(f.Payload(), []byte{0x01, 0x02, 0x03}) {
+		t.Fatalf("payload = % X", f.Payload())
+	}
+
+	long := make([]byte, 12)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	f.SetPayload(long)
+	if !f.FD || f.Length != 12 {
+		t.Fatalf("FD payload mis-stored: %+v", f)
+	}
+	if !bytes.Equal(f.Payload(), long) {
+		t.Fatalf("FD payload = % X", f.Payload())
+	}
+	// The first 8 bytes mirror into Data for pre-FD readers.
+	if !bytes.Equal(f.Data[:], long[:8]) {
+		t.Fatalf("classic mirror = % X", f.Data)
+	}
+}
+
+func TestExtendedIDFlags(t *testing.T) {
+	f := CANFrame{ID: 0x18DAF110 | EFFFlag}
+	if !f.IsExtended() || f.IsError() {
+		t.Fatalf("flags misread: %+v", f)
+	}
+	if f.Arbitration() != 0x18DAF110 {
+		t.Fatalf("arbitration = %#x", f.Arbitration())
+	}
+	std := CANFrame{ID: 0x7E8}
+	if std.IsExtended() || std.Arbitration() != 0x7E8 {
+		t.Fatalf("standard ID mishandled: %+v", std)
+	}
+}
+
+func TestSetPayloadTruncatesOversize(t *testing.T) {
+	var f CANFrame
+	f.SetPayload(make([]byte, 100))
+	if f.Length != 64 || len(f.Payload()) != 64 {
+		t.Fatalf("oversize payload kept %d bytes, want 64", f.Length)
+	}
+}
@@ -0,0 +1,260 @@
+package capture
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Session.Save holds every frame in memory and rewrites the whole file,
+// which is fine for event clips and hopeless for an all-day drive. The
+// stream writer appends frames to disk as they arrive — the same
+// header-plus-frame-lines layout the journal uses — optionally gzipped,
+// and rotates to a fresh segment file when the current one grows past a
+// size or age limit. Memory stays flat no matter how long the drive is;
+// each segment loads back as an ordinary Session.
+
+// StreamOptions bound a stream segment. Zero values disable the
+// corresponding limit; with both zero the stream is a single unbounded
+// segment.
+type StreamOptions struct {
+	// Gzip compresses segments on the fly; MaxBytes then applies to the
+	// compressed size, which is the one the SD card cares about.
+	Gzip bool
+	// MaxBytes rotates once a segment file exceeds this size.
+	MaxBytes int64
+	// MaxAge rotates once a segment has been open this long.
+	MaxAge time.Duration
+}
+
+// streamSuffix marks a stream segment file; gzipped segments append
+// ".gz" after it.
+const streamSuffix = ".seg"
+
+// StreamWriter records a session to disk incrementally. Unlike Journal
+// it does not keep frames in the Session — the files are the recording.
+type StreamWriter struct {
+	meta *Session // header template; Frames stays nil
+	dir  string
+	opts StreamOptions
+
+	seq      int
+	paths    []string
+	f        *os.File
+	gz       *gzip.Writer
+	w        *bufio.Writer
+	written  int64
+	openedAt time.Time
+}
+
+// NewStreamWriter starts streaming s to segment files in dir. The
+// session carries only metadata; its frame slice is never populated.
+func NewStreamWriter(s *Session, dir string, opts StreamOptions) (*StreamWriter, error) {
+	w := &StreamWriter{meta: s, dir: dir, opts: opts}
+	if err := w.openSegment(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddFrame appends one frame to the current segment, rotating first if
+// the segment has hit a limit.
+func (w *StreamWriter) AddFrame(f CANFrame) error {
+	if w.limitHit() {
+		if err := w.closeSegment(); err != nil {
+			return err
+		}
+		if err := w.openSegment(); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	if err := w.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	// Track the uncompressed line size for gzip streams between flushes;
+	// limitHit corrects against the file size, this just keeps a cheap
+	// running figure so we don't stat on every frame.
+	w.written += int64(len(data)) + 1
+	return nil
+}
+
+// Paths returns the segment files written so far, in order.
+func (w *StreamWriter) Paths() []string { return append([]string(nil), w.paths...) }
+
+// Close flushes and closes the current segment.
+func (w *StreamWriter) Close() error { return w.closeSegment() }
+
+// limitHit reports whether the open segment has reached a rotation
+// limit. For gzipped streams the byte check flushes and consults the
+// real file size, since compressed output lags the frames written.
+func (w *StreamWriter) limitHit() bool {
+	if w.opts.MaxAge > 0 && time.Since(w.openedAt) >= w.opts.MaxAge {
+		return true
+	}
+	if w.opts.MaxBytes <= 0 || w.written < w.opts.MaxBytes {
+		return false
+	}
+	if !w.opts.Gzip {
+		return true
+	}
+	if err := w.flush(); err != nil {
+		return true // rotation will surface the error
+	}
+	fi, err := w.f.Stat()
+	if err != nil {
+		return true
+	}
+	if fi.Size() >= w.opts.MaxBytes {
+		return true
+	}
+	w.written = fi.Size() // resync the running figure
+	return false
+}
+
+// openSegment creates the next segment file and writes its header: the
+// session metadata with the segment's sequence number, so each file
+// stands alone.
+func (w *StreamWriter) openSegment() error {
+	name := fmt.Sprintf("%s-%03d%s", w.meta.ID, w.seq, streamSuffix)
+	if w.opts.Gzip {
+		name += ".gz"
+	}
+	path := filepath.Join(w.dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	w.f = f
+	var out io.Writer = f
+	if w.opts.Gzip {
+		w.gz = gzip.NewWriter(f)
+		out = w.gz
+	}
+	w.w = bufio.NewWriter(out)
+	w.written = 0
+	w.openedAt = time.Now()
+	header := *w.meta
+	header.Frames = nil
+	header.ID = fmt.Sprintf("%s-%03d", w.meta.ID, w.seq)
+	if w.seq > 0 {
+		header.StartTime = w.openedAt.UnixNano()
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := w.w.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	w.paths = append(w.paths, path)
+	w.seq++
+	return nil
+}
+
+func (w *StreamWriter) flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	if w.gz != nil {
+		return w.gz.Flush()
+	}
+	return nil
+}
+
+// closeSegment finishes the open segment file, stamping nothing — the
+// frame lines already carry their timestamps, and LoadStreamSegment
+// rebuilds EndTime the same way journal recovery does.
+func (w *StreamWriter) closeSegment() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.w.Flush()
+	if w.gz != nil {
+		if cerr := w.gz.Close(); err == nil {
+			err = cerr
+		}
+		w.gz = nil
+	}
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	w.f = nil
+	return err
+}
+
+// LoadStreamSegment reads one segment file back as a Session,
+// decompressing when the gzip magic is present. Torn final lines are
+// tolerated the same way journal recovery tolerates them.
+func LoadStreamSegment(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("capture: %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	s, err := readSessionLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("capture: %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// LoadStream loads every segment of a streamed session in sequence
+// order and merges them into one Session carrying the first segment's
+// metadata.
+func LoadStream(dir, sessionID string) (*Session, error) {
+	pattern := filepath.Join(dir, sessionID+"-*"+streamSuffix)
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	gzMatches, err := filepath.Glob(pattern + ".gz")
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, gzMatches...)
+	sort.Strings(matches) // zero-padded sequence numbers sort correctly
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("capture: no stream segments for %s in %s", sessionID, dir)
+	}
+	var merged *Session
+	for _, path := range matches {
+		seg, err := LoadStreamSegment(path)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = seg
+			merged.ID = sessionID
+			continue
+		}
+		for _, f := range seg.Frames {
+			merged.AddFrame(f)
+		}
+	}
+	return merged, nil
+}
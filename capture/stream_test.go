@@ -0,0 +1,90 @@
+package capture
+
+import (
+	"testing"
+	"time"
+)
+
+func streamFrames(t *testing.T, w *StreamWriter, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		f := CANFrame{ID: 0x316, Length: 8, Timestamp: int64(i + 1)}
+		if err := w.AddFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStreamWriterRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSession("TESTVIN")
+	w, err := NewStreamWriter(s, dir, StreamOptions{MaxBytes: 512})
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamFrames(t, w, 50)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	paths := w.Paths()
+	if len(paths) < 2 {
+		t.Fatalf("wrote %d segment(s), want rotation past 512 bytes", len(paths))
+	}
+	merged, err := LoadStream(dir, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Frames) != 50 {
+		t.Errorf("merged %d frames, want 50", len(merged.Frames))
+	}
+	if merged.VIN != "TESTVIN" || merged.ID != s.ID {
+		t.Errorf("merged metadata = %q/%q", merged.VIN, merged.ID)
+	}
+	for i, f := range merged.Frames {
+		if f.Timestamp != int64(i+1) {
+			t.Fatalf("frame %d out of order: timestamp %d", i, f.Timestamp)
+		}
+	}
+}
+
+func TestStreamWriterGzip(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSession("TESTVIN")
+	w, err := NewStreamWriter(s, dir, StreamOptions{Gzip: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamFrames(t, w, 20)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	paths := w.Paths()
+	if len(paths) != 1 {
+		t.Fatalf("wrote %d segment(s), want 1", len(paths))
+	}
+	seg, err := LoadStreamSegment(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seg.Frames) != 20 {
+		t.Errorf("loaded %d frames, want 20", len(seg.Frames))
+	}
+}
+
+func TestStreamWriterRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSession("TESTVIN")
+	w, err := NewStreamWriter(s, dir, StreamOptions{MaxAge: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamFrames(t, w, 1)
+	time.Sleep(5 * time.Millisecond)
+	streamFrames(t, w, 1)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(w.Paths()); got != 2 {
+		t.Errorf("wrote %d segment(s), want 2 after age rotation", got)
+	}
+}
@@ -0,0 +1,106 @@
+package capture
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Rule is one user-defined capture trigger compiled from config. Rules
+// are edge-triggered: a capture fires when the condition becomes true,
+// not on every sample while it stays true.
+type Rule struct {
+	Name string
+	eval func(prev, cur *datastore.TelemetryData) bool
+
+	active bool
+}
+
+// CompileRule parses a trigger condition into an evaluator. Supported
+// forms:
+//
+//	dtc                  any trouble code present
+//	coolant_temp > 110   a telemetry channel against a threshold
+//	accel < -3           sample-to-sample acceleration in m/s²
+//
+// Channel names are the ones ChannelValue understands.
+func CompileRule(name, condition string) (Rule, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "dtc" {
+		return Rule{Name: name, eval: func(_, cur *datastore.TelemetryData) bool {
+			return len(cur.DTCs) > 0
+		}}, nil
+	}
+	parts := strings.Fields(condition)
+	if len(parts) != 3 {
+		return Rule{}, fmt.Errorf("capture: rule %q: condition must be \"dtc\" or \"<channel> <op> <value>\"", name)
+	}
+	channel, op := parts[0], parts[1]
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return Rule{}, fmt.Errorf("capture: rule %q: bad threshold %q", name, parts[2])
+	}
+	var cmp func(v float64) bool
+	switch op {
+	case ">":
+		cmp = func(v float64) bool { return v > threshold }
+	case ">=":
+		cmp = func(v float64) bool { return v >= threshold }
+	case "<":
+		cmp = func(v float64) bool { return v < threshold }
+	case "<=":
+		cmp = func(v float64) bool { return v <= threshold }
+	default:
+		return Rule{}, fmt.Errorf("capture: rule %q: unknown operator %q", name, op)
+	}
+	if channel == "accel" {
+		return Rule{Name: name, eval: func(prev, cur *datastore.TelemetryData) bool {
+			if prev == nil {
+				return false
+			}
+			dt := cur.Timestamp.Sub(prev.Timestamp).Seconds()
+			if dt <= 0 || dt >= 10 {
+				return false
+			}
+			return cmp((cur.Speed - prev.Speed) / 3.6 / dt)
+		}}, nil
+	}
+	// Validate the channel name up front so typos fail at config load,
+	// not silently at runtime.
+	if _, ok := datastore.ChannelValue(datastore.TelemetryData{}, channel); !ok {
+		return Rule{}, fmt.Errorf("capture: rule %q: unknown channel %q", name, channel)
+	}
+	return Rule{Name: name, eval: func(_, cur *datastore.TelemetryData) bool {
+		v, ok := datastore.ChannelValue(*cur, channel)
+		return ok && cmp(v)
+	}}, nil
+}
+
+// SetRules installs the compiled trigger rules evaluated on every
+// observed sample.
+func (r *Recorder) SetRules(rules []Rule) {
+	r.mu.Lock()
+	r.rules = rules
+	r.mu.Unlock()
+}
+
+// evalRules fires edge-triggered rules for one sample. Callers must not
+// hold r.mu.
+func (r *Recorder) evalRules(prev, cur *datastore.TelemetryData) {
+	r.mu.Lock()
+	var fired []string
+	for i := range r.rules {
+		rule := &r.rules[i]
+		now := rule.eval(prev, cur)
+		if now && !rule.active {
+			fired = append(fired, rule.Name)
+		}
+		rule.active = now
+	}
+	r.mu.Unlock()
+	for _, name := range fired {
+		r.Trigger(cur.VIN, name)
+	}
+}
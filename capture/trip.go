@@ -0,0 +1,237 @@
+package capture
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/clock"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// TripRecorder records the live frame stream continuously, one session
+// per drive. Where the event Recorder cuts short clips around triggers,
+// the trip recorder keeps everything: frames are journalled to disk as
+// they arrive, so a power cut mid-drive loses at most a checkpoint's
+// worth, and RecoverJournals salvages the rest on the next boot.
+//
+// The first frame after construction — or after Close — opens a new
+// recording, so closing at key-off cuts files at drive boundaries.
+type TripRecorder struct {
+	dir   string
+	enc   Encoding
+	store datastore.Store
+
+	mu  sync.Mutex
+	vin string
+	j   *Journal
+	// Streaming-mode state; see SetStream. meta is the header template
+	// shared by the trip's segments, frames and lastTS stand in for the
+	// frame slice a journalled session would carry.
+	stream bool
+	opts   StreamOptions
+	sw     *StreamWriter
+	meta   *Session
+	frames int
+	lastTS int64
+	// failed latches after a write error so a full SD card costs one
+	// log line per drive, not one per frame. Close resets it.
+	failed bool
+}
+
+// NewTripRecorder builds a trip recorder writing session files to dir.
+// Finished trips are registered in the store's capture index when store
+// is non-nil.
+func NewTripRecorder(dir string, enc Encoding, store datastore.Store) *TripRecorder {
+	return &TripRecorder{dir: dir, enc: enc, store: store}
+}
+
+// SetStream switches the recorder onto the streaming writer: frames go
+// to rotating segment files and never accumulate in memory, which is
+// the mode for all-day drives the journal would grow unbounded on.
+// Call it before the first frame arrives.
+func (r *TripRecorder) SetStream(opts StreamOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stream = true
+	r.opts = opts
+}
+
+// SetVIN records the vehicle identity once telemetry reveals it; a
+// recording opened before the VIN was known picks it up at finalize.
+func (r *TripRecorder) SetVIN(vin string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.vin = vin
+	if r.j != nil && r.j.session.VIN == "" {
+		r.j.session.VIN = vin
+	}
+	if r.meta != nil && r.meta.VIN == "" {
+		r.meta.VIN = vin
+	}
+}
+
+// ObserveLocation appends a GPS fix to the open journalled trip, so the
+// finished session carries the route alongside the traffic. Stream mode
+// drops fixes — its whole point is flat memory over a long drive.
+func (r *TripRecorder) ObserveLocation(lat, lon float64, ts int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.j != nil {
+		r.j.session.GPS = append(r.j.session.GPS, GPSPoint{Latitude: lat, Longitude: lon, Timestamp: ts})
+	}
+}
+
+// ObserveFrame appends one frame to the current trip, starting a new
+// recording if none is open.
+func (r *TripRecorder) ObserveFrame(f CANFrame) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failed {
+		return
+	}
+	if r.j == nil && r.sw == nil {
+		if err := r.open(); err != nil {
+			log.Printf("capture: open trip recording: %v (trip recording off until next drive)", err)
+			r.failed = true
+			return
+		}
+	}
+	var err error
+	if r.sw != nil {
+		if err = r.sw.AddFrame(f); err == nil {
+			r.frames++
+			if f.Timestamp > r.lastTS {
+				r.lastTS = f.Timestamp
+			}
+		}
+	} else {
+		err = r.j.AddFrame(f)
+	}
+	if err != nil {
+		// Leave the files where they are — journals recover at the next
+		// boot, stream segments tolerate a torn tail by design.
+		log.Printf("capture: trip recording: %v (trip recording off until next drive)", err)
+		r.j, r.sw = nil, nil
+		r.failed = true
+	}
+}
+
+// open starts a journal for a fresh session, named like event files so
+// a captures directory sorts by kind then time.
+func (r *TripRecorder) open() error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	s := NewSession(r.vin)
+	// Sub-second precision keeps back-to-back trips from colliding.
+	stamp := clock.Now().UTC().Format("20060102-150405.000000000")
+	if r.stream {
+		s.ID = "trip-" + stamp
+		sw, err := NewStreamWriter(s, r.dir, r.opts)
+		if err != nil {
+			return err
+		}
+		r.sw, r.meta = sw, s
+		r.frames, r.lastTS = 0, 0
+		return nil
+	}
+	j, err := CreateJournal(s, filepath.Join(r.dir, "trip-"+stamp+r.ext()))
+	if err != nil {
+		return err
+	}
+	r.j = j
+	return nil
+}
+
+// ext is the session file extension for the configured encoding.
+func (r *TripRecorder) ext() string {
+	switch r.enc {
+	case EncodingCBOR:
+		return ".cbor"
+	case EncodingCandump:
+		return ".log"
+	case EncodingASC:
+		return ".asc"
+	}
+	return ".json"
+}
+
+// Checkpoint fsyncs the open journal outside its frame-count cadence —
+// called before the daemon drops to low-power idle.
+func (r *TripRecorder) Checkpoint() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	switch {
+	case r.j != nil:
+		err = r.j.Checkpoint()
+	case r.sw != nil:
+		err = r.sw.flush()
+	}
+	if err != nil {
+		log.Printf("capture: trip checkpoint: %v", err)
+	}
+}
+
+// Close finalizes the open trip into its session file and registers it.
+// The next frame starts a new recording.
+func (r *TripRecorder) Close() {
+	r.mu.Lock()
+	j, sw, meta, frames, lastTS := r.j, r.sw, r.meta, r.frames, r.lastTS
+	r.j, r.sw, r.meta = nil, nil, nil
+	r.failed = false
+	r.mu.Unlock()
+	switch {
+	case sw != nil:
+		if err := sw.Close(); err != nil {
+			log.Printf("capture: finalize trip stream: %v", err)
+			return
+		}
+		end := meta.StartTime
+		if lastTS > end {
+			end = lastTS
+		}
+		// Segments are JSON lines whatever the session encoding is; the
+		// record points at the first one and LoadStream merges the rest.
+		r.register(&datastore.CaptureRecord{
+			VIN:      meta.VIN,
+			Path:     sw.Paths()[0],
+			Start:    time.Unix(0, meta.StartTime).UTC(),
+			End:      time.Unix(0, end).UTC(),
+			Frames:   frames,
+			Encoding: string(EncodingJSON),
+			Tags:     []string{"trip", "stream"},
+		})
+		log.Printf("capture: trip streamed to %d segment(s) in %s (%d frames)",
+			len(sw.Paths()), r.dir, frames)
+	case j != nil:
+		if err := j.Close(r.enc); err != nil {
+			log.Printf("capture: finalize trip: %v", err)
+			return
+		}
+		s := j.session
+		r.register(&datastore.CaptureRecord{
+			VIN:      s.VIN,
+			Path:     j.path,
+			Start:    time.Unix(0, s.StartTime).UTC(),
+			End:      time.Unix(0, s.EndTime).UTC(),
+			Frames:   len(s.Frames),
+			Encoding: string(r.enc),
+			Tags:     []string{"trip"},
+		})
+		log.Printf("capture: trip saved to %s (%d frames)", j.path, len(s.Frames))
+	}
+}
+
+// register files the finished trip in the store's capture index.
+func (r *TripRecorder) register(rec *datastore.CaptureRecord) {
+	if r.store == nil {
+		return
+	}
+	if err := r.store.RegisterCapture(rec); err != nil {
+		log.Printf("capture: register trip: %v", err)
+	}
+}
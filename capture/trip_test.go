@@ -0,0 +1,85 @@
+package capture
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTripRecorderCutsAtClose(t *testing.T) {
+	dir := t.TempDir()
+	r := NewTripRecorder(dir, EncodingJSON, nil)
+	for i := 0; i < 5; i++ {
+		r.ObserveFrame(CANFrame{ID: 0x316, Length: 8, Timestamp: int64(i + 1)})
+	}
+	r.SetVIN("TESTVIN")
+	r.Close()
+	r.ObserveFrame(CANFrame{ID: 0x329, Length: 8, Timestamp: 100})
+	r.Close()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "trip-*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("got %d trip files, want 2", len(paths))
+	}
+	total := 0
+	vin := ""
+	for _, p := range paths {
+		s, err := LoadSession(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += len(s.Frames)
+		if s.VIN != "" {
+			vin = s.VIN
+		}
+	}
+	if total != 6 {
+		t.Errorf("got %d frames across trips, want 6", total)
+	}
+	if vin != "TESTVIN" {
+		t.Errorf("VIN %q not carried into the first trip", vin)
+	}
+	// No journals should survive a clean close.
+	if left, _ := filepath.Glob(filepath.Join(dir, "*"+journalSuffix)); len(left) != 0 {
+		t.Errorf("journals left behind: %v", left)
+	}
+}
+
+func TestTripRecorderStreamsSegments(t *testing.T) {
+	dir := t.TempDir()
+	r := NewTripRecorder(dir, EncodingJSON, nil)
+	r.SetStream(StreamOptions{MaxBytes: 256})
+	for i := 0; i < 50; i++ {
+		r.ObserveFrame(CANFrame{ID: 0x316, Length: 8, Timestamp: int64(i + 1)})
+	}
+	r.Close()
+
+	segs, err := filepath.Glob(filepath.Join(dir, "trip-*"+streamSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segs) < 2 {
+		t.Fatalf("got %d segments, want rotation to produce several", len(segs))
+	}
+	// Strip "-NNN.seg" to recover the session ID, then merge the trip.
+	base := filepath.Base(segs[0])
+	id := base[:len(base)-len("-000"+streamSuffix)]
+	s, err := LoadStream(dir, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Frames) != 50 {
+		t.Errorf("merged stream has %d frames, want 50", len(s.Frames))
+	}
+}
+
+func TestTripRecorderCloseWithoutFramesIsNoop(t *testing.T) {
+	dir := t.TempDir()
+	r := NewTripRecorder(dir, EncodingJSON, nil)
+	r.Close()
+	if paths, _ := filepath.Glob(filepath.Join(dir, "*")); len(paths) != 0 {
+		t.Errorf("empty close wrote files: %v", paths)
+	}
+}
@@ -0,0 +1,151 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vector ASC trace interchange: the plain-text format CANoe and
+// CANalyzer read and write. Like candump logs it carries frames only —
+// VIN and tag metadata don't survive a round trip. Classic CAN only:
+// the FD line format is a different, much wider table and the vans this
+// project targets don't send FD, so FD sessions are refused on export.
+//
+//	date Mon Sep 1 10:00:00.000 2026
+//	base hex  timestamps absolute
+//	internal events logged
+//	   0.000000 1  7E8             Rx   d 8 03 41 0C 1F 40 00 00 00
+//	   0.001200 1  18DAF110x       Rx   d 5 04 62 F1 95 47
+
+// ascDateLayouts are the timestamp layouts seen in the wild on the
+// "date" header line; CANoe localises neither the day nor month names.
+var ascDateLayouts = []string{
+	"Mon Jan 2 15:04:05.000 2006",
+	"Mon Jan 2 15:04:05.000 pm 2006",
+	"Mon Jan 2 15:04:05 2006",
+}
+
+// parseASC decodes an ASC trace into s. Event lines that aren't classic
+// CAN frames — error frames, statistics, trigger blocks — are skipped;
+// ASC files are full of event types that have no frame equivalent.
+func parseASC(r io.Reader, s *Session) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	var start int64
+	for sc.Scan() {
+		text := strings.TrimSpace(sc.Text())
+		if rest, ok := strings.CutPrefix(text, "date "); ok {
+			for _, layout := range ascDateLayouts {
+				if t, err := time.Parse(layout, rest); err == nil {
+					start = t.UnixNano()
+					break
+				}
+			}
+			continue
+		}
+		f, ok := parseASCLine(text)
+		if !ok {
+			continue
+		}
+		f.Timestamp += start
+		if s.StartTime == 0 || f.Timestamp < s.StartTime {
+			s.StartTime = f.Timestamp
+		}
+		s.AddFrame(f)
+	}
+	return sc.Err()
+}
+
+// parseASCLine decodes one classic CAN event line:
+// "time channel id[x] Rx|Tx d dlc bytes...". The timestamp comes back
+// as nanoseconds from measurement start.
+func parseASCLine(text string) (CANFrame, bool) {
+	var f CANFrame
+	fields := strings.Fields(text)
+	if len(fields) < 6 || fields[4] != "d" {
+		return f, false
+	}
+	offset, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return f, false
+	}
+	ch, err := strconv.Atoi(fields[1])
+	if err != nil || ch < 1 {
+		return f, false
+	}
+	idStr, extended := strings.CutSuffix(fields[2], "x")
+	id, err := strconv.ParseUint(idStr, 16, 32)
+	if err != nil {
+		return f, false
+	}
+	if fields[3] != "Rx" && fields[3] != "Tx" {
+		return f, false
+	}
+	dlc, err := strconv.Atoi(fields[5])
+	if err != nil || dlc < 0 || dlc > 8 || len(fields) < 6+dlc {
+		return f, false
+	}
+	data := make([]byte, dlc)
+	for i := 0; i < dlc; i++ {
+		b, err := strconv.ParseUint(fields[6+i], 16, 8)
+		if err != nil {
+			return f, false
+		}
+		data[i] = byte(b)
+	}
+	f.ID = uint32(id)
+	if extended {
+		f.ID |= EFFFlag
+	}
+	f.SetPayload(data)
+	f.Timestamp = int64(offset * 1e9)
+	if ch > 1 {
+		f.Bus = fmt.Sprintf("can%d", ch-1)
+	}
+	return f, true
+}
+
+// appendASC renders the session as an ASC trace. Buses map to channels
+// in first-seen order starting at 1; error frames are skipped and FD
+// frames refuse the export.
+func (s *Session) appendASC(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "date %s\n", s.LocalStart().Format(ascDateLayouts[0]))
+	fmt.Fprintln(bw, "base hex  timestamps absolute")
+	fmt.Fprintln(bw, "internal events logged")
+	channels := map[string]int{}
+	for _, f := range s.Frames {
+		if f.IsError() {
+			continue
+		}
+		if f.FD {
+			return fmt.Errorf("session has CAN-FD frames; the ASC exporter writes classic CAN only")
+		}
+		ch, ok := channels[f.Bus]
+		if !ok {
+			ch = len(channels) + 1
+			channels[f.Bus] = ch
+		}
+		id := fmt.Sprintf("%X", f.Arbitration())
+		if f.IsExtended() {
+			id += "x"
+		}
+		offset := float64(f.Timestamp-s.StartTime) / 1e9
+		fmt.Fprintf(bw, "%11.6f %d  %-15s Rx   d %d", offset, ch, id, len(f.Payload()))
+		for _, b := range f.Payload() {
+			fmt.Fprintf(bw, " %02X", b)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// isASC sniffs the ASC signature: traces start with the "date" header
+// line.
+func isASC(data []byte) bool {
+	return len(data) > 5 && string(data[:5]) == "date "
+}
@@ -0,0 +1,168 @@
+package capture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const ascSample = `date Tue Sep 1 10:00:00.000 2026
+base hex  timestamps absolute
+internal events logged
+Begin Triggerblock
+   0.000000 1  7E8             Rx   d 8 03 41 0C 1F 40 00 00 00
+   0.001200 2  18DAF110x       Rx   d 5 04 62 F1 95 47
+   0.002000 1  ErrorFrame
+End TriggerBlock
+`
+
+func ascStart(t *testing.T) int64 {
+	t.Helper()
+	start, err := time.Parse(ascDateLayouts[0], "Tue Sep 1 10:00:00.000 2026")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return start.UnixNano()
+}
+
+func TestLoadASC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.asc")
+	if err := os.WriteFile(path, []byte(ascSample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Frames) != 2 {
+		t.Fatalf("loaded %d frames, want 2", len(s.Frames))
+	}
+	f := s.Frames[0]
+	if f.Arbitration() != 0x7E8 || f.Length != 8 || f.Bus != "" {
+		t.Errorf("frame 0 = %+v", f)
+	}
+	if f.Timestamp != ascStart(t) {
+		t.Errorf("frame 0 timestamp = %d", f.Timestamp)
+	}
+	if !s.Frames[1].IsExtended() || s.Frames[1].Arbitration() != 0x18DAF110 || s.Frames[1].Bus != "can1" {
+		t.Errorf("frame 1 = %+v", s.Frames[1])
+	}
+	if got := s.Frames[1].Timestamp - s.StartTime; got != 1_200_000 {
+		t.Errorf("frame 1 offset = %dns", got)
+	}
+}
+
+func TestASCRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "trace.asc")
+	if err := os.WriteFile(in, []byte(ascSample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.asc")
+	if err := s.SaveEncoded(out, EncodingASC); err != nil {
+		t.Fatal(err)
+	}
+	back, err := LoadSession(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Frames) != len(s.Frames) {
+		t.Fatalf("round trip has %d frames, want %d", len(back.Frames), len(s.Frames))
+	}
+	for i := range s.Frames {
+		if back.Frames[i].ID != s.Frames[i].ID ||
+			!bytes.Equal(back.Frames[i].Payload(), s.Frames[i].Payload()) ||
+			back.Frames[i].Timestamp != s.Frames[i].Timestamp {
+			t.Errorf("frame %d: %+v != %+v", i, back.Frames[i], s.Frames[i])
+		}
+	}
+}
+
+func TestASCExportRejectsFD(t *testing.T) {
+	s := NewSession("TESTVIN")
+	f := CANFrame{ID: 0x4A0, FD: true}
+	f.SetPayload(make([]byte, 12))
+	s.AddFrame(f)
+	if err := s.SaveEncoded(filepath.Join(t.TempDir(), "out.asc"), EncodingASC); err == nil {
+		t.Error("exported an FD session as classic-only ASC")
+	}
+}
+
+// blfTestFile builds a minimal BLF: a file header, then one
+// zlib-compressed log container holding a single classic CAN message.
+func blfTestFile(t *testing.T) []byte {
+	t.Helper()
+	msg := make([]byte, 48)
+	copy(msg, "LOBJ")
+	binary.LittleEndian.PutUint16(msg[4:], 32) // header size
+	binary.LittleEndian.PutUint16(msg[6:], 1)  // header version
+	binary.LittleEndian.PutUint32(msg[8:], 48) // object size
+	binary.LittleEndian.PutUint32(msg[12:], blfObjCANMessage)
+	binary.LittleEndian.PutUint32(msg[16:], blfFlagTimeNanos)
+	binary.LittleEndian.PutUint64(msg[24:], 5e9) // 5s into the log
+	binary.LittleEndian.PutUint16(msg[32:], 1)   // channel
+	msg[35] = 3                                  // dlc
+	binary.LittleEndian.PutUint32(msg[36:], 0x316)
+	copy(msg[40:], []byte{0x0B, 0xB8, 0x01})
+
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	if _, err := zw.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	container := make([]byte, 32+deflated.Len())
+	copy(container, "LOBJ")
+	binary.LittleEndian.PutUint16(container[4:], 16)
+	binary.LittleEndian.PutUint16(container[6:], 1)
+	binary.LittleEndian.PutUint32(container[8:], uint32(len(container)))
+	binary.LittleEndian.PutUint32(container[12:], blfObjLogContainer)
+	binary.LittleEndian.PutUint16(container[16:], blfZlibCompression)
+	binary.LittleEndian.PutUint32(container[24:], uint32(len(msg)))
+	copy(container[32:], deflated.Bytes())
+
+	file := make([]byte, 144)
+	copy(file, "LOGG")
+	binary.LittleEndian.PutUint32(file[4:], 144)
+	// Measurement start: 2026-09-01 10:00:00.
+	for i, v := range []uint16{2026, 9, 2, 1, 10, 0, 0, 0} {
+		binary.LittleEndian.PutUint16(file[40+2*i:], v)
+	}
+	return append(file, container...)
+}
+
+func TestLoadBLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.blf")
+	if err := os.WriteFile(path, blfTestFile(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Frames) != 1 {
+		t.Fatalf("loaded %d frames, want 1", len(s.Frames))
+	}
+	f := s.Frames[0]
+	if f.Arbitration() != 0x316 || f.Length != 3 || f.Bus != "" {
+		t.Errorf("frame = %+v", f)
+	}
+	if !bytes.Equal(f.Payload(), []byte{0x0B, 0xB8, 0x01}) {
+		t.Errorf("payload = % X", f.Payload())
+	}
+	want := time.Date(2026, 9, 1, 10, 0, 5, 0, time.UTC).UnixNano()
+	if f.Timestamp != want {
+		t.Errorf("timestamp = %d, want %d", f.Timestamp, want)
+	}
+}
@@ -0,0 +1,62 @@
+package capture
+
+import "fmt"
+
+// SessionFormatVersion is the format version written by this build.
+//
+// History:
+//
+//	1 — original layout, no version field, no time zone metadata
+//	2 — adds version, time zone metadata and per-frame bus names
+//	3 — adds CAN-FD frame fields (fd/brs/esi flags, fd_data payload)
+const SessionFormatVersion = 3
+
+// sessionMigrations upgrade a session in place; entry i takes a session
+// from version i+1 to i+2. Add an entry here for every future format
+// bump so old recordings keep loading.
+var sessionMigrations = []func(*Session){
+	migrateSessionV1,
+	migrateSessionV2,
+}
+
+// migrateSessionV1 upgrades the original unversioned layout: those files
+// predate time zone metadata, so stamp the only zone they could have
+// meant, and EndTime wasn't always maintained.
+func migrateSessionV1(s *Session) {
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+		s.UTCOffset = 0
+	}
+	if s.EndTime == 0 {
+		for _, f := range s.Frames {
+			if f.Timestamp > s.EndTime {
+				s.EndTime = f.Timestamp
+			}
+		}
+	}
+}
+
+// migrateSessionV2 is a no-op: v3 only adds optional CAN-FD fields,
+// and v2 files are classic CAN throughout, which the zero values
+// already say. The bump exists so builds without FD support refuse FD
+// recordings instead of silently dropping payload bytes.
+func migrateSessionV2(*Session) {}
+
+// migrateSession brings a decoded session up to the current format.
+// Files from a newer build than this one are refused rather than
+// silently misread.
+func migrateSession(s *Session) error {
+	if s.Version > SessionFormatVersion {
+		return fmt.Errorf("capture: session format v%d is newer than this build understands (v%d)",
+			s.Version, SessionFormatVersion)
+	}
+	v := s.Version
+	if v == 0 {
+		v = 1 // unversioned files are v1 by definition
+	}
+	for ; v < SessionFormatVersion; v++ {
+		sessionMigrations[v-1](s)
+	}
+	s.Version = SessionFormatVersion
+	return nil
+}
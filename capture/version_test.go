@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSessionMigratesV1(t *testing.T) {
+	// An unversioned file as the original builds wrote it: no version,
+	// no zone metadata, EndTime never maintained.
+	v1 := `{
+  "id": "KMFWB37HXTESTFIX0-1700000000000000000",
+  "vin": "KMFWB37HXTESTFIX0",
+  "start_time": 1700000000000000000,
+  "frames": [
+    {"id": 790, "length": 8, "data": [0,0,11,184,0,0,0,0], "timestamp": 1700000000000000000},
+    {"id": 790, "length": 8, "data": [0,0,11,200,0,0,0,0], "timestamp": 1700000001000000000}
+  ]
+}`
+	path := filepath.Join(t.TempDir(), "old.json")
+	if err := os.WriteFile(path, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != SessionFormatVersion {
+		t.Fatalf("version after load = %d, want %d", s.Version, SessionFormatVersion)
+	}
+	if s.Timezone != "UTC" {
+		t.Fatalf("migrated timezone = %q, want UTC", s.Timezone)
+	}
+	if s.EndTime != 1700000001000000000 {
+		t.Fatalf("end time not reconstructed: %d", s.EndTime)
+	}
+}
+
+func TestLoadSessionRefusesFutureVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "future.json")
+	data := []byte(`{"version": 99, "id": "x", "vin": "y"}`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSession(path); err == nil {
+		t.Fatal("future-format file loaded without error")
+	}
+}
+
+func TestNewSessionStampsCurrentVersion(t *testing.T) {
+	if s := NewSession("KMFWB37HXTESTFIX0"); s.Version != SessionFormatVersion {
+		t.Fatalf("new session version = %d", s.Version)
+	}
+}
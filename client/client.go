@@ -0,0 +1,187 @@
+// Package client is the public Go SDK for an iload-obd2 instance. It
+// wraps the REST and WebSocket APIs with typed structs so other programs
+// can consume telemetry without re-implementing the protocol.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Vehicle mirrors the server's vehicle record.
+type Vehicle struct {
+	VIN        string    `json:"vin"`
+	Make       string    `json:"make"`
+	Model      string    `json:"model"`
+	Year       int       `json:"year"`
+	Registered time.Time `json:"registered"`
+}
+
+// Location is a GPS fix attached to a telemetry sample.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+	Speed     float64 `json:"speed"`
+	Course    float64 `json:"course"`
+}
+
+// TelemetryData is one live data sample.
+type TelemetryData struct {
+	VIN         string    `json:"vin"`
+	Timestamp   time.Time `json:"timestamp"`
+	RPM         float64   `json:"rpm"`
+	Speed       float64   `json:"speed"`
+	CoolantTemp float64   `json:"coolant_temp"`
+	EngineLoad  float64   `json:"engine_load"`
+	ThrottlePos float64   `json:"throttle_pos"`
+	IntakeTemp  float64   `json:"intake_temp"`
+	MAF         float64   `json:"maf"`
+	FuelLevel   float64   `json:"fuel_level"`
+	Voltage     float64   `json:"voltage"`
+	// Fuel trims are percent corrections to base fuelling; bank 2 stays
+	// 0 on single-bank engines.
+	ShortFuelTrim1 float64 `json:"short_fuel_trim_1,omitempty"`
+	LongFuelTrim1  float64 `json:"long_fuel_trim_1,omitempty"`
+	ShortFuelTrim2 float64 `json:"short_fuel_trim_2,omitempty"`
+	LongFuelTrim2  float64 `json:"long_fuel_trim_2,omitempty"`
+	// EquivRatio is the commanded equivalence ratio λ; O2Lambda and
+	// O2Current (mA) come from wideband sensor 1.
+	EquivRatio float64 `json:"equiv_ratio,omitempty"`
+	O2Lambda   float64 `json:"o2_lambda,omitempty"`
+	O2Current  float64 `json:"o2_current,omitempty"`
+	// Diesel air and fuel path channels; manifold pressure is absolute.
+	ManifoldKPa  float64  `json:"manifold_kpa,omitempty"`
+	RailBar      float64  `json:"rail_bar,omitempty"`
+	EGRCommanded float64  `json:"egr_commanded,omitempty"`
+	EGRActual    float64  `json:"egr_actual,omitempty"`
+	DPFDiffKPa   float64  `json:"dpf_diff_kpa,omitempty"`
+	DTCs         []string `json:"dtcs,omitempty"`
+	// Pending codes haven't confirmed yet; permanent ones survive a
+	// clear until a clean drive cycle.
+	PendingDTCs   []string `json:"pending_dtcs,omitempty"`
+	PermanentDTCs []string `json:"permanent_dtcs,omitempty"`
+	// DTCsByECU splits stored codes by reporting module where the
+	// logger collected every broadcast responder.
+	DTCsByECU map[string][]string `json:"dtcs_by_ecu,omitempty"`
+	Location  *Location           `json:"location,omitempty"`
+}
+
+// Alert is a raised threshold or fault condition.
+type Alert struct {
+	ID           int64     `json:"id"`
+	VIN          string    `json:"vin"`
+	Type         string    `json:"type"`
+	Message      string    `json:"message"`
+	Value        float64   `json:"value"`
+	Threshold    float64   `json:"threshold"`
+	Timestamp    time.Time `json:"timestamp"`
+	Acknowledged bool      `json:"acknowledged"`
+}
+
+// VehicleStatus is one vehicle's entry in a fleet status response.
+type VehicleStatus struct {
+	Vehicle   Vehicle        `json:"vehicle"`
+	Telemetry *TelemetryData `json:"telemetry,omitempty"`
+	DTCs      []string       `json:"dtcs,omitempty"`
+	Alerts    []Alert        `json:"alerts,omitempty"`
+	LastSeen  *time.Time     `json:"last_seen,omitempty"`
+}
+
+// FleetStatus is the response from the fleet status endpoint.
+type FleetStatus struct {
+	Vehicles    []VehicleStatus `json:"vehicles"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Timezone    string          `json:"timezone"`
+}
+
+// Client talks to one iload-obd2 instance.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// Option customises a Client.
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithBasicAuth authenticates every request with the given account.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) { c.username, c.password = username, password }
+}
+
+// New creates a Client for the instance at baseURL, e.g.
+// "http://vanpi.local:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is a non-2xx response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("server returned %d: %s", e.StatusCode, e.Message)
+}
+
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(body, &apiErr)
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErr.Error}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// FleetStatus returns the latest state of every visible vehicle.
+func (c *Client) FleetStatus(ctx context.Context) (*FleetStatus, error) {
+	var fs FleetStatus
+	if err := c.get(ctx, "/api/v1/fleet/status", &fs); err != nil {
+		return nil, err
+	}
+	return &fs, nil
+}
+
+// Vehicle returns a single vehicle record.
+func (c *Client) Vehicle(ctx context.Context, vin string) (*Vehicle, error) {
+	var v Vehicle
+	if err := c.get(ctx, "/api/v1/vehicles/"+vin, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Message is one envelope from the live WebSocket stream. Seq increases
+// by one per server broadcast; gaps mean messages were missed.
+type Message struct {
+	Seq  uint64          `json:"seq"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Telemetry decodes the message payload as a telemetry sample; it fails
+// unless Type is "telemetry".
+func (m *Message) Telemetry() (*TelemetryData, error) {
+	var t TelemetryData
+	if err := json.Unmarshal(m.Data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Stream is a live connection to the server's WebSocket endpoint.
+type Stream struct {
+	conn *websocket.Conn
+}
+
+// wsURL converts the client's base URL to the ws:// or wss:// endpoint.
+func (c *Client) wsURL() string {
+	u := c.baseURL
+	u = strings.Replace(u, "https://", "wss://", 1)
+	u = strings.Replace(u, "http://", "ws://", 1)
+	return u + "/ws"
+}
+
+// Stream opens the live telemetry stream.
+func (c *Client) Stream(ctx context.Context) (*Stream, error) {
+	header := http.Header{}
+	if c.username != "" {
+		req, _ := http.NewRequest(http.MethodGet, c.baseURL, nil)
+		req.SetBasicAuth(c.username, c.password)
+		header.Set("Authorization", req.Header.Get("Authorization"))
+	}
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: "websocket dial failed"}
+		}
+		return nil, err
+	}
+	return &Stream{conn: conn}, nil
+}
+
+// Next blocks until the next message arrives.
+func (s *Stream) Next() (*Message, error) {
+	var m Message
+	if err := s.conn.ReadJSON(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Close shuts the stream down.
+func (s *Stream) Close() error { return s.conn.Close() }
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Subscriber consumes the live stream resiliently: it reconnects with
+// exponential backoff when the connection drops and uses the broadcast
+// sequence numbers to report messages missed while disconnected (or shed
+// by the server), so external loggers can account for gaps.
+type Subscriber struct {
+	client *Client
+
+	// Types limits delivery to these message types; empty means all.
+	Types []string
+	// OnMessage is called for every delivered message. Required.
+	OnMessage func(*Message)
+	// OnGap is called with the number of messages missed when a sequence
+	// gap is observed. Optional.
+	OnGap func(missed uint64)
+	// OnConnect is called after each successful (re)connection. Optional.
+	OnConnect func()
+
+	// MaxBackoff caps the reconnect delay; defaults to a minute.
+	MaxBackoff time.Duration
+
+	lastSeq uint64
+}
+
+// Subscribe builds a Subscriber delivering messages to onMessage.
+func (c *Client) Subscribe(onMessage func(*Message)) *Subscriber {
+	return &Subscriber{client: c, OnMessage: onMessage}
+}
+
+// Run consumes the stream until ctx is cancelled, reconnecting as needed.
+func (s *Subscriber) Run(ctx context.Context) error {
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+	backoff := time.Second
+	for {
+		stream, err := s.client.Stream(ctx)
+		if err != nil {
+			log.Printf("client: stream connect: %v", err)
+		} else {
+			if s.OnConnect != nil {
+				s.OnConnect()
+			}
+			backoff = time.Second
+			s.consume(ctx, stream)
+			stream.Close()
+		}
+		// Jittered exponential backoff before the next attempt.
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff)/2+1))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (s *Subscriber) consume(ctx context.Context, stream *Stream) {
+	// Close the connection when ctx ends so Next unblocks.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.Close()
+		case <-done:
+		}
+	}()
+	for {
+		m, err := stream.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("client: stream read: %v", err)
+			}
+			return
+		}
+		if s.lastSeq != 0 && m.Seq > s.lastSeq+1 && s.OnGap != nil {
+			s.OnGap(m.Seq - s.lastSeq - 1)
+		}
+		if m.Seq > s.lastSeq {
+			s.lastSeq = m.Seq
+		}
+		if len(s.Types) > 0 && !contains(s.Types, m.Type) {
+			continue
+		}
+		s.OnMessage(m)
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
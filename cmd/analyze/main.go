@@ -0,0 +1,314 @@
+// Command analyze inspects recorded capture sessions.
+//
+// Usage:
+//
+//	analyze -session captures/xyz.json -discover
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"strings"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/canid"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/dbc"
+	"github.com/anodyne74/iload-obd2/internal/discovery"
+	"github.com/anodyne74/iload-obd2/internal/j1939"
+	"github.com/anodyne74/iload-obd2/internal/pcap"
+)
+
+func main() {
+	var (
+		sessionPath = flag.String("session", "", "capture session file to analyze")
+		labelsPath  = flag.String("labels", "", "CAN ID label registry (YAML)")
+		discover    = flag.Bool("discover", false, "report per-ID rates, DLCs and byte-change heatmaps")
+		correlate   = flag.String("correlate", "", "rank frame bytes against a polled OBD channel (e.g. speed)")
+		diffSpec    = flag.String("diff", "", "diff two intervals, e.g. '10s-20s,40s-50s' from session start")
+		dbPath      = flag.String("db", "iload.db", "SQLite database holding polled telemetry")
+		dbcPath     = flag.String("dbc", "", "Vector DBC file; prints per-signal statistics for the session")
+		bitrate     = flag.Int("bitrate", 0, "bus arbitration bitrate for the -discover load estimate")
+		dataBitrate = flag.Int("data-bitrate", 0, "CAN-FD data-phase bitrate (defaults to -bitrate)")
+		exportPcap  = flag.String("export-pcap", "", "write the session as pcapng for Wireshark's CAN dissectors")
+		j1939Flag   = flag.Bool("j1939", false, "decode SAE J1939 broadcast groups; prints per-SPN statistics")
+	)
+	flag.Parse()
+
+	if *sessionPath == "" {
+		fmt.Fprintln(os.Stderr, "usage: analyze -session <file> [-labels <file>] [-discover] [-correlate <channel>]")
+		os.Exit(2)
+	}
+	session, err := capture.LoadSession(*sessionPath)
+	if err != nil {
+		log.Fatalf("load session: %v", err)
+	}
+
+	var reg *canid.Registry
+	if *labelsPath != "" {
+		if reg, err = canid.Load(*labelsPath); err != nil {
+			log.Fatalf("load labels: %v", err)
+		}
+	}
+
+	if *discover {
+		printDiscovery(discovery.Analyze(session.Frames, reg), *bitrate, *dataBitrate)
+	}
+	if *correlate != "" {
+		runCorrelate(session, *correlate, *dbPath)
+	}
+	if *diffSpec != "" {
+		runDiff(session, *diffSpec)
+	}
+	if *dbcPath != "" {
+		runSignals(session, *dbcPath)
+	}
+	if *exportPcap != "" {
+		runExportPcap(session, *exportPcap)
+	}
+	if *j1939Flag {
+		runJ1939(session)
+	}
+}
+
+// runJ1939 decodes the session's J1939 broadcast parameter groups and
+// prints per-SPN ranges — the quick way to tell whether a 29-bit
+// vehicle speaks J1939 before investing in a DBC for it.
+func runJ1939(session *capture.Session) {
+	type stat struct {
+		spn            int
+		unit           string
+		count          int
+		min, max, suma float64
+	}
+	stats := map[string]*stat{}
+	var order []string
+	for _, f := range session.Frames {
+		h, sigs, ok := j1939.Decode(f)
+		if !ok {
+			continue
+		}
+		for _, sig := range sigs {
+			key := fmt.Sprintf("pgn%d.%s", h.PGN, sig.Name)
+			st, ok := stats[key]
+			if !ok {
+				st = &stat{spn: sig.SPN, unit: sig.Unit, min: sig.Value, max: sig.Value}
+				stats[key] = st
+				order = append(order, key)
+			}
+			st.count++
+			st.suma += sig.Value
+			if sig.Value < st.min {
+				st.min = sig.Value
+			}
+			if sig.Value > st.max {
+				st.max = sig.Value
+			}
+		}
+	}
+	if len(order) == 0 {
+		fmt.Println("no frames matched a known J1939 parameter group")
+		return
+	}
+	sort.Strings(order)
+	fmt.Printf("%-32s %6s %8s %10s %10s %10s  %s\n", "SIGNAL", "SPN", "COUNT", "MIN", "MEAN", "MAX", "UNIT")
+	for _, key := range order {
+		st := stats[key]
+		fmt.Printf("%-32s %6d %8d %10.2f %10.2f %10.2f  %s\n",
+			key, st.spn, st.count, st.min, st.suma/float64(st.count), st.max, st.unit)
+	}
+}
+
+// runExportPcap writes the session's frames as a pcapng capture that
+// Wireshark and tshark open directly.
+func runExportPcap(session *capture.Session, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("export pcap: %v", err)
+	}
+	w := pcap.NewNGWriter(f)
+	if err := w.WriteHeader(); err != nil {
+		log.Fatalf("export pcap: %v", err)
+	}
+	for _, fr := range session.Frames {
+		if err := w.WriteFrame(fr); err != nil {
+			log.Fatalf("export pcap: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("export pcap: %v", err)
+	}
+	fmt.Printf("wrote %d frame(s) to %s\n", len(session.Frames), path)
+}
+
+// runSignals decodes the session through a DBC and prints per-signal
+// ranges — the quick sanity check that a signal definition actually
+// matches what the vehicle sends.
+func runSignals(session *capture.Session, dbcPath string) {
+	db, err := dbc.Load(dbcPath)
+	if err != nil {
+		log.Fatalf("load DBC: %v", err)
+	}
+	type stat struct {
+		unit           string
+		count          int
+		min, max, suma float64
+	}
+	stats := map[string]*stat{}
+	var order []string
+	for _, f := range session.Frames {
+		dec, ok := db.Decode(f)
+		if !ok {
+			continue
+		}
+		for _, sig := range dec.Signals {
+			key := dec.Message + "." + sig.Name
+			st, ok := stats[key]
+			if !ok {
+				st = &stat{unit: sig.Unit, min: sig.Value, max: sig.Value}
+				stats[key] = st
+				order = append(order, key)
+			}
+			st.count++
+			st.suma += sig.Value
+			if sig.Value < st.min {
+				st.min = sig.Value
+			}
+			if sig.Value > st.max {
+				st.max = sig.Value
+			}
+		}
+	}
+	if len(order) == 0 {
+		fmt.Println("no frames matched the DBC")
+		return
+	}
+	sort.Strings(order)
+	fmt.Printf("%-36s %8s %10s %10s %10s  %s\n", "SIGNAL", "COUNT", "MIN", "MEAN", "MAX", "UNIT")
+	for _, key := range order {
+		st := stats[key]
+		fmt.Printf("%-36s %8d %10.2f %10.2f %10.2f  %s\n",
+			key, st.count, st.min, st.suma/float64(st.count), st.max, st.unit)
+	}
+}
+
+// runDiff parses "10s-20s,40s-50s" (offsets from session start) and
+// prints the byte positions whose steady value changed between the two.
+func runDiff(session *capture.Session, spec string) {
+	parts := strings.Split(spec, ",")
+	if len(parts) != 2 {
+		log.Fatalf("bad -diff spec %q, want 'A-B,C-D'", spec)
+	}
+	parse := func(s string) discovery.Interval {
+		bounds := strings.Split(s, "-")
+		if len(bounds) != 2 {
+			log.Fatalf("bad interval %q", s)
+		}
+		from, err1 := time.ParseDuration(bounds[0])
+		to, err2 := time.ParseDuration(bounds[1])
+		if err1 != nil || err2 != nil || to <= from {
+			log.Fatalf("bad interval %q", s)
+		}
+		return discovery.Interval{
+			From: session.StartTime + from.Nanoseconds(),
+			To:   session.StartTime + to.Nanoseconds(),
+		}
+	}
+	diffs := discovery.Diff(session.Frames, parse(parts[0]), parse(parts[1]))
+	if len(diffs) == 0 {
+		fmt.Println("no steady-state byte changes between the intervals")
+		return
+	}
+	fmt.Printf("%-8s %-5s %-6s %-6s %-10s %s\n", "ID", "BYTE", "A", "B", "BITS", "NOTE")
+	for _, d := range diffs {
+		note := ""
+		if d.Noisy {
+			note = "noisy"
+		}
+		fmt.Printf("0x%03X    %-5d 0x%02X   0x%02X   %08b   %s\n",
+			d.ID, d.Byte, d.ValueA, d.ValueB, d.ChangedBits, note)
+	}
+}
+
+// runCorrelate builds the reference channel from telemetry polled during
+// the session window and ranks frame bytes against it.
+func runCorrelate(session *capture.Session, channel, dbPath string) {
+	store, err := datastore.OpenSQLite(dbPath)
+	if err != nil {
+		log.Fatalf("open datastore: %v", err)
+	}
+	defer store.Close()
+	samples, err := store.Telemetry(session.VIN,
+		time.Unix(0, session.StartTime), time.Unix(0, session.EndTime+1))
+	if err != nil {
+		log.Fatalf("load telemetry: %v", err)
+	}
+	var ref []discovery.RefSample
+	for _, t := range samples {
+		v, ok := datastore.ChannelValue(t, channel)
+		if !ok {
+			log.Fatalf("unknown channel %q", channel)
+		}
+		ref = append(ref, discovery.RefSample{Timestamp: t.Timestamp.UnixNano(), Value: v})
+	}
+	if len(ref) < 2 {
+		log.Fatalf("no %s telemetry recorded during the session window", channel)
+	}
+	matches := discovery.Correlate(session.Frames, ref)
+	fmt.Printf("top byte matches for %s:\n", channel)
+	for i, m := range matches {
+		if i >= 15 {
+			break
+		}
+		fmt.Printf("0x%03X byte %d  r=%+.3f  (%d samples)\n", m.ID, m.Byte, m.Correlation, m.Samples)
+	}
+}
+
+func printDiscovery(rep *discovery.Report, bitrate, dataBitrate int) {
+	fmt.Printf("window: %s, %d distinct IDs, %d unlabeled\n",
+		rep.Duration.Round(0), len(rep.IDs), len(rep.Unknown))
+	if bitrate > 0 {
+		fmt.Printf("bus load: %.1f%%\n", rep.LoadPct(bitrate, dataBitrate))
+	}
+	fmt.Println()
+	fmt.Printf("%-8s %-24s %8s %7s  %s\n", "ID", "LABEL", "COUNT", "HZ", "BYTE HEAT")
+	for _, st := range rep.IDs {
+		label := st.Label
+		if label == "" {
+			label = "(unknown)"
+		}
+		id := fmt.Sprintf("0x%03X", st.ID)
+		if st.Extended {
+			id = fmt.Sprintf("0x%08X", st.ID)
+		}
+		fmt.Printf("%-8s %-24s %8d %7.1f  ", id, label, st.Count, st.RateHz)
+		for _, h := range st.ByteHeat {
+			fmt.Printf("%c", heatGlyph(h))
+		}
+		if st.FDCount > 0 {
+			fmt.Printf("  FD×%d", st.FDCount)
+		}
+		fmt.Println()
+	}
+}
+
+// heatGlyph maps a 0..1 change rate onto a sparkline-style glyph.
+func heatGlyph(h float64) rune {
+	switch {
+	case h == 0:
+		return '.'
+	case h < 0.1:
+		return '░'
+	case h < 0.5:
+		return '▒'
+	case h < 0.9:
+		return '▓'
+	default:
+		return '█'
+	}
+}
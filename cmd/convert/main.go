@@ -0,0 +1,144 @@
+// Command convert rewrites capture session files into the current
+// format. It reads both legacy JSON layouts (unversioned v1 and
+// versioned v2) via the normal migration path, plus candump logs from
+// can-utils and Vector ASC/BLF traces, and writes the canonical
+// encoding — or a candump log for replay with canplayer, or an ASC
+// trace for CANoe — batch-processing whole directories.
+//
+// Usage:
+//
+//	convert -in captures/ -out converted/ -encoding cbor
+//	convert -in old-session.json -out session.cbor
+//	convert -in candump-2026-09-01.log -out session.json
+//	convert -in trip.json -encoding candump
+//	convert -in trip.json -out share.json -anonymize -salt fleet7 -gps fuzz
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func main() {
+	var (
+		in         = flag.String("in", "", "session file or directory of sessions to convert")
+		out        = flag.String("out", "", "output file or directory (default: alongside input)")
+		encoding   = flag.String("encoding", "cbor", "output encoding: json, cbor, candump or asc")
+		anonymize  = flag.Bool("anonymize", false, "pseudonymize the VIN and apply the GPS policy before writing")
+		salt       = flag.String("salt", "", "pseudonym salt for -anonymize; one salt keeps a vehicle's captures correlatable")
+		gps        = flag.String("gps", "strip", "location policy for -anonymize: strip or fuzz")
+		fuzzRadius = flag.Float64("fuzz-radius", 250, "maximum offset in metres for -gps fuzz")
+	)
+	flag.Parse()
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "usage: convert -in <file-or-dir> [-out <file-or-dir>] [-encoding json|cbor]")
+		os.Exit(2)
+	}
+	enc, err := capture.ParseEncoding(*encoding)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var anonOpts *capture.AnonymizeOptions
+	if *anonymize {
+		policy := capture.GPSPolicy(*gps)
+		if policy != capture.GPSStrip && policy != capture.GPSFuzz {
+			log.Fatalf("convert: unknown gps policy %q (want strip or fuzz)", *gps)
+		}
+		anonOpts = &capture.AnonymizeOptions{Salt: *salt, GPS: policy, FuzzRadiusM: *fuzzRadius}
+	}
+
+	info, err := os.Stat(*in)
+	if err != nil {
+		log.Fatalf("convert: %v", err)
+	}
+	if !info.IsDir() {
+		if err := convertOne(*in, outPath(*in, *out, enc, false), enc, anonOpts); err != nil {
+			log.Fatalf("convert: %v", err)
+		}
+		return
+	}
+
+	entries, err := os.ReadDir(*in)
+	if err != nil {
+		log.Fatalf("convert: %v", err)
+	}
+	converted, failed := 0, 0
+	for _, e := range entries {
+		if e.IsDir() || !isSessionFile(e.Name()) {
+			continue
+		}
+		src := filepath.Join(*in, e.Name())
+		dst := outPath(src, *out, enc, true)
+		if err := convertOne(src, dst, enc, anonOpts); err != nil {
+			log.Printf("convert: %s: %v", e.Name(), err)
+			failed++
+			continue
+		}
+		converted++
+	}
+	fmt.Printf("converted %d session(s), %d failed\n", converted, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func convertOne(src, dst string, enc capture.Encoding, anon *capture.AnonymizeOptions) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if anon != nil {
+		s, err := capture.LoadSession(src)
+		if err != nil {
+			return err
+		}
+		s.Anonymize(*anon)
+		if err := s.SaveEncoded(dst, enc); err != nil {
+			return err
+		}
+	} else if err := capture.Convert(src, dst, enc); err != nil {
+		return err
+	}
+	fmt.Printf("%s -> %s\n", src, dst)
+	return nil
+}
+
+// isSessionFile filters a captures/ directory down to things worth
+// attempting; the loader rejects anything that isn't really a session.
+func isSessionFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".cbor", ".log", ".asc", ".blf":
+		return true
+	}
+	return false
+}
+
+// outPath derives the destination for one input file. For directory
+// batches out names the output directory; for single files out (when
+// set) names the file itself.
+func outPath(src, out string, enc capture.Encoding, batch bool) string {
+	ext := ".json"
+	switch enc {
+	case capture.EncodingCBOR:
+		ext = ".cbor"
+	case capture.EncodingCandump:
+		ext = ".log"
+	case capture.EncodingASC:
+		ext = ".asc"
+	}
+	name := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src)) + ext
+	switch {
+	case out == "":
+		return filepath.Join(filepath.Dir(src), name)
+	case batch:
+		return filepath.Join(out, name)
+	default:
+		return out
+	}
+}
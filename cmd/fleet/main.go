@@ -0,0 +1,131 @@
+// Command fleet is the operator CLI for an iload-obd2 database.
+//
+// Usage:
+//
+//	fleet -db iload.db vehicles
+//	fleet -db iload.db ranking
+//	fleet -db iload.db trips <VIN> [tag]
+//	fleet -db iload.db tag-trip <id> tag1,tag2
+//	fleet -db iload.db delete-vin <VIN>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/rollup"
+)
+
+func main() {
+	dbPath := flag.String("db", "iload.db", "path to SQLite database")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+	}
+
+	store, err := datastore.OpenSQLite(*dbPath)
+	if err != nil {
+		log.Fatalf("open datastore: %v", err)
+	}
+	defer store.Close()
+
+	switch args[0] {
+	case "vehicles":
+		vehicles, err := store.Vehicles()
+		if err != nil {
+			log.Fatalf("list vehicles: %v", err)
+		}
+		for _, v := range vehicles {
+			fmt.Printf("%s\t%s %s %d\n", v.VIN, v.Make, v.Model, v.Year)
+		}
+	case "ranking":
+		vehicles, err := store.Vehicles()
+		if err != nil {
+			log.Fatalf("list vehicles: %v", err)
+		}
+		vins := make([]string, len(vehicles))
+		for i, v := range vehicles {
+			vins[i] = v.VIN
+		}
+		rankings, err := rollup.Rank(store, vins, "weekly", 4)
+		if err != nil {
+			log.Fatalf("rank fleet: %v", err)
+		}
+		fmt.Printf("%-4s %-20s %6s %10s %8s %10s\n",
+			"RANK", "VIN", "SCORE", "L/100KM", "IDLE%", "HARSH/100")
+		for _, r := range rankings {
+			fmt.Printf("%-4d %-20s %6.1f %10.1f %8.1f %10.1f\n",
+				r.Rank, r.VIN, r.Score, r.Economy, r.IdlePct, r.HarshPer100)
+		}
+	case "trips", "captures":
+		if len(args) < 2 || len(args) > 3 {
+			usage()
+		}
+		vin, tag := args[1], ""
+		if len(args) == 3 {
+			tag = args[2]
+		}
+		if args[0] == "trips" {
+			trips, err := store.SearchTrips(vin, tag, time.Time{}, time.Time{})
+			if err != nil {
+				log.Fatalf("search trips: %v", err)
+			}
+			for _, t := range trips {
+				fmt.Printf("%d\t%s\t%.1fkm\t%.1fL\t%s\n",
+					t.ID, t.Start.Format(time.RFC3339), t.DistanceKm, t.FuelL,
+					strings.Join(t.Tags, ","))
+			}
+		} else {
+			caps, err := store.SearchCaptures(vin, tag, time.Time{}, time.Time{})
+			if err != nil {
+				log.Fatalf("search captures: %v", err)
+			}
+			for _, c := range caps {
+				fmt.Printf("%d\t%s\t%s\t%d frames\t%s\n",
+					c.ID, c.Start.Format(time.RFC3339), c.Path, c.Frames,
+					strings.Join(c.Tags, ","))
+			}
+		}
+	case "tag-trip", "tag-capture":
+		if len(args) != 3 {
+			usage()
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			log.Fatalf("bad id %q", args[1])
+		}
+		tags := strings.Split(args[2], ",")
+		if args[0] == "tag-trip" {
+			err = store.TagTrip(id, tags)
+		} else {
+			err = store.TagCapture(id, tags)
+		}
+		if err != nil {
+			log.Fatalf("%s %d: %v", args[0], id, err)
+		}
+	case "delete-vin":
+		if len(args) != 2 {
+			usage()
+		}
+		vin := args[1]
+		if err := store.DeleteAllData(vin); err != nil {
+			log.Fatalf("delete %s: %v", vin, err)
+		}
+		fmt.Printf("deleted all data for %s\n", vin)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: fleet [-db path] vehicles | ranking | trips <VIN> [tag] | captures <VIN> [tag] | tag-trip <id> <tags> | tag-capture <id> <tags> | delete-vin <VIN>\n")
+	os.Exit(2)
+}
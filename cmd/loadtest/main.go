@@ -0,0 +1,238 @@
+// Command loadtest drives a running iload-obd2 server with synthetic
+// fleet traffic and measures what comes out the other side: ingest
+// latency at the sync endpoint and delivery latency, sequence gaps and
+// drops on the WebSocket stream. Run it against a staging instance
+// before a fleet rollout to catch throughput regressions in the
+// broadcaster or the datastore writers.
+//
+//	loadtest -url http://vanpi.local:8080 -vehicles 50 -rate 10 -duration 1m
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/client"
+)
+
+func main() {
+	var (
+		url      = flag.String("url", "http://localhost:8080", "base URL of the server under test")
+		vehicles = flag.Int("vehicles", 10, "number of simulated vehicles")
+		rate     = flag.Float64("rate", 10, "samples per second per vehicle")
+		duration = flag.Duration("duration", 30*time.Second, "how long to generate load")
+		flushInt = flag.Duration("flush", 500*time.Millisecond, "how often each vehicle posts its batch")
+		ws       = flag.Bool("ws", true, "also subscribe to the WebSocket stream and measure delivery")
+	)
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	st := &stats{}
+	var wg sync.WaitGroup
+	if *ws {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			subscribe(ctx, *url, st)
+		}()
+	}
+	for i := 0; i < *vehicles; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			drive(ctx, *url, fmt.Sprintf("LOADTEST%08d", n), *rate, *flushInt, st)
+		}(i)
+	}
+	wg.Wait()
+	st.report(*duration)
+}
+
+// drive simulates one vehicle: a batch of plausible samples per flush
+// interval, posted to the sync ingest endpoint like an edge agent.
+// Channels vary smoothly so the server's plausibility validator doesn't
+// reject the load as implausible — a rejected sample measures nothing.
+func drive(ctx context.Context, url, vin string, rate float64, flush time.Duration, st *stats) {
+	hc := &http.Client{Timeout: 30 * time.Second}
+	perFlush := int(rate * flush.Seconds())
+	if perFlush < 1 {
+		perFlush = 1
+	}
+	ticker := time.NewTicker(flush)
+	defer ticker.Stop()
+	phase := 0.0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		batch := make([]client.TelemetryData, perFlush)
+		for i := range batch {
+			phase += 0.01
+			batch[i] = client.TelemetryData{
+				VIN:         vin,
+				Timestamp:   time.Now().UTC(),
+				RPM:         1800 + 400*math.Sin(phase),
+				Speed:       80 + 5*math.Sin(phase/3),
+				CoolantTemp: 88,
+				EngineLoad:  40 + 10*math.Sin(phase/2),
+				IntakeTemp:  35,
+				MAF:         25,
+				FuelLevel:   60,
+				Voltage:     14.1,
+			}
+		}
+		body, err := json.Marshal(batch)
+		if err != nil {
+			log.Fatalf("marshal batch: %v", err)
+		}
+		start := time.Now()
+		resp, err := hc.Post(url+"/api/v1/sync/telemetry", "application/json", bytes.NewReader(body))
+		if err != nil {
+			st.postError(err)
+			continue
+		}
+		var result struct {
+			Accepted int `json:"accepted"`
+			Rejected int `json:"rejected"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			st.postError(fmt.Errorf("status %d", resp.StatusCode))
+			continue
+		}
+		st.posted(time.Since(start), result.Accepted, result.Rejected)
+	}
+}
+
+// subscribe consumes the live stream, counting sequence gaps and timing
+// each telemetry message against the timestamp stamped at generation.
+// Generator and subscriber share a clock, so the difference is genuine
+// end-to-end latency through ingest, storage and the broadcaster.
+func subscribe(ctx context.Context, url string, st *stats) {
+	stream, err := client.New(url).Stream(ctx)
+	if err != nil {
+		log.Printf("ws: %v (delivery not measured)", err)
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+	}()
+	var lastSeq uint64
+	for {
+		m, err := stream.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				// The server drops clients that can't keep up; that is
+				// itself a result worth reporting.
+				st.wsDropped()
+			}
+			return
+		}
+		gap := 0
+		if lastSeq != 0 && m.Seq > lastSeq+1 {
+			gap = int(m.Seq - lastSeq - 1)
+		}
+		lastSeq = m.Seq
+		var latency time.Duration
+		if m.Type == "telemetry" {
+			if t, err := m.Telemetry(); err == nil {
+				latency = time.Since(t.Timestamp)
+			}
+		}
+		st.received(gap, latency)
+	}
+}
+
+// stats aggregates measurements across every goroutine.
+type stats struct {
+	mu           sync.Mutex
+	postLatency  []time.Duration
+	accepted     int
+	rejected     int
+	errors       int
+	lastErr      error
+	wsLatency    []time.Duration
+	wsMessages   int
+	wsGaps       int
+	wsDisconnect bool
+}
+
+func (s *stats) posted(d time.Duration, accepted, rejected int) {
+	s.mu.Lock()
+	s.postLatency = append(s.postLatency, d)
+	s.accepted += accepted
+	s.rejected += rejected
+	s.mu.Unlock()
+}
+
+func (s *stats) postError(err error) {
+	s.mu.Lock()
+	s.errors++
+	s.lastErr = err
+	s.mu.Unlock()
+}
+
+func (s *stats) received(gap int, latency time.Duration) {
+	s.mu.Lock()
+	s.wsMessages++
+	s.wsGaps += gap
+	if latency > 0 {
+		s.wsLatency = append(s.wsLatency, latency)
+	}
+	s.mu.Unlock()
+}
+
+func (s *stats) wsDropped() {
+	s.mu.Lock()
+	s.wsDisconnect = true
+	s.mu.Unlock()
+}
+
+func (s *stats) report(ran time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("ingest: %d accepted, %d rejected, %d request errors over %s (%.0f samples/s)\n",
+		s.accepted, s.rejected, s.errors, ran, float64(s.accepted)/ran.Seconds())
+	if s.lastErr != nil {
+		fmt.Printf("ingest: last error: %v\n", s.lastErr)
+	}
+	fmt.Printf("ingest latency: p50 %s  p95 %s  max %s\n",
+		percentile(s.postLatency, 50), percentile(s.postLatency, 95), percentile(s.postLatency, 100))
+	if s.wsMessages == 0 && !s.wsDisconnect {
+		return
+	}
+	fmt.Printf("stream: %d messages, %d sequence gaps\n", s.wsMessages, s.wsGaps)
+	fmt.Printf("stream latency: p50 %s  p95 %s  max %s\n",
+		percentile(s.wsLatency, 50), percentile(s.wsLatency, 95), percentile(s.wsLatency, 100))
+	if s.wsDisconnect {
+		fmt.Println("stream: DROPPED by the server mid-test (broadcaster could not keep up)")
+	}
+}
+
+// percentile returns the pth percentile of ds, rounded for display.
+func percentile(ds []time.Duration, p int) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := len(sorted)*p/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx].Round(100 * time.Microsecond)
+}
@@ -0,0 +1,417 @@
+// Command query talks to an OBD adapter directly, outside the daemon —
+// for checking cabling and adapter health before committing to a full
+// logging setup.
+//
+// Usage:
+//
+//	query -list-ports            enumerate serial ports and probe each
+//	query -discover              scan ports and WiFi endpoints, print a config snippet
+//	query                        auto-detect the adapter and identify it
+//	query -port /dev/ttyUSB1     probe a specific port
+//	query -clear-dtc             clear stored trouble codes (mode 04)
+//	query -protocol auto         search for the vehicle protocol (pre-CAN iLoads)
+//	query -readiness             emissions readiness monitor status
+//	query -list-pids             show the PID catalog
+//	query -pid rail_pressure     read one catalog channel
+//	query -read-maps             pull the known ECU calibration maps over UDS
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/anodyne74/iload-obd2/internal/obd"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+	"github.com/anodyne74/iload-obd2/internal/uds"
+)
+
+func main() {
+	var (
+		listPorts = flag.Bool("list-ports", false, "enumerate serial ports and probe each for an ELM327")
+		discover  = flag.Bool("discover", false, "scan serial ports and known WiFi endpoints, print the matching transports: config")
+		port      = flag.String("port", "auto", "serial port, or \"auto\" to scan for the adapter")
+		clearDTC  = flag.Bool("clear-dtc", false, "clear stored trouble codes (mode 04) and confirm with a re-read")
+		readiness = flag.Bool("readiness", false, "show emissions readiness monitor status (mode 01 PID 01)")
+		listPIDs  = flag.Bool("list-pids", false, "list the PID catalog (built-in extended PIDs plus -pids-file)")
+		pidName   = flag.String("pid", "", "read one catalog channel by name")
+		pidsFile  = flag.String("pids-file", "", "YAML file of user-defined PIDs merged into the catalog")
+		readMaps  = flag.Bool("read-maps", false, "pull the known calibration maps over UDS and print them")
+		logPath   = flag.String("traffic-log", "", "tee raw adapter traffic to this file as a timestamped hex dump")
+		security  = flag.String("security", "", "SecurityAccess key algorithm for -read-maps (e.g. xor_ff); empty skips the unlock")
+		protocol  = flag.String("protocol", "", "pin the OBD protocol: can, kwp, kwp-slow or iso9141; \"auto\" searches, which pre-CAN iLoads need")
+	)
+	flag.Parse()
+
+	if *logPath != "" {
+		l, err := transport.NewTrafficLog(*logPath)
+		if err != nil {
+			log.Fatalf("query: %v", err)
+		}
+		defer l.Close()
+		trafficLog = l
+	}
+
+	catalog := obd.DefaultCatalog()
+	if *pidsFile != "" {
+		if err := catalog.LoadFile(*pidsFile); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+	}
+	if *listPIDs {
+		for _, d := range catalog.Defs() {
+			fmt.Printf("%-20s mode %02X pid %04X  %s\n", d.Name, d.Mode, d.PID, d.Unit)
+		}
+		return
+	}
+
+	if *discover {
+		if err := runDiscover(); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+		return
+	}
+
+	if *listPorts {
+		ports, err := transport.EnumeratePorts()
+		if err != nil {
+			log.Fatalf("query: %v", err)
+		}
+		if len(ports) == 0 {
+			fmt.Println("no serial ports found")
+			return
+		}
+		for _, p := range ports {
+			if banner, baud, err := transport.IdentifyELM327(p); err == nil {
+				fmt.Printf("%s\t%s @ %d baud\n", p, banner, baud)
+			} else {
+				fmt.Printf("%s\t-\n", p)
+			}
+		}
+		return
+	}
+
+	info, err := adapter(*port)
+	if err != nil {
+		log.Fatalf("query: %v", err)
+	}
+	fmt.Printf("adapter: %s on %s @ %d baud\n", info.ELM, info.Name, info.Baud)
+
+	if *clearDTC {
+		if err := runClearDTC(info, *protocol); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+	}
+	if *readiness {
+		if err := runReadiness(info, *protocol); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+	}
+	if *pidName != "" {
+		def, ok := catalog.Lookup(*pidName)
+		if !ok {
+			log.Fatalf("query: no PID named %q in the catalog", *pidName)
+		}
+		if err := runReadPID(info, def, *protocol); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+	}
+	if *readMaps {
+		if err := runReadMaps(info, *protocol, *security); err != nil {
+			log.Fatalf("query: %v", err)
+		}
+	}
+	// -protocol auto with no action still reports what the vehicle
+	// speaks, which is the whole question on an older van.
+	if *protocol == "auto" && !*clearDTC && !*readiness && *pidName == "" && !*readMaps {
+		conn, err := openAdapter(info, *protocol)
+		if err != nil {
+			log.Fatalf("query: %v", err)
+		}
+		conn.Close()
+	}
+}
+
+// runDiscover scans for adapters and prints both what answered and the
+// config.yaml transports: entries that would use them.
+func runDiscover() error {
+	found, err := transport.Discover()
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Println("no adapters found")
+		return nil
+	}
+	for _, a := range found {
+		if a.Baud > 0 {
+			fmt.Printf("%s\t%s @ %d baud\n", a.Address, a.Banner, a.Baud)
+		} else {
+			fmt.Printf("%s\t%s\n", a.Address, a.Banner)
+		}
+	}
+	fmt.Println("\ntransports:")
+	for _, a := range found {
+		fmt.Printf("  - type: %s\n    interface: %s\n", a.Type, a.Address)
+	}
+	return nil
+}
+
+// trafficLog, when -traffic-log is set, receives every raw byte
+// exchanged with the adapter.
+var trafficLog *transport.TrafficLog
+
+// protoNumbers maps the -protocol flag values onto ELM ATSP arguments.
+var protoNumbers = map[string]string{
+	"can":      transport.ProtoCAN11At500,
+	"kwp":      transport.ProtoKWPFast,
+	"kwp-slow": transport.ProtoKWP2000,
+	"iso9141":  transport.ProtoISO9141,
+}
+
+// openAdapter opens the ELM and applies the -protocol selection; the
+// adapter handles framing, so CAN and K-line vehicles share the same
+// request path from here on.
+func openAdapter(info transport.PortInfo, proto string) (*transport.ELMConn, error) {
+	conn, err := transport.OpenELM(info.Name, info.Baud)
+	if err != nil {
+		return nil, err
+	}
+	if trafficLog != nil {
+		conn.LogTraffic(info.Name, trafficLog)
+	}
+	switch proto {
+	case "":
+		return conn, nil
+	case "auto":
+		n, err := conn.DetectProtocol()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		fmt.Printf("protocol: %s\n", transport.ProtocolName(n))
+		return conn, nil
+	}
+	num, ok := protoNumbers[proto]
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unknown -protocol %q (want can, kwp, kwp-slow, iso9141 or auto)", proto)
+	}
+	if err := conn.SetProtocol(num); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// runReadPID requests one catalog channel through the adapter and
+// prints the decoded value.
+func runReadPID(info transport.PortInfo, def obd.PIDDef, proto string) error {
+	conn, err := openAdapter(info, proto)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	req := def.Request()
+	cmd := ""
+	for _, b := range req {
+		cmd += fmt.Sprintf("%02X", b)
+	}
+	resp, err := conn.Command(cmd)
+	if err != nil {
+		return err
+	}
+	// The positive response echoes the request with 0x40 on the mode.
+	echo := []string{fmt.Sprintf("%02X", req[0]+0x40)}
+	for _, b := range req[1:] {
+		echo = append(echo, fmt.Sprintf("%02X", b))
+	}
+	data := elmPayload(resp, echo...)
+	if data == nil {
+		return fmt.Errorf("no response for %s: %s", def.Name, resp)
+	}
+	v, err := def.Decode(data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %.1f %s\n", def.Name, v, def.Unit)
+	return nil
+}
+
+// runReadiness prints the I/M readiness snapshot — handy the week
+// before an inspection.
+func runReadiness(info transport.PortInfo, proto string) error {
+	conn, err := openAdapter(info, proto)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := conn.Command("0101")
+	if err != nil {
+		return err
+	}
+	data := elmPayload(resp, "41", "01")
+	if data == nil {
+		return fmt.Errorf("no readiness response: %s", resp)
+	}
+	r, err := obd.DecodeReadiness(data)
+	if err != nil {
+		return err
+	}
+	mil := "off"
+	if r.MILOn {
+		mil = "ON"
+	}
+	fmt.Printf("MIL %s, %d stored code(s)\n", mil, r.DTCCount)
+	for _, m := range r.Monitors {
+		state := "complete"
+		if !m.Complete {
+			state = "NOT READY"
+		}
+		fmt.Printf("  %-24s %s\n", m.Name, state)
+	}
+	return nil
+}
+
+// elmPayload finds the response line echoing the given tokens and
+// returns the hex bytes after them.
+func elmPayload(resp string, echo ...string) []byte {
+	for _, line := range strings.Split(resp, "\r") {
+		tokens := strings.Fields(line)
+		if len(tokens) < len(echo) {
+			continue
+		}
+		match := true
+		for i, e := range echo {
+			if tokens[i] != e {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		var data []byte
+		for _, tok := range tokens[len(echo):] {
+			b, err := strconv.ParseUint(tok, 16, 8)
+			if err != nil {
+				break
+			}
+			data = append(data, byte(b))
+		}
+		return data
+	}
+	return nil
+}
+
+// runReadMaps switches the adapter into raw frame mode and pulls the
+// known calibration tables over UDS: extended session first, then an
+// optional SecurityAccess unlock before the memory reads.
+func runReadMaps(info transport.PortInfo, proto, security string) error {
+	conn, err := openAdapter(info, proto)
+	if err != nil {
+		return err
+	}
+	tr, err := transport.NewELMTransport(conn, info.Name)
+	if err != nil {
+		return err
+	}
+	defer tr.Close()
+	// Even a hand-driven tool goes through the TX policy: only the
+	// engine ECU's tester ID may ever hit the bus from here.
+	guarded := transport.Guard(tr, transport.TXPolicy{Allow: []uint32{uds.DefaultRequestID}})
+	c := uds.NewClient(guarded, 0, 0)
+	if err := c.DiagnosticSessionControl(uds.SessionExtended); err != nil {
+		return fmt.Errorf("extended session: %w", err)
+	}
+	if security != "" {
+		p, ok := uds.KeyAlgorithm(security)
+		if !ok {
+			return fmt.Errorf("unknown -security algorithm %q", security)
+		}
+		if err := c.Unlock(1, p); err != nil {
+			return fmt.Errorf("security unlock: %w", err)
+		}
+	}
+	maps, err := c.ReadEngineMaps()
+	if err != nil {
+		return err
+	}
+	for _, m := range maps {
+		fmt.Printf("%s (%s)\n", m.Name, m.Unit)
+		for _, row := range m.Values {
+			for _, v := range row {
+				fmt.Printf(" %8.2f", v)
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// runClearDTC sends the mode 04 clear through the adapter and confirms
+// the result by re-reading mode 03.
+func runClearDTC(info transport.PortInfo, proto string) error {
+	conn, err := openAdapter(info, proto)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := conn.Command("04")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(resp, "44") {
+		return fmt.Errorf("clear rejected: %s", resp)
+	}
+	reread, err := conn.Command("03")
+	if err != nil {
+		return err
+	}
+	codes := decodeELMDTCs(reread)
+	if len(codes) == 0 {
+		fmt.Println("trouble codes cleared; mode 03 re-read shows none stored")
+		return nil
+	}
+	fmt.Printf("cleared, but %d code(s) already back:\n", len(codes))
+	for _, info := range obd.DescribeAll(codes) {
+		fmt.Printf("  %s  [%s] %s\n", info.Code, info.Severity, info.Description)
+	}
+	return nil
+}
+
+// decodeELMDTCs parses a mode 03 response in the adapter's hex text
+// form, one "43 ..." line per answering ECU.
+func decodeELMDTCs(resp string) []string {
+	var codes []string
+	for _, line := range strings.Split(resp, "\r") {
+		tokens := strings.Fields(line)
+		if len(tokens) < 1 || tokens[0] != "43" {
+			continue
+		}
+		var data []byte
+		for _, tok := range tokens[1:] {
+			b, err := strconv.ParseUint(tok, 16, 8)
+			if err != nil {
+				break
+			}
+			data = append(data, byte(b))
+		}
+		codes = append(codes, obd.DecodeDTCs(data)...)
+	}
+	return codes
+}
+
+// adapter resolves the -port flag to a probed adapter.
+func adapter(port string) (transport.PortInfo, error) {
+	if port == "auto" {
+		return transport.AutoDetectELM327()
+	}
+	banner, baud, err := transport.IdentifyELM327(port)
+	if err != nil {
+		return transport.PortInfo{}, err
+	}
+	return transport.PortInfo{Name: port, ELM: banner, Baud: baud}, nil
+}
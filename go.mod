@@ -0,0 +1,26 @@
+module github.com/anodyne74/iload-obd2
+
+go 1.21
+
+require github.com/mattn/go-sqlite3 v1.14.22
+
+require golang.org/x/crypto v0.17.0
+
+require (
+	github.com/fxamacker/cbor/v2 v2.6.0
+	github.com/gorilla/websocket v1.5.1
+	github.com/grandcat/zeroconf v1.0.0
+	github.com/yuin/gopher-lua v1.1.1
+	go.bug.st/serial v1.6.2
+	golang.org/x/sys v0.15.0
+	google.golang.org/protobuf v1.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
+	github.com/creack/goselect v0.1.2 // indirect
+	github.com/miekg/dns v1.1.27 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.17.0 // indirect
+)
@@ -0,0 +1,210 @@
+// Package agent implements the offline-first edge mode: the daemon
+// records everything locally and uploads to a central instance whenever
+// connectivity appears, resuming from the last acknowledged batch.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	iloadpb "github.com/anodyne74/iload-obd2/proto/iload/v1"
+)
+
+const (
+	batchSize       = 500
+	telemetryCursor = "sync.telemetry"
+	alertCursor     = "sync.alerts"
+	captureCursor   = "sync.captures"
+	// captureBatch bounds how many session files travel in one request —
+	// they carry whole recordings, not rows.
+	captureBatch = 5
+)
+
+// Agent uploads locally recorded data to a central iload-obd2 instance.
+type Agent struct {
+	store    *datastore.SQLiteStore
+	central  string
+	token    string
+	interval time.Duration
+	client   *http.Client
+}
+
+// New builds an Agent syncing store to the central base URL.
+func New(store *datastore.SQLiteStore, central, token string, interval time.Duration) *Agent {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Agent{
+		store:    store,
+		central:  central,
+		token:    token,
+		interval: interval,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Run syncs in a loop until ctx is cancelled. Failures are logged and
+// retried on the next tick; cursors only advance after the central
+// instance acknowledges a batch, so transfers are resumable.
+func (a *Agent) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if err := a.syncOnce(ctx); err != nil {
+			log.Printf("agent: sync: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Agent) syncOnce(ctx context.Context) error {
+	if err := a.syncTelemetry(ctx); err != nil {
+		return err
+	}
+	if err := a.syncAlerts(ctx); err != nil {
+		return err
+	}
+	return a.syncCaptures(ctx)
+}
+
+func (a *Agent) syncTelemetry(ctx context.Context) error {
+	for {
+		cursor, err := a.store.SyncCursor(telemetryCursor)
+		if err != nil {
+			return err
+		}
+		batch, last, err := a.store.TelemetryAfter(cursor, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := a.post(ctx, "/api/v1/sync/telemetry", batch); err != nil {
+			return err
+		}
+		if err := a.store.SetSyncCursor(telemetryCursor, last); err != nil {
+			return err
+		}
+		log.Printf("agent: synced %d telemetry samples", len(batch))
+	}
+}
+
+func (a *Agent) syncAlerts(ctx context.Context) error {
+	for {
+		cursor, err := a.store.SyncCursor(alertCursor)
+		if err != nil {
+			return err
+		}
+		batch, err := a.store.AlertsAfter(cursor, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		// Alerts travel in the canonical protobuf wire form; the central
+		// endpoint still accepts JSON from older agents.
+		pb := &iloadpb.AlertBatch{Alerts: make([]*iloadpb.Alert, len(batch))}
+		for i, al := range batch {
+			pb.Alerts[i] = iloadpb.AlertToProto(al)
+		}
+		body, err := proto.Marshal(pb)
+		if err != nil {
+			return err
+		}
+		if err := a.postBody(ctx, "/api/v1/sync/alerts", "application/x-protobuf", body); err != nil {
+			return err
+		}
+		if err := a.store.SetSyncCursor(alertCursor, batch[len(batch)-1].ID); err != nil {
+			return err
+		}
+		log.Printf("agent: synced %d alerts", len(batch))
+	}
+}
+
+// syncCapture mirrors the central server's SyncCapture payload: the
+// registry record plus the session file's raw bytes.
+type syncCapture struct {
+	datastore.CaptureRecord
+	Data []byte `json:"data"`
+}
+
+// syncCaptures uploads capture session files registered since the last
+// acknowledged batch, content and registry record together, mirroring
+// the server's SyncCapture shape. A registered file that has since been
+// deleted locally is skipped, not retried forever.
+func (a *Agent) syncCaptures(ctx context.Context) error {
+	for {
+		cursor, err := a.store.SyncCursor(captureCursor)
+		if err != nil {
+			return err
+		}
+		recs, err := a.store.CapturesAfter(cursor, captureBatch)
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			return nil
+		}
+		batch := make([]syncCapture, 0, len(recs))
+		for _, rec := range recs {
+			data, err := os.ReadFile(rec.Path)
+			if err != nil {
+				log.Printf("agent: capture %s: %v (skipping)", rec.Path, err)
+				continue
+			}
+			batch = append(batch, syncCapture{CaptureRecord: rec, Data: data})
+		}
+		if len(batch) > 0 {
+			if err := a.post(ctx, "/api/v1/sync/captures", batch); err != nil {
+				return err
+			}
+			log.Printf("agent: synced %d capture sessions", len(batch))
+		}
+		if err := a.store.SetSyncCursor(captureCursor, recs[len(recs)-1].ID); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *Agent) post(ctx context.Context, path string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return a.postBody(ctx, path, "application/json", body)
+}
+
+func (a *Agent) postBody(ctx context.Context, path, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.central+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("central returned %s for %s", resp.Status, path)
+	}
+	return nil
+}
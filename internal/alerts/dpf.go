@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"log"
+	"sync"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/i18n"
+)
+
+// dpfRearmMargin re-arms the warning once a regen has brought the load
+// this far back under the threshold.
+const dpfRearmMargin = 10.0
+
+// SootWatcher runs the per-vehicle DPF soot model over ingested
+// telemetry, stamps the estimate onto each sample as the dpf_soot
+// channel, and raises an alert when the load crosses the warning
+// threshold.
+type SootWatcher struct {
+	store   datastore.Store
+	warnPct float64
+
+	mu     sync.Mutex
+	models map[string]*analysis.SootModel
+	warned map[string]bool
+}
+
+// NewSootWatcher warns when estimated DPF load crosses warnPct
+// (default 80).
+func NewSootWatcher(store datastore.Store, warnPct float64) *SootWatcher {
+	if warnPct <= 0 {
+		warnPct = 80
+	}
+	return &SootWatcher{
+		store:   store,
+		warnPct: warnPct,
+		models:  make(map[string]*analysis.SootModel),
+		warned:  make(map[string]bool),
+	}
+}
+
+// Enrich advances the vehicle's soot model and writes the estimate into
+// the sample; call before the sample is persisted.
+func (w *SootWatcher) Enrich(t *datastore.TelemetryData) {
+	w.mu.Lock()
+	m := w.models[t.VIN]
+	if m == nil {
+		m = &analysis.SootModel{}
+		w.models[t.VIN] = m
+	}
+	pct := m.Observe(*t)
+	t.DPFSootPct = pct
+
+	fire := false
+	switch {
+	case pct >= w.warnPct && !w.warned[t.VIN]:
+		w.warned[t.VIN] = true
+		fire = true
+	case pct < w.warnPct-dpfRearmMargin:
+		w.warned[t.VIN] = false
+	}
+	w.mu.Unlock()
+
+	if fire {
+		a := &datastore.Alert{
+			VIN:       t.VIN,
+			Type:      "dpf_soot",
+			Message:   i18n.T("alert.dpf", pct),
+			Value:     pct,
+			Threshold: w.warnPct,
+			Timestamp: t.Timestamp,
+		}
+		if err := w.store.SaveAlert(a); err != nil {
+			log.Printf("alerts: save dpf alert: %v", err)
+		}
+	}
+}
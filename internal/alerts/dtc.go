@@ -0,0 +1,151 @@
+// Package alerts watches the live telemetry flow for conditions worth
+// telling someone about.
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/i18n"
+	"github.com/anodyne74/iload-obd2/internal/obd"
+)
+
+// DTCEvent is the payload delivered when a trouble code first appears on
+// a vehicle. Workshops subscribe to this specific event rather than the
+// general alert firehose.
+type DTCEvent struct {
+	VIN         string                  `json:"vin"`
+	Code        string                  `json:"code"`
+	Description string                  `json:"description,omitempty"`
+	Severity    string                  `json:"severity,omitempty"`
+	FirstSeen   time.Time               `json:"first_seen"`
+	Telemetry   datastore.TelemetryData `json:"telemetry"`
+	// CaptureWindow links to the stored telemetry around the event.
+	CaptureWindow string `json:"capture_window,omitempty"`
+}
+
+// DTCWatcher fires a webhook the first time each DTC appears on a VIN.
+type DTCWatcher struct {
+	store      datastore.Store
+	webhookURL string
+	baseURL    string // public base URL used to build capture-window links
+	client     *http.Client
+
+	// OnDTC, when set, is called for each first appearance — the event
+	// recorder hooks in here to cut a capture around the code.
+	OnDTC func(vin, code string)
+
+	// FetchFreezeFrame, when set, pulls the ECU's mode 02 snapshot for a
+	// fresh code so the stored event carries the conditions at the
+	// moment of fault. Only wired where the daemon has the bus.
+	FetchFreezeFrame func() (*datastore.FreezeFrame, error)
+
+	mu    sync.Mutex
+	known map[string]map[string]bool // vin -> codes already reported
+}
+
+// NewDTCWatcher builds a watcher posting to webhookURL. baseURL, when
+// set, is used to build links back to this instance's data.
+func NewDTCWatcher(store datastore.Store, webhookURL, baseURL string) *DTCWatcher {
+	return &DTCWatcher{
+		store:      store,
+		webhookURL: webhookURL,
+		baseURL:    baseURL,
+		client:     &http.Client{Timeout: 15 * time.Second},
+		known:      make(map[string]map[string]bool),
+	}
+}
+
+// Observe inspects one telemetry sample and fires the webhook for any
+// code not seen before on this VIN.
+func (w *DTCWatcher) Observe(t datastore.TelemetryData) {
+	if len(t.DTCs) == 0 {
+		return
+	}
+	var fresh []string
+	w.mu.Lock()
+	codes := w.known[t.VIN]
+	if codes == nil {
+		codes = make(map[string]bool)
+		w.known[t.VIN] = codes
+	}
+	for _, code := range t.DTCs {
+		if !codes[code] {
+			codes[code] = true
+			fresh = append(fresh, code)
+		}
+	}
+	w.mu.Unlock()
+	for _, code := range fresh {
+		w.fire(code, t)
+	}
+}
+
+func (w *DTCWatcher) fire(code string, t datastore.TelemetryData) {
+	info := obd.Describe(code)
+	ev := DTCEvent{
+		VIN:         t.VIN,
+		Code:        code,
+		Description: info.Description,
+		Severity:    info.Severity,
+		FirstSeen:   t.Timestamp,
+		Telemetry:   t,
+	}
+	if w.baseURL != "" {
+		from := t.Timestamp.Add(-2 * time.Minute).Format(time.RFC3339)
+		to := t.Timestamp.Add(2 * time.Minute).Format(time.RFC3339)
+		ev.CaptureWindow = fmt.Sprintf("%s/api/v1/vehicles/%s/telemetry?from=%s&to=%s",
+			w.baseURL, t.VIN, url.QueryEscape(from), url.QueryEscape(to))
+	}
+	a := &datastore.Alert{
+		VIN:       t.VIN,
+		Type:      "dtc",
+		Message:   i18n.T("alert.dtc", code) + ": " + info.Description,
+		Timestamp: t.Timestamp,
+	}
+	if err := w.store.SaveAlert(a); err != nil {
+		log.Printf("alerts: save dtc alert: %v", err)
+	}
+	event := &datastore.DTCEvent{
+		VIN:       t.VIN,
+		Code:      code,
+		FirstSeen: t.Timestamp,
+	}
+	if w.FetchFreezeFrame != nil {
+		if ff, err := w.FetchFreezeFrame(); err != nil {
+			log.Printf("alerts: freeze frame for %s: %v", code, err)
+		} else {
+			event.FreezeFrame = ff
+		}
+	}
+	if err := w.store.SaveDTCEvent(event); err != nil {
+		log.Printf("alerts: save dtc event: %v", err)
+	}
+	if w.OnDTC != nil {
+		w.OnDTC(t.VIN, code)
+	}
+	if w.webhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("alerts: marshal dtc event: %v", err)
+		return
+	}
+	resp, err := w.client.Post(w.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("alerts: dtc webhook: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("alerts: dtc webhook returned %s", resp.Status)
+	}
+}
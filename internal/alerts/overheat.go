@@ -0,0 +1,126 @@
+package alerts
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/i18n"
+)
+
+const (
+	// overheatWindow is how much coolant history the trend is fitted
+	// over; shorter windows react faster but chase noise.
+	overheatWindow = 3 * time.Minute
+	// overheatCooldown suppresses repeat warnings for the same episode.
+	overheatCooldown = 15 * time.Minute
+	// minTrendPoints is the fewest samples a fit is trusted on.
+	minTrendPoints = 10
+)
+
+// OverheatPredictor fits the coolant temperature trend per vehicle and
+// raises an alert when the slope predicts crossing the threshold within
+// the horizon — typically minutes before the conventional over-temp
+// alert would fire.
+type OverheatPredictor struct {
+	store     datastore.Store
+	threshold float64       // °C
+	horizon   time.Duration // how far ahead a predicted crossing warns
+
+	mu        sync.Mutex
+	history   map[string][]trendPoint
+	lastAlert map[string]time.Time
+}
+
+type trendPoint struct {
+	at   time.Time
+	temp float64
+}
+
+// NewOverheatPredictor watches coolant trends against threshold °C.
+// Zero values fall back to 105°C and a 10 minute horizon.
+func NewOverheatPredictor(store datastore.Store, threshold float64, horizon time.Duration) *OverheatPredictor {
+	if threshold <= 0 {
+		threshold = 105
+	}
+	if horizon <= 0 {
+		horizon = 10 * time.Minute
+	}
+	return &OverheatPredictor{
+		store:     store,
+		threshold: threshold,
+		horizon:   horizon,
+		history:   make(map[string][]trendPoint),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// Observe feeds one sample into the per-vehicle trend.
+func (p *OverheatPredictor) Observe(t datastore.TelemetryData) {
+	if t.CoolantTemp <= 0 || t.RPM < 300 {
+		return // sensor not reporting, or engine off
+	}
+	p.mu.Lock()
+	hist := append(p.history[t.VIN], trendPoint{at: t.Timestamp, temp: t.CoolantTemp})
+	// Trim to the fit window.
+	cut := t.Timestamp.Add(-overheatWindow)
+	for len(hist) > 0 && hist[0].at.Before(cut) {
+		hist = hist[1:]
+	}
+	p.history[t.VIN] = hist
+	eta, ok := p.predict(hist)
+	fire := ok && t.CoolantTemp < p.threshold &&
+		t.Timestamp.Sub(p.lastAlert[t.VIN]) > overheatCooldown
+	if fire {
+		p.lastAlert[t.VIN] = t.Timestamp
+	}
+	p.mu.Unlock()
+
+	if fire {
+		a := &datastore.Alert{
+			VIN:  t.VIN,
+			Type: "overheat_predicted",
+			Message: i18n.T("alert.overheat",
+				p.threshold, eta.Minutes(), t.CoolantTemp),
+			Value:     t.CoolantTemp,
+			Threshold: p.threshold,
+			Timestamp: t.Timestamp,
+		}
+		if err := p.store.SaveAlert(a); err != nil {
+			log.Printf("alerts: save overheat alert: %v", err)
+		}
+	}
+}
+
+// predict fits a least-squares line through the window and returns the
+// time until the threshold is crossed, if that lands within the horizon.
+func (p *OverheatPredictor) predict(hist []trendPoint) (time.Duration, bool) {
+	if len(hist) < minTrendPoints {
+		return 0, false
+	}
+	t0 := hist[0].at
+	var sumX, sumY, sumXY, sumXX float64
+	for _, pt := range hist {
+		x := pt.at.Sub(t0).Seconds()
+		sumX += x
+		sumY += pt.temp
+		sumXY += x * pt.temp
+		sumXX += x * x
+	}
+	n := float64(len(hist))
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denom // °C per second
+	if slope <= 0 {
+		return 0, false
+	}
+	current := hist[len(hist)-1].temp
+	eta := time.Duration((p.threshold - current) / slope * float64(time.Second))
+	if eta <= 0 || eta > p.horizon {
+		return 0, false
+	}
+	return eta, true
+}
@@ -0,0 +1,54 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// stubStore records saved alerts; the embedded nil Store panics on
+// anything else, which is what we want in these tests.
+type stubStore struct {
+	datastore.Store
+	alerts []*datastore.Alert
+}
+
+func (s *stubStore) SaveAlert(a *datastore.Alert) error {
+	s.alerts = append(s.alerts, a)
+	return nil
+}
+
+func coolantRamp(p *OverheatPredictor, rise float64) {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 180; i++ {
+		p.Observe(datastore.TelemetryData{
+			VIN:         "KMFWB37HXTESTFIX0",
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+			RPM:         2500,
+			CoolantTemp: 90 + rise*float64(i),
+		})
+	}
+}
+
+func TestOverheatPredictorFiresOnRisingTrend(t *testing.T) {
+	store := &stubStore{}
+	p := NewOverheatPredictor(store, 105, 10*time.Minute)
+	// +0.05 °C/s: 99°C after three minutes, threshold ~2 min away.
+	coolantRamp(p, 0.05)
+	if len(store.alerts) != 1 {
+		t.Fatalf("alerts fired = %d, want 1 (cooldown should suppress repeats)", len(store.alerts))
+	}
+	if store.alerts[0].Type != "overheat_predicted" {
+		t.Fatalf("alert type = %q", store.alerts[0].Type)
+	}
+}
+
+func TestOverheatPredictorQuietWhenStable(t *testing.T) {
+	store := &stubStore{}
+	p := NewOverheatPredictor(store, 105, 10*time.Minute)
+	coolantRamp(p, 0) // steady 90°C
+	if len(store.alerts) != 0 {
+		t.Fatalf("stable engine raised %d alerts", len(store.alerts))
+	}
+}
@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Sea-level standard atmosphere, the reference all corrections scale to.
+const (
+	stdPressureKPa = 101.325
+	stdTempK       = 288.15
+)
+
+// pressureAtAltitude estimates barometric pressure from GPS altitude
+// using the standard atmosphere, for vehicles without PID 0x33.
+func pressureAtAltitude(metres float64) float64 {
+	return stdPressureKPa * math.Pow(1-2.25577e-5*metres, 5.25588)
+}
+
+// AirDensityRatio returns the sample's air density relative to sea-level
+// standard conditions. It prefers the barometric pressure PID, falls
+// back to GPS altitude, and uses ambient temperature when reported.
+// Returns 1 when the sample carries nothing usable.
+func AirDensityRatio(t datastore.TelemetryData) float64 {
+	p := t.BaroKPa
+	if p <= 0 && t.Location != nil && t.Location.Altitude > 0 {
+		p = pressureAtAltitude(t.Location.Altitude)
+	}
+	if p <= 0 {
+		p = stdPressureKPa
+	}
+	tempK := stdTempK
+	if t.AmbientTemp != 0 {
+		tempK = t.AmbientTemp + 273.15
+	}
+	return (p / stdPressureKPa) * (stdTempK / tempK)
+}
+
+// MeanDensityRatio averages the density ratio over a window; 1 when the
+// window is empty.
+func MeanDensityRatio(samples []datastore.TelemetryData) float64 {
+	if len(samples) == 0 {
+		return 1
+	}
+	var sum float64
+	for i := range samples {
+		sum += AirDensityRatio(samples[i])
+	}
+	return sum / float64(len(samples))
+}
+
+// CompensateEconomy scales a measured L/100km figure to sea-level
+// standard conditions, so a week in the mountains isn't scored as a bad
+// driver. Thin air costs a turbodiesel roughly in proportion to density;
+// half weight keeps the correction conservative.
+func CompensateEconomy(lPer100km float64, samples []datastore.TelemetryData) float64 {
+	ratio := MeanDensityRatio(samples)
+	correction := 1 + (1-ratio)*0.5
+	if correction <= 0 {
+		return lPer100km
+	}
+	return lPer100km / correction
+}
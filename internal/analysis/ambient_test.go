@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestAirDensityRatio(t *testing.T) {
+	// Nothing usable: no correction.
+	if got := AirDensityRatio(datastore.TelemetryData{}); got != 1 {
+		t.Fatalf("empty sample ratio = %v, want 1", got)
+	}
+	// Standard conditions reported explicitly: still ~1.
+	std := AirDensityRatio(datastore.TelemetryData{BaroKPa: 101.325, AmbientTemp: 15})
+	if math.Abs(std-1) > 0.001 {
+		t.Fatalf("standard conditions ratio = %v", std)
+	}
+	// 2000 m via GPS altitude: noticeably thinner air.
+	high := AirDensityRatio(datastore.TelemetryData{
+		AmbientTemp: 15,
+		Location:    &datastore.Location{Altitude: 2000},
+	})
+	if high > 0.85 || high < 0.7 {
+		t.Fatalf("2000 m ratio = %v, want ~0.78", high)
+	}
+	// Hot day at sea level is thinner than a cold one.
+	hot := AirDensityRatio(datastore.TelemetryData{BaroKPa: 101.325, AmbientTemp: 40})
+	cold := AirDensityRatio(datastore.TelemetryData{BaroKPa: 101.325, AmbientTemp: -5})
+	if hot >= cold {
+		t.Fatalf("hot %v should be thinner than cold %v", hot, cold)
+	}
+}
+
+func TestCompensateEconomyMountainWindow(t *testing.T) {
+	var samples []datastore.TelemetryData
+	for i := 0; i < 10; i++ {
+		samples = append(samples, datastore.TelemetryData{
+			AmbientTemp: 10,
+			Location:    &datastore.Location{Altitude: 1500},
+		})
+	}
+	got := CompensateEconomy(10, samples)
+	if got >= 10 {
+		t.Fatalf("mountain economy %v not reduced", got)
+	}
+	if got < 8.5 {
+		t.Fatalf("correction too aggressive: %v", got)
+	}
+	// Sea-level window is untouched.
+	if got := CompensateEconomy(10, nil); got != 10 {
+		t.Fatalf("sea-level economy changed: %v", got)
+	}
+}
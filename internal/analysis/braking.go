@@ -0,0 +1,73 @@
+package analysis
+
+import "github.com/anodyne74/iload-obd2/internal/datastore"
+
+const (
+	// kerbMassKg is the unladen iLoad; scaled by the window's mass
+	// ratio when the load heuristic produced one.
+	kerbMassKg = 2100.0
+	// brakeDecel is the deceleration magnitude treated as braking when
+	// no brake switch signal is decoded.
+	brakeDecel = 1.5 // m/s²
+	// Coasting band: decelerating on engine braking and rolling
+	// resistance alone.
+	coastDecelMin = 0.2
+	coastDecelMax = 1.2
+)
+
+// BrakeReport summarises braking and coasting behaviour over a window.
+type BrakeReport struct {
+	BrakeEvents int     `json:"brake_events"`
+	BrakeSec    float64 `json:"brake_sec"`
+	// WastedKJ is kinetic energy scrubbed off with the brakes — energy
+	// that anticipation could have spent coasting instead.
+	WastedKJ float64 `json:"wasted_kj"`
+	CoastSec float64 `json:"coast_sec"`
+	// CoastPct is the share of deceleration time spent coasting rather
+	// than braking; higher means smoother, cheaper driving.
+	CoastPct float64 `json:"coast_pct"`
+	// SwitchDecoded is true when the brake light switch was available,
+	// making the split exact rather than inferred from deceleration.
+	SwitchDecoded bool `json:"switch_decoded"`
+}
+
+// AnalyzeBraking classifies every deceleration in the window as braking
+// or coasting and integrates the energy lost to the brakes.
+func AnalyzeBraking(samples []datastore.TelemetryData) *BrakeReport {
+	rep := &BrakeReport{}
+	mass := kerbMassKg
+	if mr := AnalyzeLoad(samples).MassRatio; mr > 1 {
+		mass *= mr
+	}
+	braking := false
+	for i := 1; i < len(samples); i++ {
+		t, prev := samples[i], samples[i-1]
+		dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 || dt > maxSampleGap {
+			braking = false
+			continue
+		}
+		decel := (prev.Speed - t.Speed) / 3.6 / dt // m/s², positive slowing
+		if t.Brake {
+			rep.SwitchDecoded = true
+		}
+		isBraking := t.Brake || decel >= brakeDecel
+		switch {
+		case isBraking && decel > 0:
+			rep.BrakeSec += dt
+			if !braking {
+				rep.BrakeEvents++
+			}
+			v1 := prev.Speed / 3.6
+			v2 := t.Speed / 3.6
+			rep.WastedKJ += 0.5 * mass * (v1*v1 - v2*v2) / 1000
+		case decel >= coastDecelMin && decel <= coastDecelMax && t.ThrottlePos < 5:
+			rep.CoastSec += dt
+		}
+		braking = isBraking
+	}
+	if total := rep.BrakeSec + rep.CoastSec; total > 0 {
+		rep.CoastPct = rep.CoastSec / total * 100
+	}
+	return rep
+}
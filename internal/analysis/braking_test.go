@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// speedProfile builds one sample per second following the given speeds.
+func speedProfile(speeds []float64) []datastore.TelemetryData {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	out := make([]datastore.TelemetryData, len(speeds))
+	for i, v := range speeds {
+		out[i] = datastore.TelemetryData{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			RPM:       1800,
+			Speed:     v,
+		}
+	}
+	return out
+}
+
+func TestAnalyzeBrakingHardStop(t *testing.T) {
+	// 60 km/h to rest in two seconds: unambiguous braking.
+	rep := AnalyzeBraking(speedProfile([]float64{60, 60, 30, 0, 0}))
+	if rep.BrakeEvents != 1 {
+		t.Fatalf("brake events = %d, want 1", rep.BrakeEvents)
+	}
+	// Full kinetic energy at 60 km/h and kerb mass: ½·2100·16.67² ≈ 292 kJ.
+	if math.Abs(rep.WastedKJ-292) > 5 {
+		t.Fatalf("wasted energy = %.0f kJ, want ~292", rep.WastedKJ)
+	}
+}
+
+func TestAnalyzeBrakingCoastingShare(t *testing.T) {
+	// Gentle 1 km/h per second roll-down from 60: all coasting.
+	speeds := make([]float64, 40)
+	for i := range speeds {
+		speeds[i] = 60 - float64(i)
+	}
+	rep := AnalyzeBraking(speedProfile(speeds))
+	if rep.BrakeEvents != 0 {
+		t.Fatalf("coast-down counted %d brake events", rep.BrakeEvents)
+	}
+	if rep.CoastPct != 100 {
+		t.Fatalf("coast share = %.0f%%, want 100", rep.CoastPct)
+	}
+}
+
+func TestAnalyzeBrakingUsesSwitchWhenDecoded(t *testing.T) {
+	samples := speedProfile([]float64{50, 49, 48, 47})
+	for i := 1; i < len(samples); i++ {
+		samples[i].Brake = true // light braking below the decel threshold
+	}
+	rep := AnalyzeBraking(samples)
+	if !rep.SwitchDecoded {
+		t.Fatal("brake switch not recognised")
+	}
+	if rep.BrakeSec != 3 {
+		t.Fatalf("brake time = %.0fs, want 3", rep.BrakeSec)
+	}
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	// crankRPMMax is the ceiling of starter-motor speed; above it the
+	// engine has caught.
+	crankRPMMax = 600.0
+	// weakCrankVolts is the minimum cranking voltage below which the
+	// battery (or starter draw) is suspect. A healthy system stays
+	// above 10V even on a cold morning.
+	weakCrankVolts = 9.6
+	// maxCrankSec is the longest plausible crank; anything beyond it is
+	// treated as a recording artefact, not one start event.
+	maxCrankSec = 15.0
+)
+
+// CrankEvent is one engine start as seen in the voltage and RPM traces.
+type CrankEvent struct {
+	At          time.Time `json:"at"`
+	MinVoltage  float64   `json:"min_voltage"`
+	DurationSec float64   `json:"duration_sec"`
+	ColdStart   bool      `json:"cold_start"`
+}
+
+// CrankReport trends start events over a window. Falling minimum
+// voltage or stretching crank times are the warning signs before a
+// no-start morning.
+type CrankReport struct {
+	Events []CrankEvent `json:"events"`
+	// MinVoltageTrend is volts per start event from a least-squares fit
+	// across the window; meaningfully negative means a weakening
+	// battery.
+	MinVoltageTrend float64 `json:"min_voltage_trend"`
+	// WeakStarts counts events that dipped below the healthy floor.
+	WeakStarts int `json:"weak_starts"`
+}
+
+// AnalyzeCranking extracts start events from a telemetry window and
+// trends their minimum voltage.
+func AnalyzeCranking(samples []datastore.TelemetryData) *CrankReport {
+	rep := &CrankReport{}
+	var ev *CrankEvent
+	var crankStart time.Time
+	for i := range samples {
+		t := samples[i]
+		cranking := t.RPM > 0 && t.RPM < crankRPMMax && t.Voltage > 0
+		switch {
+		case cranking && ev == nil:
+			crankStart = t.Timestamp
+			ev = &CrankEvent{
+				At:         t.Timestamp,
+				MinVoltage: t.Voltage,
+				ColdStart:  t.CoolantTemp < coldStartCoolant,
+			}
+		case cranking:
+			if t.Voltage < ev.MinVoltage {
+				ev.MinVoltage = t.Voltage
+			}
+		case ev != nil:
+			ev.DurationSec = t.Timestamp.Sub(crankStart).Seconds()
+			if t.RPM >= crankRPMMax && ev.DurationSec <= maxCrankSec {
+				rep.Events = append(rep.Events, *ev)
+				if ev.MinVoltage < weakCrankVolts {
+					rep.WeakStarts++
+				}
+			}
+			ev = nil
+		}
+	}
+	rep.MinVoltageTrend = voltageTrend(rep.Events)
+	return rep
+}
+
+// voltageTrend fits volts against event index; per-event slope keeps the
+// figure meaningful whether the window is a week or a season.
+func voltageTrend(events []CrankEvent) float64 {
+	if len(events) < 3 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, e := range events {
+		x := float64(i)
+		sumX += x
+		sumY += e.MinVoltage
+		sumXY += x * e.MinVoltage
+		sumXX += x * x
+	}
+	n := float64(len(events))
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// startEvent appends one off→crank→run sequence with the given minimum
+// cranking voltage.
+func startEvent(samples []datastore.TelemetryData, at time.Time, minVolts float64) []datastore.TelemetryData {
+	samples = append(samples, datastore.TelemetryData{Timestamp: at, RPM: 0, Voltage: 12.6, CoolantTemp: 15})
+	for i := 0; i < 3; i++ {
+		samples = append(samples, datastore.TelemetryData{
+			Timestamp:   at.Add(time.Duration(i+1) * time.Second),
+			RPM:         250,
+			Voltage:     minVolts + float64(i)*0.3, // dip then recover
+			CoolantTemp: 15,
+		})
+	}
+	return append(samples, datastore.TelemetryData{
+		Timestamp: at.Add(5 * time.Second), RPM: 850, Voltage: 14.1, CoolantTemp: 15,
+	})
+}
+
+func TestAnalyzeCrankingExtractsEvents(t *testing.T) {
+	start := time.Date(2024, 3, 14, 7, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	for day := 0; day < 4; day++ {
+		samples = startEvent(samples, start.AddDate(0, 0, day), 10.8)
+	}
+	rep := AnalyzeCranking(samples)
+	if len(rep.Events) != 4 {
+		t.Fatalf("events = %d, want 4", len(rep.Events))
+	}
+	if rep.WeakStarts != 0 {
+		t.Fatalf("healthy starts flagged weak: %d", rep.WeakStarts)
+	}
+	if e := rep.Events[0]; e.MinVoltage != 10.8 || e.DurationSec < 3 {
+		t.Fatalf("first event = %+v", e)
+	}
+}
+
+func TestAnalyzeCrankingDetectsDecline(t *testing.T) {
+	start := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	// Two weeks of starts losing 0.15V a day.
+	for day := 0; day < 14; day++ {
+		samples = startEvent(samples, start.AddDate(0, 0, day), 11.0-0.15*float64(day))
+	}
+	rep := AnalyzeCranking(samples)
+	if rep.MinVoltageTrend > -0.14 {
+		t.Fatalf("trend = %.3f V/start, want ~-0.15", rep.MinVoltageTrend)
+	}
+	if rep.WeakStarts == 0 {
+		t.Fatal("late weak starts not counted")
+	}
+}
@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	// Fallback MAF-based estimate, matching the rollup job's model.
+	dieselAFR     = 14.5
+	dieselDensity = 832.0 // g/L
+	// maxSampleGap ignores integration across recording gaps.
+	maxSampleGap = 60.0 // seconds
+	// loadBucketPct is the engine-load bucket width in the fuel map.
+	loadBucketPct = 10
+)
+
+// FuelRateLph returns the sample's fuel consumption in L/h, preferring
+// the measured rate (PID 0x5E) and falling back to the MAF estimate.
+func FuelRateLph(t datastore.TelemetryData) float64 {
+	if t.FuelRate > 0 {
+		return t.FuelRate
+	}
+	// g/s air → g/s fuel → L/h.
+	return t.MAF / dieselAFR / dieselDensity * 3600
+}
+
+// LoadBucket is one cell of the load-vs-fuel map.
+type LoadBucket struct {
+	LoadFrom int     `json:"load_from"` // percent, inclusive
+	LoadTo   int     `json:"load_to"`   // percent, exclusive
+	Seconds  float64 `json:"seconds"`
+	FuelL    float64 `json:"fuel_l"`
+	AvgLph   float64 `json:"avg_lph"` // average L/h while in this bucket
+	Samples  int     `json:"samples"`
+	sumRate  float64
+}
+
+// FuelReport summarises fuel usage over a telemetry window.
+type FuelReport struct {
+	TotalFuelL float64 `json:"total_fuel_l"`
+	// IdleFuelL is fuel burnt while stationary with the engine running —
+	// the cheapest saving there is.
+	IdleFuelL float64 `json:"idle_fuel_l"`
+	// Measured is true when the window had the fuel-rate PID rather
+	// than the MAF estimate.
+	Measured bool         `json:"measured"`
+	LoadMap  []LoadBucket `json:"load_map,omitempty"`
+}
+
+// AnalyzeFuel integrates fuel usage over the window and builds the
+// load-vs-fuel map.
+func AnalyzeFuel(samples []datastore.TelemetryData) *FuelReport {
+	rep := &FuelReport{}
+	buckets := map[int]*LoadBucket{}
+	for i := 1; i < len(samples); i++ {
+		t, prev := samples[i], samples[i-1]
+		dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 || dt > maxSampleGap {
+			continue
+		}
+		rate := (FuelRateLph(t) + FuelRateLph(prev)) / 2
+		fuel := rate * dt / 3600
+		rep.TotalFuelL += fuel
+		if t.FuelRate > 0 {
+			rep.Measured = true
+		}
+		if t.RPM > 300 && t.Speed < 2 {
+			rep.IdleFuelL += fuel
+		}
+
+		key := int(t.EngineLoad) / loadBucketPct
+		b, ok := buckets[key]
+		if !ok {
+			b = &LoadBucket{LoadFrom: key * loadBucketPct, LoadTo: (key + 1) * loadBucketPct}
+			buckets[key] = b
+		}
+		b.Seconds += dt
+		b.FuelL += fuel
+		b.sumRate += rate
+		b.Samples++
+	}
+	for _, b := range buckets {
+		if b.Samples > 0 {
+			b.AvgLph = b.sumRate / float64(b.Samples)
+		}
+		rep.LoadMap = append(rep.LoadMap, *b)
+	}
+	sort.Slice(rep.LoadMap, func(i, j int) bool {
+		return rep.LoadMap[i].LoadFrom < rep.LoadMap[j].LoadFrom
+	})
+	return rep
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestAnalyzeFuelMeasuredRate(t *testing.T) {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	// 10 minutes at a steady 6 L/h, idling for the first minute.
+	for i := 0; i <= 600; i++ {
+		s := datastore.TelemetryData{
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			RPM:        800,
+			FuelRate:   6,
+			EngineLoad: 25,
+		}
+		if i >= 60 {
+			s.Speed = 60
+			s.RPM = 2000
+		}
+		samples = append(samples, s)
+	}
+	rep := AnalyzeFuel(samples)
+	if !rep.Measured {
+		t.Fatal("measured rate not recognised")
+	}
+	if math.Abs(rep.TotalFuelL-1.0) > 0.01 {
+		t.Fatalf("total fuel = %.3f L, want ~1.0", rep.TotalFuelL)
+	}
+	if math.Abs(rep.IdleFuelL-0.1) > 0.01 {
+		t.Fatalf("idle fuel = %.3f L, want ~0.1", rep.IdleFuelL)
+	}
+	if len(rep.LoadMap) != 1 || rep.LoadMap[0].LoadFrom != 20 {
+		t.Fatalf("load map = %+v, want single 20-30%% bucket", rep.LoadMap)
+	}
+	if math.Abs(rep.LoadMap[0].AvgLph-6) > 0.01 {
+		t.Fatalf("bucket avg = %.2f L/h, want 6", rep.LoadMap[0].AvgLph)
+	}
+}
+
+func TestFuelRateFallsBackToMAF(t *testing.T) {
+	// 14.5 g/s of air ≈ 1 g/s of fuel ≈ 4.33 L/h.
+	got := FuelRateLph(datastore.TelemetryData{MAF: 14.5})
+	want := 1.0 / dieselDensity * 3600
+	if math.Abs(got-want) > 0.001 {
+		t.Fatalf("fallback rate = %.3f, want %.3f", got, want)
+	}
+	if got := FuelRateLph(datastore.TelemetryData{MAF: 14.5, FuelRate: 5}); got != 5 {
+		t.Fatalf("measured rate not preferred: %v", got)
+	}
+}
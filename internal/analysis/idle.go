@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	// minIdlePhaseSamples is the shortest stretch counted as an idle
+	// phase; brief stops at junctions say nothing about idle quality.
+	minIdlePhaseSamples = 10
+	// steadyIdleStdDev is the RPM standard deviation of a healthy warm
+	// idle; scatter beyond it costs score.
+	steadyIdleStdDev = 15.0
+)
+
+// IdleReport scores idle smoothness over a telemetry window. A falling
+// score week on week points at deteriorating injectors or engine mounts
+// before they become a breakdown.
+type IdleReport struct {
+	Phases      int     `json:"phases"`
+	IdleSeconds float64 `json:"idle_seconds"`
+	// RPMStdDev is the duration-weighted average RPM standard deviation
+	// across idle phases.
+	RPMStdDev float64 `json:"rpm_std_dev"`
+	// Misfires counts misfire increments observed while idling.
+	Misfires int `json:"misfires"`
+	// Score is 0–100; a healthy engine idles in the high 90s.
+	Score float64 `json:"score"`
+}
+
+func isIdle(t datastore.TelemetryData) bool {
+	return t.RPM > 300 && t.Speed < 2 && t.ThrottlePos < 5
+}
+
+// AnalyzeIdle detects idle phases and scores their smoothness.
+func AnalyzeIdle(samples []datastore.TelemetryData) *IdleReport {
+	rep := &IdleReport{}
+	var phase []datastore.TelemetryData
+	flush := func() {
+		if len(phase) >= minIdlePhaseSamples {
+			rep.Phases++
+			dur := phase[len(phase)-1].Timestamp.Sub(phase[0].Timestamp).Seconds()
+			rep.IdleSeconds += dur
+			rep.RPMStdDev += rpmStdDev(phase) * dur
+			rep.Misfires += phase[len(phase)-1].Misfires - phase[0].Misfires
+		}
+		phase = phase[:0]
+	}
+	for _, t := range samples {
+		if isIdle(t) {
+			phase = append(phase, t)
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	if rep.IdleSeconds > 0 {
+		rep.RPMStdDev /= rep.IdleSeconds
+		rep.Score = idleScore(rep.RPMStdDev, float64(rep.Misfires), rep.IdleSeconds)
+	}
+	return rep
+}
+
+func rpmStdDev(phase []datastore.TelemetryData) float64 {
+	var sum float64
+	for _, t := range phase {
+		sum += t.RPM
+	}
+	mean := sum / float64(len(phase))
+	var sq float64
+	for _, t := range phase {
+		sq += (t.RPM - mean) * (t.RPM - mean)
+	}
+	return math.Sqrt(sq / float64(len(phase)))
+}
+
+func idleScore(stdDev, misfires, idleSec float64) float64 {
+	score := 100.0
+	if stdDev > steadyIdleStdDev {
+		score -= (stdDev - steadyIdleStdDev) * 1.5
+	}
+	// Normalise misfires to a per-minute rate so long windows aren't
+	// punished for accumulating the occasional count.
+	score -= misfires / idleSec * 60 * 10
+	return math.Max(0, math.Min(100, score))
+}
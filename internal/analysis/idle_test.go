@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/fixtures"
+)
+
+func idleSamples(n int, rpm func(i int) float64, misfires func(i int) int) []datastore.TelemetryData {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	out := make([]datastore.TelemetryData, n)
+	for i := range out {
+		out[i] = datastore.TelemetryData{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			RPM:       rpm(i),
+			Misfires:  misfires(i),
+		}
+	}
+	return out
+}
+
+func TestAnalyzeIdleSmoothEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rep := AnalyzeIdle(idleSamples(300,
+		func(i int) float64 { return 780 + rng.Float64()*10 },
+		func(i int) int { return 0 },
+	))
+	if rep.Phases != 1 {
+		t.Fatalf("phases = %d, want 1", rep.Phases)
+	}
+	if rep.Score < 95 {
+		t.Fatalf("smooth idle scored %.1f, want ≥95", rep.Score)
+	}
+}
+
+func TestAnalyzeIdleRoughEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	rep := AnalyzeIdle(idleSamples(300,
+		func(i int) float64 { return 750 + rng.Float64()*200 }, // hunting idle
+		func(i int) int { return i / 30 },                      // steady misfiring
+	))
+	if rep.Score > 60 {
+		t.Fatalf("rough idle scored %.1f, want well below a healthy engine", rep.Score)
+	}
+	if rep.Misfires != 9 {
+		t.Fatalf("misfires = %d, want 9", rep.Misfires)
+	}
+}
+
+func TestAnalyzeIdleGoldenFixture(t *testing.T) {
+	// The golden idle trace is two minutes stationary with a gentle RPM
+	// dither — it must read as a single, healthy idle phase.
+	rep := AnalyzeIdle(fixtures.Samples(fixtures.Idle()))
+	if rep.Phases != 1 {
+		t.Fatalf("phases = %d, want 1", rep.Phases)
+	}
+	if rep.Score < 90 {
+		t.Fatalf("golden idle scored %.1f, want ≥90", rep.Score)
+	}
+}
+
+func TestAnalyzeIdleIgnoresShortStops(t *testing.T) {
+	samples := idleSamples(300, func(i int) float64 { return 800 }, func(i int) int { return 0 })
+	for i := range samples {
+		if i%10 != 0 { // only every tenth sample is stationary
+			samples[i].Speed = 50
+		}
+	}
+	rep := AnalyzeIdle(samples)
+	if rep.Phases != 0 {
+		t.Fatalf("phases = %d, want 0 for junction-length stops", rep.Phases)
+	}
+}
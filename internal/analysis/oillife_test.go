@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestAnalyzeOilLifeFreshOil(t *testing.T) {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	// One warm hour at 60 km/h: barely touches the interval.
+	for i := 0; i <= 3600; i++ {
+		samples = append(samples, datastore.TelemetryData{
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+			RPM:         2000,
+			Speed:       60,
+			CoolantTemp: 88,
+			EngineLoad:  35,
+		})
+	}
+	rep := AnalyzeOilLife(samples)
+	if rep.LifePct < 99 {
+		t.Fatalf("life after one gentle hour = %.1f%%, want ~100", rep.LifePct)
+	}
+	if rep.EngineHours < 0.9 || rep.EngineHours > 1.1 {
+		t.Fatalf("engine hours = %.2f, want ~1", rep.EngineHours)
+	}
+	if rep.DistanceKm < 59 || rep.DistanceKm > 61 {
+		t.Fatalf("distance = %.1f km, want ~60", rep.DistanceKm)
+	}
+}
+
+func TestAnalyzeOilLifeCountsColdStarts(t *testing.T) {
+	start := time.Date(2024, 3, 14, 6, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	// Three cold mornings: engine off, then a cold start and short run.
+	for day := 0; day < 3; day++ {
+		base := start.AddDate(0, 0, day)
+		samples = append(samples, datastore.TelemetryData{Timestamp: base, RPM: 0, CoolantTemp: 8})
+		for i := 1; i <= 30; i++ {
+			samples = append(samples, datastore.TelemetryData{
+				Timestamp:   base.Add(time.Duration(i) * time.Second),
+				RPM:         900,
+				CoolantTemp: 8 + float64(i),
+			})
+		}
+	}
+	rep := AnalyzeOilLife(samples)
+	if rep.ColdStarts != 3 {
+		t.Fatalf("cold starts = %d, want 3", rep.ColdStarts)
+	}
+	if rep.EquivalentHours < 1.5 {
+		t.Fatalf("equivalent hours = %.2f, want cold-start penalty applied", rep.EquivalentHours)
+	}
+}
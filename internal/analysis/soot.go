@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	// sootCapacityG is the soot mass treated as a 100% loaded DPF.
+	sootCapacityG = 40.0
+	// sootPerLitreG is soot produced per litre of diesel at normal
+	// operating load.
+	sootPerLitreG = 1.2
+	// lowLoadSootFactor scales production when the engine is cold or
+	// barely loaded — incomplete combustion soots the filter faster.
+	lowLoadSootFactor = 1.5
+	// regenBurnGPerSec is the soot oxidation rate during an active
+	// regeneration.
+	regenBurnGPerSec = 0.15
+	// Regen signature: elevated exhaust temperature (coolant as proxy)
+	// while rolling — the ECU won't regen stationary.
+	regenMinCoolant = 100.0
+	regenMinSpeed   = 30.0
+)
+
+// SootModel maintains a per-vehicle estimate of DPF soot loading from
+// fuel burn, engine load and detected regenerations. It's a trend model,
+// not a measurement: good enough to warn that a van stuck on short urban
+// runs needs a motorway drive.
+type SootModel struct {
+	SootG       float64 `json:"soot_g"`
+	RegenActive bool    `json:"regen_active"`
+	Regens      int     `json:"regens"` // completed regenerations observed
+
+	lastAt time.Time
+}
+
+// LoadPct is the estimated DPF load as a percentage of capacity.
+func (m *SootModel) LoadPct() float64 {
+	return m.SootG / sootCapacityG * 100
+}
+
+// Observe advances the model by one telemetry sample and returns the
+// updated load percentage.
+func (m *SootModel) Observe(t datastore.TelemetryData) float64 {
+	if !m.lastAt.IsZero() {
+		dt := t.Timestamp.Sub(m.lastAt).Seconds()
+		if dt > 0 && dt <= maxSampleGap && t.RPM > 300 {
+			regen := t.CoolantTemp >= regenMinCoolant && t.Speed >= regenMinSpeed
+			if m.RegenActive && !regen {
+				m.Regens++
+			}
+			m.RegenActive = regen
+
+			fuelLps := FuelRateLph(t) / 3600
+			rate := fuelLps * sootPerLitreG
+			if t.EngineLoad < 30 {
+				rate *= lowLoadSootFactor
+			}
+			m.SootG += rate * dt
+			if regen {
+				m.SootG -= regenBurnGPerSec * dt
+			}
+			if m.SootG < 0 {
+				m.SootG = 0
+			}
+			if m.SootG > sootCapacityG {
+				m.SootG = sootCapacityG
+			}
+		}
+	}
+	m.lastAt = t.Timestamp
+	return m.LoadPct()
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestSootModelAccumulatesOnShortRuns(t *testing.T) {
+	m := &SootModel{}
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	// An hour of low-load urban crawling at 4 L/h.
+	for i := 0; i <= 3600; i++ {
+		m.Observe(datastore.TelemetryData{
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+			RPM:         1200,
+			Speed:       15,
+			FuelRate:    4,
+			EngineLoad:  20,
+			CoolantTemp: 85,
+		})
+	}
+	// 4 L × 1.2 g/L × 1.5 low-load factor = 7.2 g ≈ 18% of capacity.
+	if m.SootG < 6.5 || m.SootG > 8 {
+		t.Fatalf("soot after urban hour = %.1f g, want ~7.2", m.SootG)
+	}
+	if m.RegenActive || m.Regens != 0 {
+		t.Fatalf("urban crawl triggered a regen: %+v", m)
+	}
+}
+
+func TestSootModelRegenBurnsLoad(t *testing.T) {
+	m := &SootModel{SootG: 30}
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	// Ten minutes at motorway speed with regen-level temperatures.
+	for i := 0; i <= 600; i++ {
+		m.Observe(datastore.TelemetryData{
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+			RPM:         2200,
+			Speed:       100,
+			FuelRate:    9,
+			EngineLoad:  45,
+			CoolantTemp: 110,
+		})
+	}
+	if m.SootG > 5 {
+		t.Fatalf("soot after regen = %.1f g, want mostly burnt off", m.SootG)
+	}
+	// Cooling back down completes the regen.
+	m.Observe(datastore.TelemetryData{
+		Timestamp:   start.Add(11 * time.Minute),
+		RPM:         2000,
+		Speed:       100,
+		FuelRate:    8,
+		EngineLoad:  40,
+		CoolantTemp: 92,
+	})
+	if m.Regens != 1 {
+		t.Fatalf("regens = %d, want 1", m.Regens)
+	}
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import "github.com/anodyne74/iload-obd2/internal/datastore"
+
+const (
+	// unladenLoadPerAccel is the engine load (%) an empty iLoad needs
+	// per m/s² of acceleration in the usable band, from unladen
+	// reference logs. The load-vs-acceleration ratio scales roughly
+	// with gross mass.
+	unladenLoadPerAccel = 35.0
+	// ladenRatio above which the window is called heavily loaded or
+	// towing; ~40% more load per unit acceleration is close to a tonne
+	// in the back or a braked trailer.
+	ladenRatio = 1.4
+	// Acceleration band where the mass estimate works: hard enough to
+	// be signal, gentle enough that the pedal isn't on the floor.
+	minAccel = 0.3 // m/s²
+	maxAccel = 1.5
+	// highLoadPct counts toward sustained high-load operation.
+	highLoadPct = 85.0
+	// sustainedHighLoadSec flags the window for shortened service
+	// intervals.
+	sustainedHighLoadSec = 300.0
+)
+
+// LoadReport estimates how heavily the vehicle was loaded over a window.
+type LoadReport struct {
+	// LoadPerAccel is the measured engine load per m/s² in the usable
+	// acceleration band; 0 when the window had no usable accelerations.
+	LoadPerAccel float64 `json:"load_per_accel"`
+	// MassRatio is LoadPerAccel relative to the unladen reference.
+	MassRatio float64 `json:"mass_ratio"`
+	// Laden is true when the mass ratio indicates heavy load or towing.
+	Laden bool `json:"laden"`
+	// HighLoadSec is time spent above 85% engine load.
+	HighLoadSec float64 `json:"high_load_sec"`
+	// SustainedHighLoad flags enough accumulated high-load running to
+	// justify a shortened service interval.
+	SustainedHighLoad bool `json:"sustained_high_load"`
+}
+
+// AnalyzeLoad runs the load/towing heuristic over a telemetry window.
+func AnalyzeLoad(samples []datastore.TelemetryData) *LoadReport {
+	rep := &LoadReport{}
+	var ratioSum float64
+	var ratioN int
+	for i := 1; i < len(samples); i++ {
+		t, prev := samples[i], samples[i-1]
+		dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 || dt > maxSampleGap {
+			continue
+		}
+		if t.EngineLoad >= highLoadPct && t.RPM > 300 {
+			rep.HighLoadSec += dt
+		}
+		accel := (t.Speed - prev.Speed) / 3.6 / dt // m/s²
+		// Only steady accelerations at road speed: pull-aways and
+		// braking tell us about the clutch and brakes, not the mass.
+		if accel < minAccel || accel > maxAccel || t.Speed < 20 || t.Speed > 80 {
+			continue
+		}
+		ratioSum += t.EngineLoad / accel
+		ratioN++
+	}
+	if ratioN >= 10 {
+		rep.LoadPerAccel = ratioSum / float64(ratioN)
+		rep.MassRatio = rep.LoadPerAccel / unladenLoadPerAccel
+		rep.Laden = rep.MassRatio >= ladenRatio
+	}
+	rep.SustainedHighLoad = rep.HighLoadSec >= sustainedHighLoadSec
+	return rep
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// accelRun builds a window of repeated 40→60 km/h accelerations at the
+// given engine load, with cruise stretches between.
+func accelRun(loadPct float64) []datastore.TelemetryData {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	var out []datastore.TelemetryData
+	speed := 40.0
+	for i := 0; i < 600; i++ {
+		s := datastore.TelemetryData{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			RPM:       2000,
+			Speed:     speed,
+		}
+		if i%60 < 10 { // accelerate ~0.55 m/s² for ten seconds
+			speed += 2
+			s.EngineLoad = loadPct
+		} else {
+			s.EngineLoad = 30
+			if speed > 40 {
+				speed = 40
+			}
+		}
+		s.Speed = speed
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestAnalyzeLoadUnladen(t *testing.T) {
+	rep := AnalyzeLoad(accelRun(20)) // ~36%/m/s², close to reference
+	if rep.Laden {
+		t.Fatalf("unladen run flagged laden: %+v", rep)
+	}
+	if rep.LoadPerAccel == 0 {
+		t.Fatal("no usable accelerations found")
+	}
+}
+
+func TestAnalyzeLoadTowing(t *testing.T) {
+	rep := AnalyzeLoad(accelRun(35)) // much more load for the same accel
+	if !rep.Laden {
+		t.Fatalf("towing run not flagged: mass ratio %.2f", rep.MassRatio)
+	}
+}
+
+func TestAnalyzeLoadSustainedHighLoad(t *testing.T) {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	var samples []datastore.TelemetryData
+	for i := 0; i < 400; i++ {
+		samples = append(samples, datastore.TelemetryData{
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			RPM:        3000,
+			Speed:      90,
+			EngineLoad: 92,
+		})
+	}
+	rep := AnalyzeLoad(samples)
+	if !rep.SustainedHighLoad {
+		t.Fatalf("6+ minutes at 92%% load not flagged: %.0fs", rep.HighLoadSec)
+	}
+}
@@ -0,0 +1,116 @@
+// Package analysis runs offline passes over stored telemetry, turning
+// raw channels into findings a fleet operator can act on. Each pass is a
+// pure function over a telemetry slice so it works equally on live data,
+// session windows and historical queries.
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// The iLoad is rear-wheel drive: the rears are the driven axle, the
+// fronts roll free and act as the ground-speed reference.
+const (
+	// slipThresholdPct flags the driven axle overspeeding the free axle.
+	slipThresholdPct = 8.0
+	// absLockDropKmh flags a single wheel dropping this far below the
+	// vehicle in one sample — the signature of ABS cycling on a locking
+	// wheel.
+	absLockDropKmh = 10.0
+	// mismatchPct flags a wheel whose rolling radius disagrees with the
+	// axle set; ~1% is one tyre a size off or badly underinflated.
+	mismatchPct = 1.0
+	// minAnalysisSpeed ignores samples where wheel-speed resolution is
+	// too coarse to compare.
+	minAnalysisSpeed = 20.0
+)
+
+// WheelEvent is one detected slip or ABS episode.
+type WheelEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Wheel     string    `json:"wheel,omitempty"` // empty for axle-level events
+	// Magnitude is km/h of slip or lock-up depth, depending on the pass.
+	Magnitude float64 `json:"magnitude"`
+}
+
+// WheelReport is the output of the wheel-speed passes for one vehicle.
+type WheelReport struct {
+	Samples    int          `json:"samples"`
+	SlipEvents []WheelEvent `json:"slip_events,omitempty"`
+	ABSEvents  []WheelEvent `json:"abs_events,omitempty"`
+	// RatioToMean is each wheel's average speed relative to the sample
+	// mean over the window; a healthy set sits within ±0.5%.
+	RatioToMean map[string]float64 `json:"ratio_to_mean,omitempty"`
+	// Mismatched lists wheels whose ratio deviates beyond the mismatch
+	// threshold — tyre diameter or pressure suspects.
+	Mismatched []string `json:"mismatched,omitempty"`
+}
+
+// AnalyzeWheels runs the slip, ABS and tyre-mismatch passes over samples
+// that carry wheel speeds. Samples without wheel data are skipped.
+func AnalyzeWheels(samples []datastore.TelemetryData) *WheelReport {
+	rep := &WheelReport{RatioToMean: map[string]float64{}}
+	sums := map[string]float64{}
+	var meanSum float64
+
+	for i := range samples {
+		ws := samples[i].Wheels
+		if ws == nil {
+			continue
+		}
+		rep.Samples++
+
+		mean := (ws.FrontLeft + ws.FrontRight + ws.RearLeft + ws.RearRight) / 4
+		if mean >= minAnalysisSpeed {
+			for name, v := range wheelMap(ws) {
+				sums[name] += v
+			}
+			meanSum += mean
+
+			// Driven-axle slip: rears spinning up against the fronts.
+			front := (ws.FrontLeft + ws.FrontRight) / 2
+			rear := (ws.RearLeft + ws.RearRight) / 2
+			if front > 0 && (rear-front)/front*100 > slipThresholdPct {
+				rep.SlipEvents = append(rep.SlipEvents, WheelEvent{
+					Timestamp: samples[i].Timestamp,
+					Magnitude: rear - front,
+				})
+			}
+
+			// ABS: one wheel collapsing toward lock-up while the rest
+			// keep rolling.
+			for name, v := range wheelMap(ws) {
+				if mean-v > absLockDropKmh {
+					rep.ABSEvents = append(rep.ABSEvents, WheelEvent{
+						Timestamp: samples[i].Timestamp,
+						Wheel:     name,
+						Magnitude: mean - v,
+					})
+				}
+			}
+		}
+	}
+
+	if meanSum > 0 {
+		for name, sum := range sums {
+			ratio := sum / meanSum
+			rep.RatioToMean[name] = ratio
+			if math.Abs(ratio-1)*100 > mismatchPct {
+				rep.Mismatched = append(rep.Mismatched, name)
+			}
+		}
+	}
+	return rep
+}
+
+func wheelMap(ws *datastore.WheelSpeeds) map[string]float64 {
+	return map[string]float64{
+		"front_left":  ws.FrontLeft,
+		"front_right": ws.FrontRight,
+		"rear_left":   ws.RearLeft,
+		"rear_right":  ws.RearRight,
+	}
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func wheelSamples(n int, f func(i int) datastore.WheelSpeeds) []datastore.TelemetryData {
+	start := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	out := make([]datastore.TelemetryData, n)
+	for i := range out {
+		ws := f(i)
+		out[i] = datastore.TelemetryData{
+			VIN:       "KMFWB37HXTESTFIX0",
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Wheels:    &ws,
+		}
+	}
+	return out
+}
+
+func TestAnalyzeWheelsCleanRun(t *testing.T) {
+	rep := AnalyzeWheels(wheelSamples(60, func(i int) datastore.WheelSpeeds {
+		return datastore.WheelSpeeds{FrontLeft: 80, FrontRight: 80, RearLeft: 80, RearRight: 80}
+	}))
+	if len(rep.SlipEvents) != 0 || len(rep.ABSEvents) != 0 || len(rep.Mismatched) != 0 {
+		t.Fatalf("clean run produced findings: %+v", rep)
+	}
+	if rep.Samples != 60 {
+		t.Fatalf("samples = %d, want 60", rep.Samples)
+	}
+}
+
+func TestAnalyzeWheelsDetectsSlip(t *testing.T) {
+	rep := AnalyzeWheels(wheelSamples(30, func(i int) datastore.WheelSpeeds {
+		ws := datastore.WheelSpeeds{FrontLeft: 50, FrontRight: 50, RearLeft: 50, RearRight: 50}
+		if i >= 10 && i < 13 { // rears break loose for three samples
+			ws.RearLeft, ws.RearRight = 60, 60
+		}
+		return ws
+	}))
+	if len(rep.SlipEvents) != 3 {
+		t.Fatalf("slip events = %d, want 3", len(rep.SlipEvents))
+	}
+}
+
+func TestAnalyzeWheelsDetectsABSAndMismatch(t *testing.T) {
+	rep := AnalyzeWheels(wheelSamples(100, func(i int) datastore.WheelSpeeds {
+		// Front-left runs 2% fast the whole window: smaller tyre.
+		ws := datastore.WheelSpeeds{FrontLeft: 81.6, FrontRight: 80, RearLeft: 80, RearRight: 80}
+		if i == 50 { // rear-right heads for lock-up once
+			ws.RearRight = 55
+		}
+		return ws
+	}))
+	if len(rep.ABSEvents) != 1 || rep.ABSEvents[0].Wheel != "rear_right" {
+		t.Fatalf("ABS events = %+v, want one on rear_right", rep.ABSEvents)
+	}
+	found := false
+	for _, w := range rep.Mismatched {
+		if w == "front_left" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("mismatched = %v, want front_left flagged", rep.Mismatched)
+	}
+}
@@ -0,0 +1,125 @@
+// Package canid maps raw CAN identifiers to human-readable names
+// ("BCM status", "Wheel speeds") for the analyzer, frame stream and
+// reports. The registry is a user-editable YAML file of hex IDs:
+//
+//	0x316: Engine RPM
+//	0x4F0: Wheel speeds
+//	0x545: Coolant / temperatures
+package canid
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Label is one registry entry.
+type Label struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+// Registry holds the ID-to-name mapping, safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	path   string
+	labels map[uint32]string
+}
+
+// Load reads the registry from a YAML file; a missing file yields an
+// empty registry that saves back to the same path.
+func Load(path string) (*Registry, error) {
+	r := &Registry{path: path, labels: make(map[uint32]string)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("canid: parse %s: %w", path, err)
+	}
+	for k, name := range raw {
+		id, err := parseID(k)
+		if err != nil {
+			return nil, fmt.Errorf("canid: %s: %w", path, err)
+		}
+		r.labels[id] = name
+	}
+	return r, nil
+}
+
+func parseID(s string) (uint32, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+	id, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("bad CAN ID %q", s)
+	}
+	return uint32(id), nil
+}
+
+// Lookup returns the label for an ID, or "" when unknown.
+func (r *Registry) Lookup(id uint32) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.labels[id]
+}
+
+// Name returns the label for an ID, falling back to the hex ID itself.
+func (r *Registry) Name(id uint32) string {
+	if name := r.Lookup(id); name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%03X", id)
+}
+
+// Known reports whether the ID has a label.
+func (r *Registry) Known(id uint32) bool { return r.Lookup(id) != "" }
+
+// Set adds or updates a label and persists the registry.
+func (r *Registry) Set(id uint32, name string) error {
+	r.mu.Lock()
+	if name == "" {
+		delete(r.labels, id)
+	} else {
+		r.labels[id] = name
+	}
+	r.mu.Unlock()
+	return r.save()
+}
+
+// All returns every entry ordered by ID.
+func (r *Registry) All() []Label {
+	r.mu.RLock()
+	out := make([]Label, 0, len(r.labels))
+	for id, name := range r.labels {
+		out = append(out, Label{ID: id, Name: name})
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	r.mu.RLock()
+	raw := make(map[string]string, len(r.labels))
+	for id, name := range r.labels {
+		raw[fmt.Sprintf("0x%03X", id)] = name
+	}
+	r.mu.RUnlock()
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
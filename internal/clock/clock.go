@@ -0,0 +1,73 @@
+// Package clock provides a shared capture clock so OBD replies, raw CAN
+// frames and GPS fixes are all stamped against one time base.
+//
+// Timestamps are derived from a single monotonic reference taken when the
+// clock is created, plus the wall time observed at that instant and an
+// optional correction learned from GPS time. This keeps cross-source
+// deltas exact even when the system wall clock steps (NTP sync on a Pi
+// that booted without a network).
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Capture is a monotonic capture clock with a wall-clock anchor.
+type Capture struct {
+	mu     sync.RWMutex
+	start  time.Time     // carries the monotonic reading
+	wall   time.Time     // wall time at start, without monotonic part
+	offset time.Duration // correction learned from GPS
+}
+
+// NewCapture anchors a capture clock at the current instant.
+func NewCapture() *Capture {
+	now := time.Now()
+	return &Capture{start: now, wall: now.Round(0)}
+}
+
+// Now returns the current corrected capture time.
+func (c *Capture) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.wall.Add(time.Since(c.start) + c.offset)
+}
+
+// Elapsed returns the monotonic time since the clock was anchored.
+func (c *Capture) Elapsed() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.start)
+}
+
+// Offset returns the correction currently applied to wall time.
+func (c *Capture) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+// CorrectGPS adjusts the clock using an authoritative GPS timestamp
+// received at (uncorrected) local instant. Corrections under the jitter
+// floor are ignored so we don't chase serial latency noise.
+const gpsJitterFloor = 50 * time.Millisecond
+
+func (c *Capture) CorrectGPS(gps time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	uncorrected := c.wall.Add(time.Since(c.start))
+	delta := gps.Sub(uncorrected)
+	if delta > gpsJitterFloor || delta < -gpsJitterFloor {
+		c.offset = delta
+	}
+}
+
+var defaultClock = NewCapture()
+
+// Now returns the corrected capture time from the process-wide clock.
+func Now() time.Time { return defaultClock.Now() }
+
+// Default returns the process-wide capture clock, shared by the poller,
+// CAN reader and GPS subsystem.
+func Default() *Capture { return defaultClock }
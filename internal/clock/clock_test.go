@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowIsMonotonic(t *testing.T) {
+	c := NewCapture()
+	a := c.Now()
+	b := c.Now()
+	if b.Before(a) {
+		t.Fatalf("clock went backwards: %v then %v", a, b)
+	}
+}
+
+func TestCorrectGPSAppliesOffset(t *testing.T) {
+	c := NewCapture()
+	c.CorrectGPS(c.Now().Add(2 * time.Second))
+	got := c.Offset()
+	if got < 1900*time.Millisecond || got > 2100*time.Millisecond {
+		t.Fatalf("offset = %v, want ~2s", got)
+	}
+}
+
+func TestCorrectGPSIgnoresJitter(t *testing.T) {
+	c := NewCapture()
+	c.CorrectGPS(c.Now().Add(10 * time.Millisecond))
+	if got := c.Offset(); got != 0 {
+		t.Fatalf("offset = %v, want 0 for sub-jitter correction", got)
+	}
+}
@@ -0,0 +1,390 @@
+// Package config loads and manages daemon configuration, from a local
+// config.yaml and optionally from a central management endpoint.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration wraps time.Duration so YAML values like "10s" parse naturally.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("config: bad duration %q: %w", value.Value, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) Std() time.Duration { return time.Duration(d) }
+
+// AlertRule raises an alert when a telemetry channel leaves its bounds.
+type AlertRule struct {
+	Channel string   `yaml:"channel"`
+	Min     *float64 `yaml:"min,omitempty"`
+	Max     *float64 `yaml:"max,omitempty"`
+	Message string   `yaml:"message,omitempty"`
+}
+
+// CaptureTrigger starts a capture session when its condition fires.
+// Conditions are interpreted by the capture subsystem.
+type CaptureTrigger struct {
+	Name      string `yaml:"name"`
+	Condition string `yaml:"condition"`
+}
+
+// DerivedChannel defines a computed telemetry channel as an arithmetic
+// expression over existing channel names, e.g. "fuel_rate / speed * 100".
+type DerivedChannel struct {
+	Name string `yaml:"name"`
+	Unit string `yaml:"unit,omitempty"`
+	Expr string `yaml:"expr"`
+}
+
+// Remote configures pulling this daemon's config from a central endpoint,
+// so a fleet of Pis can be managed without touching each one.
+type Remote struct {
+	URL      string   `yaml:"url"`
+	Token    string   `yaml:"token,omitempty"`
+	Interval Duration `yaml:"interval,omitempty"`
+}
+
+// Agent configures offline-first edge mode: record locally, sync to a
+// central instance when connectivity appears.
+type Agent struct {
+	CentralURL   string   `yaml:"central_url"`
+	Token        string   `yaml:"token,omitempty"`
+	SyncInterval Duration `yaml:"sync_interval,omitempty"`
+}
+
+// Sync configures the central side of edge-agent uploads: agents
+// present the bearer token on the sync endpoints, and uploaded capture
+// session files land in CapturesDir.
+type Sync struct {
+	Token       string `yaml:"token,omitempty"`
+	CapturesDir string `yaml:"captures_dir,omitempty"`
+}
+
+// Email configures SMTP delivery of scheduled reports.
+type Email struct {
+	SMTPAddr string   `yaml:"smtp_addr"` // host:port
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+}
+
+// Reports configures scheduled per-vehicle report generation.
+type Reports struct {
+	Enabled   bool   `yaml:"enabled"`
+	Time      string `yaml:"time,omitempty"` // local HH:MM, default 06:00
+	OutputDir string `yaml:"output_dir,omitempty"`
+	Email     Email  `yaml:"email,omitempty"`
+}
+
+// CANBus describes one CAN link to capture from. Name is the label
+// stamped on frames and telemetry from this bus; it defaults to the
+// interface name.
+type CANBus struct {
+	Name string `yaml:"name,omitempty"`
+	// Interface is the link's address: a network interface for
+	// "socketcan", a serial device for "elm", host:port for "udp".
+	Interface string `yaml:"interface"`
+	// Type selects the transport: "socketcan" (the default) opens a raw
+	// AF_CAN socket; "elm" and "udp" run an ELM327 adapter in raw frame
+	// mode; "mock" is the offline simulator.
+	Type       string `yaml:"type,omitempty"`
+	Bitrate    int    `yaml:"bitrate,omitempty"`
+	AutoSetup  bool   `yaml:"auto_setup,omitempty"`
+	ListenOnly bool   `yaml:"listen_only,omitempty"`
+	// Filters lists acceptance filters ("id/mask" hex, e.g.
+	// "0x7E8/0x7F8") pushed down to the controller so a chatty bus
+	// doesn't flood the frame channel. Empty accepts everything; the
+	// ELM327 types take at most one entry.
+	Filters []string `yaml:"filters,omitempty"`
+}
+
+// Config is the full daemon configuration.
+type Config struct {
+	// Mode selects how the daemon runs: "server" (default) serves the
+	// HTTP API, "agent" records locally with no web server and syncs
+	// to the configured central instance.
+	Mode     string `yaml:"mode,omitempty"`
+	Listen   string `yaml:"listen"`
+	Database string `yaml:"database"`
+
+	CAN struct {
+		Interface string `yaml:"interface"`
+		// Type selects the transport for the primary bus; see CANBus.Type.
+		Type string `yaml:"type,omitempty"`
+		// Bitrate in bit/s (500000 on the iLoad diagnostic bus, 250000
+		// on some body buses). Only used when AutoSetup is on.
+		Bitrate int `yaml:"bitrate,omitempty"`
+		// AutoSetup brings the interface up with `ip link` at startup so
+		// a fresh Pi image needs no manual network configuration.
+		AutoSetup bool `yaml:"auto_setup,omitempty"`
+		// ListenOnly configures the controller to never ACK frames —
+		// guaranteed passive even below the TX policy layer.
+		ListenOnly bool `yaml:"listen_only,omitempty"`
+		// Filters lists acceptance filters for the primary bus; see
+		// CANBus.Filters.
+		Filters []string `yaml:"filters,omitempty"`
+		// Bluetooth configures an RFCOMM ELM327 for type: "bluetooth".
+		// The dongle must already be paired at the OS level; Channel 0
+		// uses the SPP default of 1.
+		Bluetooth struct {
+			MAC     string `yaml:"mac"`
+			Channel int    `yaml:"channel,omitempty"`
+		} `yaml:"bluetooth,omitempty"`
+		// WiFi configures a network ELM327 for type: "udp". Addr is the
+		// adapter's host:port, conventionally 192.168.0.10:35000.
+		WiFi struct {
+			Addr string `yaml:"addr"`
+		} `yaml:"wifi,omitempty"`
+		// Serial tunes the port parameters for type: "elm". Zero values
+		// use 38400-8N1; clone adapters sometimes need 9600 or 115200
+		// with explicit framing.
+		Serial struct {
+			Baud        int      `yaml:"baud,omitempty"`
+			DataBits    int      `yaml:"data_bits,omitempty"`
+			Parity      string   `yaml:"parity,omitempty"` // none, odd, even
+			StopBits    int      `yaml:"stop_bits,omitempty"`
+			ReadTimeout Duration `yaml:"read_timeout,omitempty"`
+		} `yaml:"serial,omitempty"`
+		// LabelsFile is the user-editable YAML registry mapping CAN IDs
+		// to human names.
+		LabelsFile string `yaml:"labels_file,omitempty"`
+		// DBCFile is a Vector DBC signal database; when set, raw frames
+		// are decoded into named signals for the live stream.
+		DBCFile string `yaml:"dbc_file,omitempty"`
+		// Extra lists additional buses captured alongside the primary
+		// one, e.g. a body bus on can1.
+		Extra []CANBus `yaml:"extra,omitempty"`
+		// PcapListen serves the live frame stream as pcap over TCP on
+		// this address, for attaching Wireshark to the running daemon.
+		PcapListen string `yaml:"pcap_listen,omitempty"`
+	} `yaml:"can"`
+
+	Poll struct {
+		PIDs     []string `yaml:"pids"`
+		Interval Duration `yaml:"interval"`
+		// StatusInterval paces the slow status cycle — trouble code and
+		// readiness reads that ride alongside the live data cycle. Zero
+		// uses a minute.
+		StatusInterval Duration `yaml:"status_interval,omitempty"`
+		// BroadcastInterval and PersistInterval decouple the dashboard
+		// stream and datastore writes from the poll rate: e.g. poll at
+		// 100ms, broadcast at 200ms, persist a 1s aggregate. Zero means
+		// every polled sample passes through.
+		BroadcastInterval Duration `yaml:"broadcast_interval,omitempty"`
+		PersistInterval   Duration `yaml:"persist_interval,omitempty"`
+	} `yaml:"poll"`
+
+	Capture struct {
+		// Encoding selects the session file encoding: "json" (default)
+		// or "cbor".
+		Encoding string `yaml:"encoding,omitempty"`
+		// EventsDir enables the pre-trigger ring recorder and names the
+		// directory event captures are written to.
+		EventsDir string `yaml:"events_dir,omitempty"`
+		// SessionsDir enables continuous trip recording: every drive is
+		// journalled crash-safe to this directory and finalized as a
+		// session file at key-off. Recordings a power cut interrupted
+		// are recovered at the next start.
+		SessionsDir string `yaml:"sessions_dir,omitempty"`
+		// Pre is how much history the ring holds before a trigger; Post
+		// is how long recording continues after one. Zero values use 30s.
+		Pre  Duration `yaml:"pre,omitempty"`
+		Post Duration `yaml:"post,omitempty"`
+		// Stream switches trip recording from one journalled session per
+		// drive to rotating segment files: frames never accumulate in
+		// memory and segments are bounded by size or age, which is the
+		// mode for all-day drives.
+		Stream struct {
+			Enabled bool `yaml:"enabled"`
+			// Gzip compresses segments on the fly.
+			Gzip bool `yaml:"gzip,omitempty"`
+			// MaxMB rotates a segment once it exceeds this many
+			// megabytes on disk.
+			MaxMB int `yaml:"max_mb,omitempty"`
+			// MaxAge rotates a segment open longer than this.
+			MaxAge Duration `yaml:"max_age,omitempty"`
+		} `yaml:"stream,omitempty"`
+	} `yaml:"capture,omitempty"`
+
+	TX struct {
+		// ReadOnly refuses every transmit onto a real bus.
+		ReadOnly bool `yaml:"read_only"`
+		// Allow lists hex CAN IDs this daemon may transmit (e.g.
+		// "0x7DF"). Empty means transmit nothing.
+		Allow []string `yaml:"allow,omitempty"`
+		// Deny lists hex CAN IDs refused even when whitelisted.
+		Deny []string `yaml:"deny,omitempty"`
+	} `yaml:"tx,omitempty"`
+
+	Webhooks struct {
+		// DTCURL receives a POST the first time each trouble code
+		// appears on a vehicle.
+		DTCURL string `yaml:"dtc_url,omitempty"`
+		// BaseURL is this instance's public URL, used to build links in
+		// webhook payloads.
+		BaseURL string `yaml:"base_url,omitempty"`
+	} `yaml:"webhooks,omitempty"`
+
+	// DTC points at an optional YAML file of extra trouble-code
+	// descriptions, merged over the built-in SAE and Hyundai tables.
+	DTC struct {
+		DescriptionsFile string `yaml:"descriptions_file,omitempty"`
+	} `yaml:"dtc,omitempty"`
+
+	// DPF tunes the soot load model. Zero warn_pct uses 80.
+	DPF struct {
+		WarnPct float64 `yaml:"warn_pct,omitempty"`
+	} `yaml:"dpf,omitempty"`
+
+	// MDNS advertises the API on the local network so companion apps
+	// discover the daemon without knowing its IP.
+	MDNS struct {
+		Enabled  bool   `yaml:"enabled"`
+		Instance string `yaml:"instance,omitempty"` // default: hostname
+	} `yaml:"mdns,omitempty"`
+
+	// I18n selects the locale for generated text (alert messages,
+	// report headings) and an optional directory of <locale>.yaml
+	// translation catalogs.
+	I18n struct {
+		Locale string `yaml:"locale,omitempty"`
+		Dir    string `yaml:"dir,omitempty"`
+	} `yaml:"i18n,omitempty"`
+
+	// Power tunes engine-off detection: after off_delay with no sample
+	// showing a running engine, polling drops to a low-power loop that
+	// probes every wake_check. Zero values use 90s and 30s.
+	Power struct {
+		OffDelay  Duration `yaml:"off_delay,omitempty"`
+		WakeCheck Duration `yaml:"wake_check,omitempty"`
+	} `yaml:"power,omitempty"`
+
+	// GPS attaches a serial NMEA receiver: fixes are fused into
+	// telemetry and the satellite time corrects the capture clock on
+	// Pis without an RTC. Device also accepts gpsd's raw NMEA socket
+	// via a pty; Baud 0 uses the NMEA standard 9600.
+	GPS struct {
+		Device string `yaml:"device,omitempty"`
+		Baud   int    `yaml:"baud,omitempty"`
+	} `yaml:"gps,omitempty"`
+
+	// Plugins configures the Lua scripting engine. Every .lua file in
+	// the directory is loaded at startup.
+	Plugins struct {
+		Dir string `yaml:"dir,omitempty"`
+	} `yaml:"plugins,omitempty"`
+
+	// Security selects the SecurityAccess seed→key algorithm used to
+	// unlock protected UDS reads: a built-in name (e.g. "xor_ff"),
+	// "plugin" for a Lua script's compute_key hook, or empty to never
+	// attempt an unlock. Level is the odd seed level, default 0x01.
+	Security struct {
+		Algorithm string `yaml:"algorithm,omitempty"`
+		Level     byte   `yaml:"level,omitempty"`
+	} `yaml:"security,omitempty"`
+
+	// Overheat tunes the predictive coolant-trend warning. Zero values
+	// use 105°C and a 10 minute horizon.
+	Overheat struct {
+		Threshold float64  `yaml:"threshold,omitempty"` // °C
+		Horizon   Duration `yaml:"horizon,omitempty"`
+	} `yaml:"overheat,omitempty"`
+
+	// Debug holds developer toggles that have no place in normal
+	// operation.
+	Debug struct {
+		// TrafficLog names a file receiving a timestamped hex dump of
+		// every frame sent or received, for debugging flaky adapters.
+		// Logging starts enabled and can be toggled over the API.
+		TrafficLog string `yaml:"traffic_log,omitempty"`
+	} `yaml:"debug,omitempty"`
+
+	// Transports lists additional links run alongside the CAN buses —
+	// typically an ELM327 for OBD polling next to a SocketCAN interface
+	// sniffing raw traffic. Entries use the same shape as can.extra.
+	Transports []CANBus `yaml:"transports,omitempty"`
+
+	Alerts          []AlertRule      `yaml:"alerts,omitempty"`
+	CaptureTriggers []CaptureTrigger `yaml:"capture_triggers,omitempty"`
+	Derived         []DerivedChannel `yaml:"derived,omitempty"`
+
+	Remote  Remote  `yaml:"remote,omitempty"`
+	Agent   Agent   `yaml:"agent,omitempty"`
+	Sync    Sync    `yaml:"sync,omitempty"`
+	Reports Reports `yaml:"reports,omitempty"`
+}
+
+// Buses returns every configured CAN bus, primary first, with names
+// defaulted to the interface name.
+func (c *Config) Buses() []CANBus {
+	var buses []CANBus
+	if c.CAN.Interface != "" {
+		buses = append(buses, CANBus{
+			Name:       c.CAN.Interface,
+			Interface:  c.CAN.Interface,
+			Type:       c.CAN.Type,
+			Bitrate:    c.CAN.Bitrate,
+			AutoSetup:  c.CAN.AutoSetup,
+			ListenOnly: c.CAN.ListenOnly,
+			Filters:    c.CAN.Filters,
+		})
+	}
+	for _, b := range c.CAN.Extra {
+		if b.Name == "" {
+			b.Name = b.Interface
+		}
+		buses = append(buses, b)
+	}
+	for _, b := range c.Transports {
+		if b.Name == "" {
+			b.Name = b.Interface
+		}
+		buses = append(buses, b)
+	}
+	return buses
+}
+
+// Default returns the configuration used when no config.yaml exists.
+func Default() *Config {
+	c := &Config{
+		Listen:   ":8080",
+		Database: "iload.db",
+	}
+	c.CAN.Interface = "can0"
+	c.Poll.Interval = Duration(time.Second)
+	return c
+}
+
+// Load reads the YAML config at path, applying defaults for unset fields.
+func Load(path string) (*Config, error) {
+	c := Default()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes a YAML config document, applying defaults for unset fields.
+func Parse(data []byte) (*Config, error) {
+	c := Default()
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("config: parse: %w", err)
+	}
+	return c, nil
+}
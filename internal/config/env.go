@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ApplyEnv overrides configuration from ILOAD_* environment variables,
+// so the daemon runs headless in a container or locked-down service
+// without a config.yaml: every data path, the listen address and the
+// CAN interface are settable from the unit file or compose file. Env
+// values override the file; command-line flags still override both.
+func (c *Config) ApplyEnv() error {
+	for _, v := range []struct {
+		name string
+		set  func(string) error
+	}{
+		{"ILOAD_MODE", stringVar(&c.Mode)},
+		{"ILOAD_LISTEN", stringVar(&c.Listen)},
+		{"ILOAD_DATABASE", stringVar(&c.Database)},
+		{"ILOAD_CAN_INTERFACE", stringVar(&c.CAN.Interface)},
+		{"ILOAD_CAN_BITRATE", intVar(&c.CAN.Bitrate)},
+		{"ILOAD_CAN_LISTEN_ONLY", boolVar(&c.CAN.ListenOnly)},
+		{"ILOAD_CAN_LABELS_FILE", stringVar(&c.CAN.LabelsFile)},
+		{"ILOAD_EVENTS_DIR", stringVar(&c.Capture.EventsDir)},
+		{"ILOAD_PLUGINS_DIR", stringVar(&c.Plugins.Dir)},
+		{"ILOAD_LOCALE", stringVar(&c.I18n.Locale)},
+		{"ILOAD_LOCALES_DIR", stringVar(&c.I18n.Dir)},
+		{"ILOAD_REPORTS_DIR", stringVar(&c.Reports.OutputDir)},
+		{"ILOAD_MDNS", boolVar(&c.MDNS.Enabled)},
+		{"ILOAD_AGENT_CENTRAL_URL", stringVar(&c.Agent.CentralURL)},
+		{"ILOAD_AGENT_TOKEN", stringVar(&c.Agent.Token)},
+	} {
+		raw, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := v.set(raw); err != nil {
+			return fmt.Errorf("config: %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+func stringVar(dst *string) func(string) error {
+	return func(raw string) error {
+		*dst = raw
+		return nil
+	}
+}
+
+func intVar(dst *int) func(string) error {
+	return func(raw string) error {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("bad integer %q", raw)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func boolVar(dst *bool) func(string) error {
+	return func(raw string) error {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("bad boolean %q", raw)
+		}
+		*dst = b
+		return nil
+	}
+}
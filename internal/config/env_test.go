@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Setenv("ILOAD_LISTEN", ":9090")
+	t.Setenv("ILOAD_CAN_BITRATE", "250000")
+	t.Setenv("ILOAD_MDNS", "true")
+	c := Default()
+	if err := c.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Listen != ":9090" || c.CAN.Bitrate != 250000 || !c.MDNS.Enabled {
+		t.Errorf("overrides not applied: listen=%q bitrate=%d mdns=%v",
+			c.Listen, c.CAN.Bitrate, c.MDNS.Enabled)
+	}
+	// Untouched fields keep their defaults.
+	if c.Database != "iload.db" {
+		t.Errorf("database = %q, want default", c.Database)
+	}
+}
+
+func TestApplyEnvRejectsBadValues(t *testing.T) {
+	t.Setenv("ILOAD_CAN_BITRATE", "fast")
+	if err := Default().ApplyEnv(); err == nil {
+		t.Error("bad integer accepted")
+	}
+}
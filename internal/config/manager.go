@@ -0,0 +1,44 @@
+package config
+
+import "sync"
+
+// Manager holds the live configuration and notifies subscribers when it
+// changes, so subsystems (poller, alert engine, capture triggers) can hot
+// reload without a daemon restart.
+type Manager struct {
+	mu   sync.RWMutex
+	cur  *Config
+	subs []func(*Config)
+}
+
+// NewManager starts with the given configuration.
+func NewManager(initial *Config) *Manager {
+	return &Manager{cur: initial}
+}
+
+// Current returns the active configuration. Callers must not mutate it.
+func (m *Manager) Current() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cur
+}
+
+// Subscribe registers fn to be called with every configuration applied
+// after this point.
+func (m *Manager) Subscribe(fn func(*Config)) {
+	m.mu.Lock()
+	m.subs = append(m.subs, fn)
+	m.mu.Unlock()
+}
+
+// Apply installs c as the active configuration and notifies subscribers.
+func (m *Manager) Apply(c *Config) {
+	m.mu.Lock()
+	m.cur = c
+	subs := make([]func(*Config), len(m.subs))
+	copy(subs, m.subs)
+	m.mu.Unlock()
+	for _, fn := range subs {
+		fn(c)
+	}
+}
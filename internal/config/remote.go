@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const defaultFetchInterval = 5 * time.Minute
+
+// Fetcher periodically pulls configuration from a central HTTP endpoint
+// and applies it through a Manager. The Remote section of the local config
+// (URL, token, interval) is always preserved so a bad central push cannot
+// orphan the device.
+type Fetcher struct {
+	remote Remote
+	mgr    *Manager
+	client *http.Client
+	etag   string
+}
+
+// NewFetcher builds a Fetcher for the given remote settings.
+func NewFetcher(remote Remote, mgr *Manager) *Fetcher {
+	return &Fetcher{
+		remote: remote,
+		mgr:    mgr,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run polls the endpoint until ctx is cancelled. It fetches once
+// immediately so a freshly provisioned device picks up its config fast.
+func (f *Fetcher) Run(ctx context.Context) {
+	interval := f.remote.Interval.Std()
+	if interval <= 0 {
+		interval = defaultFetchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f.fetch(ctx); err != nil {
+			log.Printf("config: remote fetch: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Fetcher) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.remote.URL, nil)
+	if err != nil {
+		return err
+	}
+	if f.remote.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.remote.Token)
+	}
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	// Never let the pushed document redirect or silence the fetcher itself.
+	c.Remote = f.remote
+	f.etag = resp.Header.Get("ETag")
+	f.mgr.Apply(c)
+	log.Printf("config: applied remote configuration from %s", f.remote.URL)
+	return nil
+}
@@ -0,0 +1,54 @@
+package datastore
+
+import "sync"
+
+// CachedStore wraps a Store with an in-memory cache of the most recent
+// telemetry sample per VIN, so dashboard reads don't hit the database on
+// every poll.
+type CachedStore struct {
+	Store
+
+	mu     sync.RWMutex
+	latest map[string]TelemetryData
+}
+
+// WithLatestCache wraps store with a latest-telemetry cache.
+func WithLatestCache(store Store) *CachedStore {
+	return &CachedStore{Store: store, latest: make(map[string]TelemetryData)}
+}
+
+func (c *CachedStore) SaveTelemetry(t TelemetryData) error {
+	if err := c.Store.SaveTelemetry(t); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.latest[t.VIN] = t
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *CachedStore) DeleteAllData(vin string) error {
+	if err := c.Store.DeleteAllData(vin); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	delete(c.latest, vin)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *CachedStore) LatestTelemetry(vin string) (TelemetryData, error) {
+	c.mu.RLock()
+	t, ok := c.latest[vin]
+	c.mu.RUnlock()
+	if ok {
+		return t, nil
+	}
+	t, err := c.Store.LatestTelemetry(vin)
+	if err == nil {
+		c.mu.Lock()
+		c.latest[vin] = t
+		c.mu.Unlock()
+	}
+	return t, err
+}
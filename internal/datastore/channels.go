@@ -0,0 +1,65 @@
+package datastore
+
+// ChannelValue extracts a named channel from a telemetry sample. It is
+// the single place channel names are mapped, shared by alert rules,
+// correlation tooling and derived channels.
+func ChannelValue(t TelemetryData, name string) (float64, bool) {
+	switch name {
+	case "rpm":
+		return t.RPM, true
+	case "speed":
+		return t.Speed, true
+	case "coolant_temp":
+		return t.CoolantTemp, true
+	case "engine_load":
+		return t.EngineLoad, true
+	case "throttle_pos":
+		return t.ThrottlePos, true
+	case "intake_temp":
+		return t.IntakeTemp, true
+	case "maf":
+		return t.MAF, true
+	case "fuel_level":
+		return t.FuelLevel, true
+	case "fuel_rate":
+		return t.FuelRate, true
+	case "short_fuel_trim_1":
+		return t.ShortFuelTrim1, true
+	case "long_fuel_trim_1":
+		return t.LongFuelTrim1, true
+	case "short_fuel_trim_2":
+		return t.ShortFuelTrim2, true
+	case "long_fuel_trim_2":
+		return t.LongFuelTrim2, true
+	case "equiv_ratio":
+		return t.EquivRatio, true
+	case "o2_lambda":
+		return t.O2Lambda, true
+	case "o2_current":
+		return t.O2Current, true
+	case "manifold_kpa":
+		return t.ManifoldKPa, true
+	case "rail_bar":
+		return t.RailBar, true
+	case "egr_commanded":
+		return t.EGRCommanded, true
+	case "egr_actual":
+		return t.EGRActual, true
+	case "dpf_diff_kpa":
+		return t.DPFDiffKPa, true
+	case "ambient_temp":
+		return t.AmbientTemp, true
+	case "baro_kpa":
+		return t.BaroKPa, true
+	case "voltage":
+		return t.Voltage, true
+	case "dpf_soot":
+		return t.DPFSootPct, true
+	}
+	// Derived channels resolve by name like polled ones, so alert rules,
+	// capture triggers and correlation see them transparently.
+	if v, ok := t.Derived[name]; ok {
+		return v, true
+	}
+	return 0, false
+}
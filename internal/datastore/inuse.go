@@ -0,0 +1,71 @@
+package datastore
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PerformanceCounter is one emissions monitor's in-use tracking pair:
+// how many times it ran to completion against how many drive cycles
+// gave it the chance.
+type PerformanceCounter struct {
+	Name        string `json:"name"`
+	Completions int    `json:"completions"`
+	Conditions  int    `json:"conditions"`
+}
+
+// InUsePerformance is a mode 09 in-use performance tracking snapshot.
+// The counters only ever grow (until codes are cleared), so a series of
+// snapshots per VIN trends long-term monitor health — a monitor whose
+// completions stop climbing has a problem readiness alone won't show.
+type InUsePerformance struct {
+	VIN            string               `json:"vin"`
+	Timestamp      time.Time            `json:"timestamp"`
+	OBDConditions  int                  `json:"obd_conditions"`  // drive cycles meeting OBD monitoring conditions
+	IgnitionCycles int                  `json:"ignition_cycles"` // engine starts
+	Monitors       []PerformanceCounter `json:"monitors,omitempty"`
+}
+
+const inUseSchema = `
+CREATE TABLE IF NOT EXISTS in_use_performance (
+	vin  TEXT NOT NULL,
+	ts   TIMESTAMP NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_inuse_vin_ts ON in_use_performance (vin, ts);
+`
+
+func (s *SQLiteStore) SaveInUsePerformance(p InUsePerformance) error {
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO in_use_performance (vin, ts, data) VALUES (?, ?, ?)`,
+		p.VIN, p.Timestamp, data)
+	return err
+}
+
+func (s *SQLiteStore) InUsePerformanceHistory(vin string, limit int) ([]InUsePerformance, error) {
+	rows, err := s.db.Query(
+		`SELECT data FROM in_use_performance WHERE vin = ? ORDER BY ts DESC LIMIT ?`, vin, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []InUsePerformance
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var p InUsePerformance
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,110 @@
+package datastore
+
+import "time"
+
+// Rollup is a pre-aggregated summary of one VIN over one period bucket,
+// computed by the background aggregation job so trend queries don't scan
+// raw telemetry.
+type Rollup struct {
+	VIN         string    `json:"vin"`
+	Period      string    `json:"period"` // "weekly" or "monthly"
+	PeriodStart time.Time `json:"period_start"`
+	DistanceKm  float64   `json:"distance_km"`
+	FuelL       float64   `json:"fuel_l"`
+	AvgEconomy  float64   `json:"avg_economy"` // L/100km
+	HarshEvents int       `json:"harsh_events"`
+	DTCCount    int       `json:"dtc_count"`
+	IdleSec     float64   `json:"idle_sec"`  // engine running, stationary
+	DriveSec    float64   `json:"drive_sec"` // engine running, any speed
+	// IdleQuality is the 0–100 idle smoothness score for the period;
+	// 0 when the period had no usable idle phases.
+	IdleQuality float64 `json:"idle_quality"`
+	// MassRatio estimates gross mass relative to unladen over the
+	// period; 0 when the period had no usable accelerations.
+	MassRatio float64 `json:"mass_ratio"`
+	// HighLoadSec is time spent above 85% engine load — sustained
+	// high-load operation shortens service intervals.
+	HighLoadSec float64   `json:"high_load_sec"`
+	ComputedAt  time.Time `json:"computed_at"`
+}
+
+// IdlePct returns the share of engine-on time spent stationary.
+func (r Rollup) IdlePct() float64 {
+	if r.DriveSec <= 0 {
+		return 0
+	}
+	return r.IdleSec / r.DriveSec * 100
+}
+
+const rollupSchema = `
+CREATE TABLE IF NOT EXISTS rollups (
+	vin          TEXT NOT NULL,
+	period       TEXT NOT NULL,
+	period_start TIMESTAMP NOT NULL,
+	distance_km  REAL NOT NULL DEFAULT 0,
+	fuel_l       REAL NOT NULL DEFAULT 0,
+	avg_economy  REAL NOT NULL DEFAULT 0,
+	harsh_events INTEGER NOT NULL DEFAULT 0,
+	dtc_count    INTEGER NOT NULL DEFAULT 0,
+	idle_s       REAL NOT NULL DEFAULT 0,
+	drive_s      REAL NOT NULL DEFAULT 0,
+	idle_quality REAL NOT NULL DEFAULT 0,
+	mass_ratio   REAL NOT NULL DEFAULT 0,
+	high_load_s  REAL NOT NULL DEFAULT 0,
+	computed_at  TIMESTAMP NOT NULL,
+	PRIMARY KEY (vin, period, period_start)
+);
+`
+
+// rollupMigrations are applied on open and must be safe to re-run;
+// "duplicate column" errors from older databases are ignored.
+var rollupMigrations = []string{
+	`ALTER TABLE rollups ADD COLUMN idle_quality REAL NOT NULL DEFAULT 0`,
+	`ALTER TABLE rollups ADD COLUMN mass_ratio REAL NOT NULL DEFAULT 0`,
+	`ALTER TABLE rollups ADD COLUMN high_load_s REAL NOT NULL DEFAULT 0`,
+}
+
+func (s *SQLiteStore) SaveRollup(r Rollup) error {
+	if r.ComputedAt.IsZero() {
+		r.ComputedAt = time.Now().UTC()
+	}
+	_, err := s.db.Exec(`INSERT INTO rollups
+		(vin, period, period_start, distance_km, fuel_l, avg_economy, harsh_events, dtc_count, idle_s, drive_s, idle_quality, mass_ratio, high_load_s, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(vin, period, period_start) DO UPDATE SET
+			distance_km=excluded.distance_km, fuel_l=excluded.fuel_l,
+			avg_economy=excluded.avg_economy, harsh_events=excluded.harsh_events,
+			dtc_count=excluded.dtc_count, idle_s=excluded.idle_s,
+			drive_s=excluded.drive_s, idle_quality=excluded.idle_quality,
+			mass_ratio=excluded.mass_ratio, high_load_s=excluded.high_load_s,
+			computed_at=excluded.computed_at`,
+		r.VIN, r.Period, r.PeriodStart, r.DistanceKm, r.FuelL, r.AvgEconomy,
+		r.HarshEvents, r.DTCCount, r.IdleSec, r.DriveSec, r.IdleQuality,
+		r.MassRatio, r.HighLoadSec, r.ComputedAt)
+	return err
+}
+
+func (s *SQLiteStore) Rollups(vin, period string, limit int) ([]Rollup, error) {
+	if limit <= 0 {
+		limit = 52
+	}
+	rows, err := s.db.Query(`SELECT vin, period, period_start, distance_km, fuel_l,
+		avg_economy, harsh_events, dtc_count, idle_s, drive_s, idle_quality, mass_ratio, high_load_s, computed_at
+		FROM rollups WHERE vin = ? AND period = ?
+		ORDER BY period_start DESC LIMIT ?`, vin, period, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rollup
+	for rows.Next() {
+		var r Rollup
+		if err := rows.Scan(&r.VIN, &r.Period, &r.PeriodStart, &r.DistanceKm, &r.FuelL,
+			&r.AvgEconomy, &r.HarshEvents, &r.DTCCount, &r.IdleSec, &r.DriveSec,
+			&r.IdleQuality, &r.MassRatio, &r.HighLoadSec, &r.ComputedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
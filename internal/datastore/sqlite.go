@@ -0,0 +1,260 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS vehicles (
+	vin        TEXT PRIMARY KEY,
+	make       TEXT NOT NULL DEFAULT '',
+	model      TEXT NOT NULL DEFAULT '',
+	year       INTEGER NOT NULL DEFAULT 0,
+	registered TIMESTAMP NOT NULL,
+	capabilities TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS telemetry (
+	vin  TEXT NOT NULL,
+	ts   TIMESTAMP NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_telemetry_vin_ts ON telemetry (vin, ts);
+CREATE TABLE IF NOT EXISTS alerts (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	vin          TEXT NOT NULL,
+	type         TEXT NOT NULL,
+	message      TEXT NOT NULL,
+	value        REAL NOT NULL DEFAULT 0,
+	threshold    REAL NOT NULL DEFAULT 0,
+	ts           TIMESTAMP NOT NULL,
+	acknowledged INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_alerts_vin ON alerts (vin, ts);
+`
+
+// vehicleMigrations add columns to the vehicles table for databases
+// created by older builds.
+var vehicleMigrations = []string{
+	`ALTER TABLE vehicles ADD COLUMN capabilities TEXT NOT NULL DEFAULT ''`,
+}
+
+// SQLiteStore is the default on-disk Store backend.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (creating if necessary) the SQLite database at path.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema + usersSchema + syncSchema + rollupSchema + tripsSchema + inUseSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init schema: %w", err)
+	}
+	// Migrations bring databases created by older builds up to date;
+	// failures on already-migrated columns are expected and ignored.
+	migrations := append(append([]string{}, rollupMigrations...), tripsMigrations...)
+	migrations = append(migrations, vehicleMigrations...)
+	for _, m := range migrations {
+		if _, err := db.Exec(m); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			db.Close()
+			return nil, fmt.Errorf("migrate schema: %w", err)
+		}
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) RegisterVehicle(v Vehicle) error {
+	if v.Registered.IsZero() {
+		v.Registered = time.Now().UTC()
+	}
+	_, err := s.db.Exec(`INSERT INTO vehicles (vin, make, model, year, registered)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(vin) DO UPDATE SET make=excluded.make, model=excluded.model, year=excluded.year`,
+		v.VIN, v.Make, v.Model, v.Year, v.Registered)
+	return err
+}
+
+func (s *SQLiteStore) Vehicle(vin string) (Vehicle, error) {
+	var v Vehicle
+	var caps string
+	err := s.db.QueryRow(`SELECT vin, make, model, year, registered, capabilities FROM vehicles WHERE vin = ?`, vin).
+		Scan(&v.VIN, &v.Make, &v.Model, &v.Year, &v.Registered, &caps)
+	if err == sql.ErrNoRows {
+		return v, ErrNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	return v, unmarshalCapabilities(&v, caps)
+}
+
+func (s *SQLiteStore) Vehicles() ([]Vehicle, error) {
+	rows, err := s.db.Query(`SELECT vin, make, model, year, registered, capabilities FROM vehicles ORDER BY vin`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Vehicle
+	for rows.Next() {
+		var v Vehicle
+		var caps string
+		if err := rows.Scan(&v.VIN, &v.Make, &v.Model, &v.Year, &v.Registered, &caps); err != nil {
+			return nil, err
+		}
+		if err := unmarshalCapabilities(&v, caps); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func unmarshalCapabilities(v *Vehicle, caps string) error {
+	if caps == "" {
+		return nil
+	}
+	v.Capabilities = &Capabilities{}
+	return json.Unmarshal([]byte(caps), v.Capabilities)
+}
+
+// SetVehicleCapabilities records a scan result without touching the
+// rest of the vehicle record, so an auto-registration later can't wipe
+// it.
+func (s *SQLiteStore) SetVehicleCapabilities(vin string, c Capabilities) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.Exec(`UPDATE vehicles SET capabilities = ? WHERE vin = ?`, data, vin)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteVehicle(vin string) error {
+	for _, q := range []string{
+		`DELETE FROM user_vehicles WHERE vin = ?`,
+		`DELETE FROM vehicles WHERE vin = ?`,
+	} {
+		if _, err := s.db.Exec(q, vin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteAllData(vin string) error {
+	for _, q := range []string{
+		`DELETE FROM telemetry WHERE vin = ?`,
+		`DELETE FROM alerts WHERE vin = ?`,
+		`DELETE FROM rollups WHERE vin = ?`,
+		`DELETE FROM trips WHERE vin = ?`,
+		`DELETE FROM dtc_events WHERE vin = ?`,
+		`DELETE FROM captures WHERE vin = ?`,
+		`DELETE FROM in_use_performance WHERE vin = ?`,
+		`DELETE FROM user_vehicles WHERE vin = ?`,
+		`DELETE FROM vehicles WHERE vin = ?`,
+	} {
+		if _, err := s.db.Exec(q, vin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) SaveTelemetry(t TelemetryData) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO telemetry (vin, ts, data) VALUES (?, ?, ?)`,
+		t.VIN, t.Timestamp, data)
+	return err
+}
+
+func (s *SQLiteStore) Telemetry(vin string, from, to time.Time) ([]TelemetryData, error) {
+	rows, err := s.db.Query(`SELECT data FROM telemetry WHERE vin = ? AND ts >= ? AND ts < ? ORDER BY ts`,
+		vin, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []TelemetryData
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var t TelemetryData
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) LatestTelemetry(vin string) (TelemetryData, error) {
+	var raw []byte
+	err := s.db.QueryRow(`SELECT data FROM telemetry WHERE vin = ? ORDER BY ts DESC LIMIT 1`, vin).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return TelemetryData{}, ErrNotFound
+	}
+	if err != nil {
+		return TelemetryData{}, err
+	}
+	var t TelemetryData
+	err = json.Unmarshal(raw, &t)
+	return t, err
+}
+
+func (s *SQLiteStore) SaveAlert(a *Alert) error {
+	if a.Timestamp.IsZero() {
+		a.Timestamp = time.Now().UTC()
+	}
+	res, err := s.db.Exec(`INSERT INTO alerts (vin, type, message, value, threshold, ts, acknowledged)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.VIN, a.Type, a.Message, a.Value, a.Threshold, a.Timestamp, a.Acknowledged)
+	if err != nil {
+		return err
+	}
+	a.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) Alerts(vin string, openOnly bool) ([]Alert, error) {
+	q := `SELECT id, vin, type, message, value, threshold, ts, acknowledged FROM alerts WHERE vin = ?`
+	if openOnly {
+		q += ` AND acknowledged = 0`
+	}
+	q += ` ORDER BY ts DESC`
+	rows, err := s.db.Query(q, vin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Alert
+	for rows.Next() {
+		var a Alert
+		if err := rows.Scan(&a.ID, &a.VIN, &a.Type, &a.Message, &a.Value, &a.Threshold, &a.Timestamp, &a.Acknowledged); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) Close() error { return s.db.Close() }
@@ -0,0 +1,101 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a vehicle or record does not exist.
+var ErrNotFound = errors.New("datastore: not found")
+
+// Store is the persistence contract shared by all backends.
+type Store interface {
+	// RegisterVehicle creates or updates a vehicle record.
+	RegisterVehicle(v Vehicle) error
+	// Vehicle returns the vehicle with the given VIN.
+	Vehicle(vin string) (Vehicle, error)
+	// Vehicles lists every registered vehicle.
+	Vehicles() ([]Vehicle, error)
+	// DeleteVehicle removes a vehicle and its access grants. Admin only
+	// at the API layer.
+	DeleteVehicle(vin string) error
+	// DeleteAllData removes every record held for a VIN — the vehicle,
+	// its telemetry, alerts and access grants — for privacy requests and
+	// vehicle sales. New per-VIN tables must be added here as they appear.
+	DeleteAllData(vin string) error
+	// SetVehicleCapabilities stores the result of a supported-PID scan
+	// against an already-registered vehicle.
+	SetVehicleCapabilities(vin string, c Capabilities) error
+
+	// SaveTelemetry persists one telemetry sample.
+	SaveTelemetry(t TelemetryData) error
+	// Telemetry returns samples for a VIN in [from, to), oldest first.
+	Telemetry(vin string, from, to time.Time) ([]TelemetryData, error)
+	// LatestTelemetry returns the most recent sample for a VIN.
+	LatestTelemetry(vin string) (TelemetryData, error)
+
+	// SaveAlert persists an alert and fills in its ID.
+	SaveAlert(a *Alert) error
+	// Alerts returns alerts for a VIN, newest first. If openOnly is set,
+	// only unacknowledged alerts are returned.
+	Alerts(vin string, openOnly bool) ([]Alert, error)
+
+	// SaveTrip persists a segmented journey and fills in its ID.
+	SaveTrip(t *Trip) error
+	// Trips returns journeys for a VIN, newest first.
+	Trips(vin string, limit int) ([]Trip, error)
+	// SearchTrips filters a VIN's journeys by tag and date range; an
+	// empty tag or zero time leaves that filter open.
+	SearchTrips(vin, tag string, from, to time.Time) ([]Trip, error)
+	// TagTrip replaces the tags on a trip.
+	TagTrip(id int64, tags []string) error
+
+	// SaveDTCEvent records a trouble code's first appearance and fills
+	// in its ID.
+	SaveDTCEvent(e *DTCEvent) error
+	// ClearDTCEvent marks every open occurrence of a code as cleared.
+	ClearDTCEvent(vin, code string, at time.Time) error
+	// DTCEvents returns trouble-code history for a VIN, newest first.
+	DTCEvents(vin string, limit int) ([]DTCEvent, error)
+
+	// RegisterCapture indexes a session file against its vehicle and
+	// fills in the record's ID.
+	RegisterCapture(c *CaptureRecord) error
+	// Captures lists registered session files for a VIN, newest first.
+	Captures(vin string, limit int) ([]CaptureRecord, error)
+	// SearchCaptures filters a VIN's capture registrations by tag and
+	// date range; an empty tag or zero time leaves that filter open.
+	SearchCaptures(vin, tag string, from, to time.Time) ([]CaptureRecord, error)
+	// TagCapture replaces the tags on a capture registration.
+	TagCapture(id int64, tags []string) error
+
+	// SaveInUsePerformance records a mode 09 in-use performance
+	// tracking snapshot.
+	SaveInUsePerformance(p InUsePerformance) error
+	// InUsePerformanceHistory returns snapshots for a VIN, newest
+	// first, for trending monitor health.
+	InUsePerformanceHistory(vin string, limit int) ([]InUsePerformance, error)
+
+	// SaveRollup upserts a period summary computed by the aggregation job.
+	SaveRollup(r Rollup) error
+	// Rollups returns period summaries for a VIN, newest first.
+	Rollups(vin, period string, limit int) ([]Rollup, error)
+
+	// CreateUser creates an account and fills in its ID.
+	CreateUser(u *User) error
+	// UserByName returns the account with the given username.
+	UserByName(username string) (User, error)
+	// UserCount returns the number of accounts; zero means the instance
+	// runs in open single-owner mode.
+	UserCount() (int, error)
+	// GrantVehicle makes a vehicle visible to a user.
+	GrantVehicle(userID int64, vin string) error
+	// RevokeVehicle removes a user's access to a vehicle.
+	RevokeVehicle(userID int64, vin string) error
+	// UserVehicles lists the vehicles a user can see.
+	UserVehicles(userID int64) ([]Vehicle, error)
+	// CanAccessVehicle reports whether a user may see the given VIN.
+	CanAccessVehicle(userID int64, vin string) (bool, error)
+
+	Close() error
+}
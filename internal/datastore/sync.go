@@ -0,0 +1,84 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// Sync cursors are stored in a small key/value table so an edge agent can
+// resume uploads exactly where the last acknowledged batch ended.
+
+const syncSchema = `
+CREATE TABLE IF NOT EXISTS sync_state (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// SyncCursor returns the stored cursor for key, or 0 if none is set.
+func (s *SQLiteStore) SyncCursor(key string) (int64, error) {
+	var v int64
+	err := s.db.QueryRow(`SELECT value FROM sync_state WHERE key = ?`, key).Scan(&v)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return v, err
+}
+
+// SetSyncCursor stores the cursor for key.
+func (s *SQLiteStore) SetSyncCursor(key string, value int64) error {
+	_, err := s.db.Exec(`INSERT INTO sync_state (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value`, key, value)
+	return err
+}
+
+// TelemetryAfter returns up to limit telemetry samples with rowid greater
+// than cursor, along with the rowid of the last returned sample.
+func (s *SQLiteStore) TelemetryAfter(cursor int64, limit int) ([]TelemetryData, int64, error) {
+	rows, err := s.db.Query(`SELECT rowid, data FROM telemetry WHERE rowid > ? ORDER BY rowid LIMIT ?`,
+		cursor, limit)
+	if err != nil {
+		return nil, cursor, err
+	}
+	defer rows.Close()
+	var out []TelemetryData
+	last := cursor
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&last, &raw); err != nil {
+			return nil, cursor, err
+		}
+		var t TelemetryData
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, cursor, err
+		}
+		out = append(out, t)
+	}
+	return out, last, rows.Err()
+}
+
+// CapturesAfter returns up to limit capture registrations with ID
+// greater than cursor.
+func (s *SQLiteStore) CapturesAfter(cursor int64, limit int) ([]CaptureRecord, error) {
+	return s.queryCaptures(`SELECT id, vin, path, start, end, frames, encoding, tags
+		FROM captures WHERE id > ? ORDER BY id LIMIT ?`, cursor, limit)
+}
+
+// AlertsAfter returns up to limit alerts with ID greater than cursor.
+func (s *SQLiteStore) AlertsAfter(cursor int64, limit int) ([]Alert, error) {
+	rows, err := s.db.Query(`SELECT id, vin, type, message, value, threshold, ts, acknowledged
+		FROM alerts WHERE id > ? ORDER BY id LIMIT ?`, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Alert
+	for rows.Next() {
+		var a Alert
+		if err := rows.Scan(&a.ID, &a.VIN, &a.Type, &a.Message, &a.Value, &a.Threshold, &a.Timestamp, &a.Acknowledged); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
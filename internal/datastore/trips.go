@@ -0,0 +1,318 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Trip is one engine-on to engine-off journey, segmented from telemetry.
+type Trip struct {
+	ID         int64     `json:"id"`
+	VIN        string    `json:"vin"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DistanceKm float64   `json:"distance_km"`
+	FuelL      float64   `json:"fuel_l"`
+	MaxSpeed   float64   `json:"max_speed"` // km/h
+	IdleSec    float64   `json:"idle_sec"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// DTCEvent records a trouble code's lifecycle on a vehicle: when it was
+// first seen and, once it stops being reported, when it cleared.
+type DTCEvent struct {
+	ID        int64      `json:"id"`
+	VIN       string     `json:"vin"`
+	Code      string     `json:"code"`
+	FirstSeen time.Time  `json:"first_seen"`
+	ClearedAt *time.Time `json:"cleared_at,omitempty"`
+	// FreezeFrame is the ECU's latched snapshot of conditions when the
+	// fault set (mode 02), where the bus was available to fetch it.
+	FreezeFrame *FreezeFrame `json:"freeze_frame,omitempty"`
+}
+
+// FreezeFrame is the operating snapshot an ECU stores at the moment a
+// fault sets — what the engine was doing when things went wrong.
+type FreezeFrame struct {
+	RPM         float64 `json:"rpm"`
+	Speed       float64 `json:"speed"`        // km/h
+	CoolantTemp float64 `json:"coolant_temp"` // °C
+	EngineLoad  float64 `json:"engine_load"`  // percent
+}
+
+// CaptureRecord registers a session file held on disk against the
+// vehicle it recorded, so captures are discoverable per VIN instead of
+// by trawling the directory.
+type CaptureRecord struct {
+	ID       int64     `json:"id"`
+	VIN      string    `json:"vin"`
+	Path     string    `json:"path"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Frames   int       `json:"frames"`
+	Encoding string    `json:"encoding"`
+	Tags     []string  `json:"tags,omitempty"`
+}
+
+const tripsSchema = `
+CREATE TABLE IF NOT EXISTS trips (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	vin         TEXT NOT NULL,
+	start       TIMESTAMP NOT NULL,
+	end         TIMESTAMP NOT NULL,
+	distance_km REAL NOT NULL DEFAULT 0,
+	fuel_l      REAL NOT NULL DEFAULT 0,
+	max_speed   REAL NOT NULL DEFAULT 0,
+	idle_s      REAL NOT NULL DEFAULT 0,
+	tags        TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_trips_vin ON trips (vin, start);
+CREATE TABLE IF NOT EXISTS dtc_events (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	vin        TEXT NOT NULL,
+	code       TEXT NOT NULL,
+	first_seen TIMESTAMP NOT NULL,
+	cleared_at TIMESTAMP,
+	freeze_frame TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_dtc_events_vin ON dtc_events (vin, first_seen);
+CREATE TABLE IF NOT EXISTS captures (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	vin      TEXT NOT NULL,
+	path     TEXT NOT NULL,
+	start    TIMESTAMP NOT NULL,
+	end      TIMESTAMP NOT NULL,
+	frames   INTEGER NOT NULL DEFAULT 0,
+	encoding TEXT NOT NULL DEFAULT 'json',
+	tags     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_captures_vin ON captures (vin, start);
+`
+
+// tripsMigrations follows the rollup migration pattern for columns added
+// after the tables first shipped.
+var tripsMigrations = []string{
+	`ALTER TABLE trips ADD COLUMN tags TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE captures ADD COLUMN tags TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE dtc_events ADD COLUMN freeze_frame TEXT NOT NULL DEFAULT ''`,
+}
+
+// Tags are stored comma-joined with sentinel commas at both ends so a
+// single LIKE matches whole tags only.
+func joinTags(tags []string) string {
+	var clean []string
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" {
+			clean = append(clean, t)
+		}
+	}
+	if len(clean) == 0 {
+		return ""
+	}
+	return "," + strings.Join(clean, ",") + ","
+}
+
+func splitTags(s string) []string {
+	var out []string
+	for _, t := range strings.Split(s, ",") {
+		if t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (s *SQLiteStore) SaveTrip(t *Trip) error {
+	res, err := s.db.Exec(`INSERT INTO trips (vin, start, end, distance_km, fuel_l, max_speed, idle_s, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.VIN, t.Start, t.End, t.DistanceKm, t.FuelL, t.MaxSpeed, t.IdleSec, joinTags(t.Tags))
+	if err != nil {
+		return err
+	}
+	t.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) Trips(vin string, limit int) ([]Trip, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.queryTrips(`SELECT id, vin, start, end, distance_km, fuel_l, max_speed, idle_s, tags
+		FROM trips WHERE vin = ? ORDER BY start DESC LIMIT ?`, vin, limit)
+}
+
+// SearchTrips returns trips for a VIN filtered by tag and date range;
+// an empty tag or zero time leaves that filter open.
+func (s *SQLiteStore) SearchTrips(vin, tag string, from, to time.Time) ([]Trip, error) {
+	q, args := searchQuery("trips", "id, vin, start, end, distance_km, fuel_l, max_speed, idle_s, tags",
+		vin, tag, from, to)
+	return s.queryTrips(q, args...)
+}
+
+func (s *SQLiteStore) queryTrips(q string, args ...interface{}) ([]Trip, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Trip
+	for rows.Next() {
+		var t Trip
+		var tags string
+		if err := rows.Scan(&t.ID, &t.VIN, &t.Start, &t.End, &t.DistanceKm,
+			&t.FuelL, &t.MaxSpeed, &t.IdleSec, &tags); err != nil {
+			return nil, err
+		}
+		t.Tags = splitTags(tags)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// TagTrip replaces the tags on a trip.
+func (s *SQLiteStore) TagTrip(id int64, tags []string) error {
+	res, err := s.db.Exec(`UPDATE trips SET tags = ? WHERE id = ?`, joinTags(tags), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// searchQuery builds the shared tag/date-range filter for trips and
+// captures; both tables index (vin, start).
+func searchQuery(table, cols, vin, tag string, from, to time.Time) (string, []interface{}) {
+	q := `SELECT ` + cols + ` FROM ` + table + ` WHERE vin = ?`
+	args := []interface{}{vin}
+	if tag != "" {
+		q += ` AND tags LIKE ?`
+		args = append(args, "%,"+tag+",%")
+	}
+	if !from.IsZero() {
+		q += ` AND start >= ?`
+		args = append(args, from)
+	}
+	if !to.IsZero() {
+		q += ` AND start < ?`
+		args = append(args, to)
+	}
+	return q + ` ORDER BY start DESC`, args
+}
+
+func (s *SQLiteStore) SaveDTCEvent(e *DTCEvent) error {
+	var frame string
+	if e.FreezeFrame != nil {
+		data, err := json.Marshal(e.FreezeFrame)
+		if err != nil {
+			return err
+		}
+		frame = string(data)
+	}
+	res, err := s.db.Exec(`INSERT INTO dtc_events (vin, code, first_seen, cleared_at, freeze_frame)
+		VALUES (?, ?, ?, ?, ?)`, e.VIN, e.Code, e.FirstSeen, e.ClearedAt, frame)
+	if err != nil {
+		return err
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) ClearDTCEvent(vin, code string, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE dtc_events SET cleared_at = ?
+		WHERE vin = ? AND code = ? AND cleared_at IS NULL`, at, vin, code)
+	return err
+}
+
+func (s *SQLiteStore) DTCEvents(vin string, limit int) ([]DTCEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := s.db.Query(`SELECT id, vin, code, first_seen, cleared_at, freeze_frame
+		FROM dtc_events WHERE vin = ? ORDER BY first_seen DESC LIMIT ?`, vin, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []DTCEvent
+	for rows.Next() {
+		var e DTCEvent
+		var cleared sql.NullTime
+		var frame string
+		if err := rows.Scan(&e.ID, &e.VIN, &e.Code, &e.FirstSeen, &cleared, &frame); err != nil {
+			return nil, err
+		}
+		if cleared.Valid {
+			t := cleared.Time
+			e.ClearedAt = &t
+		}
+		if frame != "" {
+			var ff FreezeFrame
+			if err := json.Unmarshal([]byte(frame), &ff); err == nil {
+				e.FreezeFrame = &ff
+			}
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) RegisterCapture(c *CaptureRecord) error {
+	res, err := s.db.Exec(`INSERT INTO captures (vin, path, start, end, frames, encoding, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, c.VIN, c.Path, c.Start, c.End, c.Frames, c.Encoding, joinTags(c.Tags))
+	if err != nil {
+		return err
+	}
+	c.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) Captures(vin string, limit int) ([]CaptureRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.queryCaptures(`SELECT id, vin, path, start, end, frames, encoding, tags
+		FROM captures WHERE vin = ? ORDER BY start DESC LIMIT ?`, vin, limit)
+}
+
+// SearchCaptures returns capture registrations for a VIN filtered by tag
+// and date range; an empty tag or zero time leaves that filter open.
+func (s *SQLiteStore) SearchCaptures(vin, tag string, from, to time.Time) ([]CaptureRecord, error) {
+	q, args := searchQuery("captures", "id, vin, path, start, end, frames, encoding, tags",
+		vin, tag, from, to)
+	return s.queryCaptures(q, args...)
+}
+
+func (s *SQLiteStore) queryCaptures(q string, args ...interface{}) ([]CaptureRecord, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CaptureRecord
+	for rows.Next() {
+		var c CaptureRecord
+		var tags string
+		if err := rows.Scan(&c.ID, &c.VIN, &c.Path, &c.Start, &c.End, &c.Frames, &c.Encoding, &tags); err != nil {
+			return nil, err
+		}
+		c.Tags = splitTags(tags)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// TagCapture replaces the tags on a capture registration.
+func (s *SQLiteStore) TagCapture(id int64, tags []string) error {
+	res, err := s.db.Exec(`UPDATE captures SET tags = ? WHERE id = ?`, joinTags(tags), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package datastore
+
+import "time"
+
+// Vehicle describes a van known to the logger, keyed by VIN.
+type Vehicle struct {
+	VIN        string    `json:"vin"`
+	Make       string    `json:"make"`
+	Model      string    `json:"model"`
+	Year       int       `json:"year"`
+	Registered time.Time `json:"registered"`
+	// Capabilities is what the vehicle answered during the supported-PID
+	// scan; nil until a scan has run.
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+}
+
+// Capabilities is a vehicle's scanned capability map. Polling consults
+// it so only PIDs the car actually supports go on the wire.
+type Capabilities struct {
+	// Mode01 lists the supported live-data PIDs, ascending.
+	Mode01 []uint8 `json:"mode01,omitempty"`
+}
+
+// Location is a GPS fix associated with a telemetry sample.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+	Speed     float64 `json:"speed"`
+	Course    float64 `json:"course"`
+}
+
+// WheelSpeeds carries the four individual wheel speeds in km/h, decoded
+// from the ABS module's broadcast frames.
+type WheelSpeeds struct {
+	FrontLeft  float64 `json:"front_left"`
+	FrontRight float64 `json:"front_right"`
+	RearLeft   float64 `json:"rear_left"`
+	RearRight  float64 `json:"rear_right"`
+}
+
+// TelemetryData is one sample of live OBD-II data for a vehicle.
+type TelemetryData struct {
+	VIN         string    `json:"vin"`
+	Timestamp   time.Time `json:"timestamp"`
+	RPM         float64   `json:"rpm"`
+	Speed       float64   `json:"speed"`        // km/h
+	CoolantTemp float64   `json:"coolant_temp"` // °C
+	EngineLoad  float64   `json:"engine_load"`  // percent
+	ThrottlePos float64   `json:"throttle_pos"` // percent
+	IntakeTemp  float64   `json:"intake_temp"`  // °C
+	MAF         float64   `json:"maf"`          // g/s
+	FuelLevel   float64   `json:"fuel_level"`   // percent
+	FuelRate    float64   `json:"fuel_rate"`    // L/h, PID 0x5E; 0 when unsupported
+	// Fuel trims are percent corrections to base fuelling (PIDs 06–09);
+	// positive means the ECU is adding fuel. Bank 2 stays 0 on
+	// single-bank engines.
+	ShortFuelTrim1 float64 `json:"short_fuel_trim_1,omitempty"`
+	LongFuelTrim1  float64 `json:"long_fuel_trim_1,omitempty"`
+	ShortFuelTrim2 float64 `json:"short_fuel_trim_2,omitempty"`
+	LongFuelTrim2  float64 `json:"long_fuel_trim_2,omitempty"`
+	// EquivRatio is the commanded equivalence ratio λ (PID 0x44);
+	// O2Lambda/O2Current come from wideband sensor 1 (PID 0x34). All 0
+	// when unsupported.
+	EquivRatio float64 `json:"equiv_ratio,omitempty"`
+	O2Lambda   float64 `json:"o2_lambda,omitempty"`
+	O2Current  float64 `json:"o2_current,omitempty"` // mA
+	// Diesel air and fuel path channels. ManifoldKPa is absolute (PID
+	// 0x0B) — subtract BaroKPa for boost. EGR is commanded (PID 0x2C)
+	// vs actual position (Hyundai DID); DPFDiffKPa is the pressure drop
+	// across the filter.
+	ManifoldKPa  float64 `json:"manifold_kpa,omitempty"`
+	RailBar      float64 `json:"rail_bar,omitempty"`
+	EGRCommanded float64 `json:"egr_commanded,omitempty"` // percent
+	EGRActual    float64 `json:"egr_actual,omitempty"`    // percent
+	DPFDiffKPa   float64 `json:"dpf_diff_kpa,omitempty"`
+	AmbientTemp  float64 `json:"ambient_temp"` // °C, PID 0x46; 0 when unsupported
+	BaroKPa      float64 `json:"baro_kpa"`     // kPa, PID 0x33; 0 when unsupported
+	Voltage      float64 `json:"voltage"`      // battery volts
+	// Brake reports the brake light switch where it's been DBC-mapped;
+	// false either means not braking or not decoded.
+	Brake bool `json:"brake,omitempty"`
+	// Misfires is the cumulative misfire counter since engine start,
+	// where the vehicle reports one; 0 otherwise.
+	Misfires int      `json:"misfires,omitempty"`
+	DTCs     []string `json:"dtcs,omitempty"`
+	// DTCDetails carries descriptions and severity for DTCs, stamped on
+	// ingest from the description tables.
+	DTCDetails []DTCInfo `json:"dtc_details,omitempty"`
+	// PendingDTCs (mode 07) are faults seen once but not yet confirmed —
+	// the early warning for intermittent problems. PermanentDTCs (mode
+	// 0A) survive a mode 04 clear until the ECU sees a clean drive cycle.
+	PendingDTCs   []string `json:"pending_dtcs,omitempty"`
+	PermanentDTCs []string `json:"permanent_dtcs,omitempty"`
+	// DTCsByECU splits the stored codes by the module that reported
+	// them ("engine", "transmission", …), where the poller collected
+	// every responder to the broadcast read rather than just 0x7E8.
+	DTCsByECU map[string][]string `json:"dtcs_by_ecu,omitempty"`
+	// Readiness carries the I/M monitor snapshot where it was polled;
+	// nil on samples that didn't include it.
+	Readiness *Readiness `json:"readiness,omitempty"`
+	Location  *Location  `json:"location,omitempty"`
+	// Wheels is present once individual wheel speeds are decoded for
+	// the vehicle; most OBD-only setups leave it nil.
+	Wheels *WheelSpeeds `json:"wheels,omitempty"`
+	// DPFSootPct is the modelled DPF soot load, stamped on ingest by
+	// the soot watcher; 0 until the model has seen enough driving.
+	DPFSootPct float64 `json:"dpf_soot_pct,omitempty"`
+	// Derived holds computed channels (acceleration, power estimate,
+	// user expressions) stamped on by the derived channel engine.
+	Derived map[string]float64 `json:"derived,omitempty"`
+}
+
+// DTCInfo is the human-readable side of a trouble code, resolved from
+// the description tables at read time.
+type DTCInfo struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	// Severity is "critical", "service", "info" or "unknown".
+	Severity string `json:"severity"`
+}
+
+// MonitorStatus is one emissions readiness monitor's state.
+type MonitorStatus struct {
+	Name     string `json:"name"`
+	Complete bool   `json:"complete"`
+}
+
+// Readiness is the I/M readiness snapshot (mode 01 PID 01): which
+// emissions monitors have completed since codes were last cleared —
+// what an inspection station checks before plugging in its own tool.
+type Readiness struct {
+	MILOn    bool            `json:"mil_on"`
+	DTCCount int             `json:"dtc_count"`
+	Monitors []MonitorStatus `json:"monitors,omitempty"`
+}
+
+// Alert records a threshold or fault condition raised against a vehicle.
+type Alert struct {
+	ID           int64     `json:"id"`
+	VIN          string    `json:"vin"`
+	Type         string    `json:"type"`
+	Message      string    `json:"message"`
+	Value        float64   `json:"value"`
+	Threshold    float64   `json:"threshold"`
+	Timestamp    time.Time `json:"timestamp"`
+	Acknowledged bool      `json:"acknowledged"`
+}
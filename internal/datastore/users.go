@@ -0,0 +1,130 @@
+package datastore
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Role controls what an account may do. Read-only users can view data,
+// operators may run live commands (captures, actuator tests), and admins
+// may perform destructive operations such as clearing DTCs, UDS writes
+// and vehicle deletion.
+type Role string
+
+const (
+	RoleReadOnly Role = "readonly"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// Level orders roles for privilege comparisons.
+func (r Role) Level() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleOperator:
+		return 2
+	case RoleReadOnly:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether r grants at least the privileges of min.
+func (r Role) AtLeast(min Role) bool { return r.Level() >= min.Level() }
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool { return r.Level() > 0 }
+
+// User is an account on a hosted instance. Vehicles are visible only to
+// users they have been granted to; an instance with no users at all runs
+// in open single-owner mode.
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `json:"role"`
+	Created      time.Time `json:"created"`
+}
+
+const usersSchema = `
+CREATE TABLE IF NOT EXISTS users (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	username      TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	role          TEXT NOT NULL DEFAULT 'readonly',
+	created       TIMESTAMP NOT NULL
+);
+CREATE TABLE IF NOT EXISTS user_vehicles (
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	vin     TEXT NOT NULL,
+	PRIMARY KEY (user_id, vin)
+);
+`
+
+func (s *SQLiteStore) CreateUser(u *User) error {
+	if u.Created.IsZero() {
+		u.Created = time.Now().UTC()
+	}
+	if u.Role == "" {
+		u.Role = RoleReadOnly
+	}
+	res, err := s.db.Exec(`INSERT INTO users (username, password_hash, role, created) VALUES (?, ?, ?, ?)`,
+		u.Username, u.PasswordHash, u.Role, u.Created)
+	if err != nil {
+		return err
+	}
+	u.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) UserByName(username string) (User, error) {
+	var u User
+	err := s.db.QueryRow(`SELECT id, username, password_hash, role, created FROM users WHERE username = ?`, username).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.Created)
+	if err == sql.ErrNoRows {
+		return u, ErrNotFound
+	}
+	return u, err
+}
+
+func (s *SQLiteStore) UserCount() (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n)
+	return n, err
+}
+
+func (s *SQLiteStore) GrantVehicle(userID int64, vin string) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO user_vehicles (user_id, vin) VALUES (?, ?)`, userID, vin)
+	return err
+}
+
+func (s *SQLiteStore) RevokeVehicle(userID int64, vin string) error {
+	_, err := s.db.Exec(`DELETE FROM user_vehicles WHERE user_id = ? AND vin = ?`, userID, vin)
+	return err
+}
+
+func (s *SQLiteStore) UserVehicles(userID int64) ([]Vehicle, error) {
+	rows, err := s.db.Query(`SELECT v.vin, v.make, v.model, v.year, v.registered
+		FROM vehicles v JOIN user_vehicles uv ON uv.vin = v.vin
+		WHERE uv.user_id = ? ORDER BY v.vin`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Vehicle
+	for rows.Next() {
+		var v Vehicle
+		if err := rows.Scan(&v.VIN, &v.Make, &v.Model, &v.Year, &v.Registered); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) CanAccessVehicle(userID int64, vin string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM user_vehicles WHERE user_id = ? AND vin = ?`, userID, vin).Scan(&n)
+	return n > 0, err
+}
@@ -0,0 +1,190 @@
+// Package dbc parses Vector DBC files — the de-facto exchange format
+// for CAN signal definitions — and decodes raw frames into named,
+// scaled signal values. Only the message (BO_) and signal (SG_)
+// sections are read; attributes, comments and value tables are skipped.
+package dbc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// Signal is one signal's placement and scaling within its message.
+type Signal struct {
+	Name string `json:"name"`
+	// Start is the DBC start bit: the LSB for little-endian signals,
+	// the MSB (sawtooth numbering) for big-endian ones.
+	Start        int     `json:"start"`
+	Length       int     `json:"length"`
+	LittleEndian bool    `json:"little_endian"`
+	Signed       bool    `json:"signed"`
+	Factor       float64 `json:"factor"`
+	Offset       float64 `json:"offset"`
+	Unit         string  `json:"unit,omitempty"`
+}
+
+// Message is one frame definition.
+type Message struct {
+	// ID is the identifier as the DBC states it; extended IDs carry
+	// bit 31, the same convention as capture.EFFFlag.
+	ID      uint32   `json:"id"`
+	Name    string   `json:"name"`
+	Length  int      `json:"length"`
+	Signals []Signal `json:"signals"`
+}
+
+// Database is a parsed DBC file.
+type Database struct {
+	messages map[uint32]*Message
+}
+
+var (
+	boRe = regexp.MustCompile(`^BO_ (\d+) (\w+)\s*: (\d+) (\w+)`)
+	sgRe = regexp.MustCompile(`^\s*SG_ (\w+)\s*(?:m\d+|M)?\s*: (\d+)\|(\d+)@([01])([+-]) \(([^,]+),([^)]+)\) \[[^\]]*\] "([^"]*)"`)
+)
+
+// Load parses the DBC file at path.
+func Load(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db := &Database{messages: make(map[uint32]*Message)}
+	var current *Message
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		if m := boRe.FindStringSubmatch(sc.Text()); m != nil {
+			id, err := strconv.ParseUint(m[1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("dbc: %s:%d: bad message ID %q", path, line, m[1])
+			}
+			length, _ := strconv.Atoi(m[3])
+			current = &Message{ID: uint32(id), Name: m[2], Length: length}
+			db.messages[current.ID] = current
+			continue
+		}
+		m := sgRe.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("dbc: %s:%d: signal before any message", path, line)
+		}
+		start, _ := strconv.Atoi(m[2])
+		length, _ := strconv.Atoi(m[3])
+		factor, err1 := strconv.ParseFloat(m[6], 64)
+		offset, err2 := strconv.ParseFloat(m[7], 64)
+		if length < 1 || length > 64 || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("dbc: %s:%d: bad signal %s", path, line, m[1])
+		}
+		current.Signals = append(current.Signals, Signal{
+			Name:         m[1],
+			Start:        start,
+			Length:       length,
+			LittleEndian: m[4] == "1",
+			Signed:       m[5] == "-",
+			Factor:       factor,
+			Offset:       offset,
+			Unit:         m[8],
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("dbc: read %s: %w", path, err)
+	}
+	if len(db.messages) == 0 {
+		return nil, fmt.Errorf("dbc: %s defines no messages", path)
+	}
+	return db, nil
+}
+
+// Message looks up a frame definition by wire identifier.
+func (d *Database) Message(id uint32) (*Message, bool) {
+	m, ok := d.messages[id]
+	return m, ok
+}
+
+// Messages returns the number of frame definitions loaded.
+func (d *Database) Messages() int { return len(d.messages) }
+
+// DecodedSignal is one scaled value extracted from a frame.
+type DecodedSignal struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
+// Decoded is a frame rendered through its DBC definition.
+type Decoded struct {
+	Message string          `json:"message"`
+	Signals []DecodedSignal `json:"signals"`
+}
+
+// Decode renders one frame; ok is false when the database has no
+// definition for its identifier. Signals that don't fit the received
+// payload are skipped rather than misread.
+func (d *Database) Decode(f capture.CANFrame) (Decoded, bool) {
+	if f.IsError() {
+		return Decoded{}, false
+	}
+	msg, ok := d.messages[f.ID&^capture.ErrFlag]
+	if !ok {
+		return Decoded{}, false
+	}
+	payload := f.Payload()
+	out := Decoded{Message: msg.Name}
+	for _, s := range msg.Signals {
+		raw, ok := extract(payload, s)
+		if !ok {
+			continue
+		}
+		v := float64(raw)
+		if s.Signed && raw&(1<<(s.Length-1)) != 0 {
+			v = float64(int64(raw) - 1<<s.Length)
+		}
+		out.Signals = append(out.Signals, DecodedSignal{
+			Name:  s.Name,
+			Value: v*s.Factor + s.Offset,
+			Unit:  s.Unit,
+		})
+	}
+	return out, true
+}
+
+// extract pulls a signal's raw bits from the payload. DBC numbers bits
+// LSB-first within each byte; big-endian (Motorola) signals start at
+// their MSB and walk down through the sawtooth.
+func extract(data []byte, s Signal) (uint64, bool) {
+	var raw uint64
+	if s.LittleEndian {
+		if s.Start+s.Length > len(data)*8 {
+			return 0, false
+		}
+		for i := s.Length - 1; i >= 0; i-- {
+			bit := s.Start + i
+			raw = raw<<1 | uint64(data[bit/8]>>(bit%8)&1)
+		}
+		return raw, true
+	}
+	pos := s.Start
+	for i := 0; i < s.Length; i++ {
+		if pos/8 >= len(data) || pos < 0 {
+			return 0, false
+		}
+		raw = raw<<1 | uint64(data[pos/8]>>(pos%8)&1)
+		if pos%8 == 0 {
+			pos += 15
+		} else {
+			pos--
+		}
+	}
+	return raw, true
+}
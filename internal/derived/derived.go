@@ -0,0 +1,106 @@
+// Package derived computes channels that don't come off the bus —
+// acceleration from successive speeds, engine power from fuel burn,
+// instantaneous consumption — and stamps them onto telemetry so they
+// flow through alerts, capture rules, analysis and export exactly like
+// polled channels. Beyond the builtins, channels can be defined in
+// config as arithmetic expressions over existing channel names.
+package derived
+
+import (
+	"sync"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Channel is one computed channel. eval may return ok=false when the
+// inputs it needs aren't present in the sample.
+type Channel struct {
+	Name string
+	Unit string
+	eval func(prev, cur *datastore.TelemetryData) (float64, bool)
+}
+
+// dieselKWhPerL and engineEfficiency turn a fuel burn rate into a crude
+// crankshaft power estimate: ~9.9 kWh/L energy content at ~38% diesel
+// thermal efficiency.
+const (
+	dieselKWhPerL    = 9.9
+	engineEfficiency = 0.38
+)
+
+// Builtins returns the standard derived channels.
+func Builtins() []Channel {
+	return []Channel{
+		{Name: "accel", Unit: "m/s²", eval: func(prev, cur *datastore.TelemetryData) (float64, bool) {
+			if prev == nil {
+				return 0, false
+			}
+			dt := cur.Timestamp.Sub(prev.Timestamp).Seconds()
+			if dt <= 0 || dt >= 10 {
+				return 0, false
+			}
+			return (cur.Speed - prev.Speed) / 3.6 / dt, true
+		}},
+		{Name: "power_kw", Unit: "kW", eval: func(_, cur *datastore.TelemetryData) (float64, bool) {
+			lph := analysis.FuelRateLph(*cur)
+			if lph <= 0 {
+				return 0, false
+			}
+			return lph * dieselKWhPerL * engineEfficiency, true
+		}},
+		{Name: "l_per_100km", Unit: "L/100km", eval: func(_, cur *datastore.TelemetryData) (float64, bool) {
+			lph := analysis.FuelRateLph(*cur)
+			if lph <= 0 || cur.Speed < 5 {
+				return 0, false
+			}
+			return lph / cur.Speed * 100, true
+		}},
+	}
+}
+
+// Engine evaluates a channel list against live telemetry, tracking the
+// previous sample per VIN for rate-based channels. It is safe for
+// concurrent use.
+type Engine struct {
+	channels []Channel
+
+	mu   sync.Mutex
+	prev map[string]datastore.TelemetryData
+}
+
+// NewEngine builds an engine computing the builtins plus any extra
+// channels, evaluated in order so later channels can reference earlier
+// ones by name.
+func NewEngine(extra ...Channel) *Engine {
+	return &Engine{
+		channels: append(Builtins(), extra...),
+		prev:     make(map[string]datastore.TelemetryData),
+	}
+}
+
+// Channels returns the channels this engine computes.
+func (e *Engine) Channels() []Channel { return e.channels }
+
+// Enrich stamps every computable derived channel onto the sample.
+func (e *Engine) Enrich(t *datastore.TelemetryData) {
+	e.mu.Lock()
+	var prev *datastore.TelemetryData
+	if p, ok := e.prev[t.VIN]; ok {
+		prev = &p
+	}
+	e.mu.Unlock()
+
+	for _, c := range e.channels {
+		if v, ok := c.eval(prev, t); ok {
+			if t.Derived == nil {
+				t.Derived = make(map[string]float64)
+			}
+			t.Derived[c.Name] = v
+		}
+	}
+
+	e.mu.Lock()
+	e.prev[t.VIN] = *t
+	e.mu.Unlock()
+}
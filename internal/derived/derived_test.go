@@ -0,0 +1,78 @@
+package derived
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestEngineComputesBuiltins(t *testing.T) {
+	e := NewEngine()
+	base := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+
+	first := datastore.TelemetryData{VIN: "TESTVIN", Timestamp: base, Speed: 50, FuelRate: 8}
+	e.Enrich(&first)
+	if _, ok := first.Derived["accel"]; ok {
+		t.Error("accel computed without a previous sample")
+	}
+	if got := first.Derived["l_per_100km"]; math.Abs(got-16) > 0.01 {
+		t.Errorf("l_per_100km = %.2f, want 16", got)
+	}
+
+	second := datastore.TelemetryData{VIN: "TESTVIN", Timestamp: base.Add(2 * time.Second), Speed: 64, FuelRate: 8}
+	e.Enrich(&second)
+	// 14 km/h over 2s ≈ 1.94 m/s².
+	if got := second.Derived["accel"]; math.Abs(got-1.94) > 0.01 {
+		t.Errorf("accel = %.2f, want ~1.94", got)
+	}
+	if got := second.Derived["power_kw"]; math.Abs(got-8*dieselKWhPerL*engineEfficiency) > 0.01 {
+		t.Errorf("power_kw = %.2f", got)
+	}
+}
+
+func TestCompileExpression(t *testing.T) {
+	ch, err := Compile("custom", "", "(coolant_temp - 40) * 2 + rpm / 100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok := ch.eval(nil, &datastore.TelemetryData{CoolantTemp: 90, RPM: 2000})
+	if !ok || math.Abs(v-120) > 0.001 {
+		t.Errorf("eval = %.2f ok=%v, want 120", v, ok)
+	}
+}
+
+func TestCompileRejectsMalformed(t *testing.T) {
+	for _, expr := range []string{"", "1 +", "(rpm", "rpm ** 2", "1..2"} {
+		if _, err := Compile("bad", "", expr); err == nil {
+			t.Errorf("Compile(%q) accepted a malformed expression", expr)
+		}
+	}
+}
+
+func TestExpressionDivisionByZeroNotStamped(t *testing.T) {
+	ch, err := Compile("econ", "", "fuel_rate / speed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ch.eval(nil, &datastore.TelemetryData{FuelRate: 8, Speed: 0}); ok {
+		t.Error("division by zero reported ok")
+	}
+}
+
+func TestValidateRefs(t *testing.T) {
+	if err := ValidateRefs("rpm / 100 + accel", map[string]bool{"accel": true}); err != nil {
+		t.Errorf("valid refs rejected: %v", err)
+	}
+	if err := ValidateRefs("rmp / 100", nil); err == nil {
+		t.Error("typo'd channel name accepted")
+	}
+}
+
+func TestDerivedResolvesViaChannelValue(t *testing.T) {
+	sample := datastore.TelemetryData{Derived: map[string]float64{"power_kw": 42}}
+	if v, ok := datastore.ChannelValue(sample, "power_kw"); !ok || v != 42 {
+		t.Errorf("ChannelValue(power_kw) = %v, %v; want 42, true", v, ok)
+	}
+}
@@ -0,0 +1,199 @@
+package derived
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Compile parses an arithmetic expression over channel names into a
+// Channel, e.g. "fuel_rate / speed * 100" or "(coolant_temp - 40) * 2".
+// Identifiers resolve through ChannelValue, so expressions can reference
+// polled channels and derived channels computed earlier in the list.
+func Compile(name, unit, expr string) (Channel, error) {
+	p := &parser{input: expr}
+	node, err := p.parseExpr()
+	if err != nil {
+		return Channel{}, fmt.Errorf("derived: channel %q: %w", name, err)
+	}
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return Channel{}, fmt.Errorf("derived: channel %q: unexpected %q", name, p.input[p.pos:])
+	}
+	return Channel{Name: name, Unit: unit, eval: func(_, cur *datastore.TelemetryData) (float64, bool) {
+		return node.eval(*cur)
+	}}, nil
+}
+
+// node is one compiled expression node.
+type node interface {
+	eval(t datastore.TelemetryData) (float64, bool)
+}
+
+type literal float64
+
+func (l literal) eval(datastore.TelemetryData) (float64, bool) { return float64(l), true }
+
+type channelRef string
+
+func (c channelRef) eval(t datastore.TelemetryData) (float64, bool) {
+	return datastore.ChannelValue(t, string(c))
+}
+
+type binary struct {
+	op          byte
+	left, right node
+}
+
+func (b binary) eval(t datastore.TelemetryData) (float64, bool) {
+	l, ok := b.left.eval(t)
+	if !ok {
+		return 0, false
+	}
+	r, ok := b.right.eval(t)
+	if !ok {
+		return 0, false
+	}
+	switch b.op {
+	case '+':
+		return l + r, true
+	case '-':
+		return l - r, true
+	case '*':
+		return l * r, true
+	default: // '/'
+		if r == 0 {
+			return 0, false
+		}
+		return l / r, true
+	}
+}
+
+// parser is a recursive-descent parser for + - * / and parentheses with
+// the usual precedence.
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseFactor() (node, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		p.pos++
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case c == '-':
+		p.pos++
+		inner, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return binary{op: '-', left: literal(0), right: inner}, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", p.input[start:p.pos])
+		}
+		return literal(v), nil
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.input) {
+			r := rune(p.input[p.pos])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				break
+			}
+			p.pos++
+		}
+		return channelRef(p.input[start:p.pos]), nil
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", string(c))
+	}
+}
+
+// ValidateRefs checks that every identifier in expr names a channel
+// ChannelValue or earlier derived channels can resolve, so typos fail at
+// config load.
+func ValidateRefs(expr string, known map[string]bool) error {
+	for _, word := range strings.FieldsFunc(expr, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		if word == "" || unicode.IsDigit(rune(word[0])) {
+			continue
+		}
+		if _, ok := datastore.ChannelValue(datastore.TelemetryData{}, word); ok {
+			continue
+		}
+		if !known[word] {
+			return fmt.Errorf("derived: unknown channel %q in expression", word)
+		}
+	}
+	return nil
+}
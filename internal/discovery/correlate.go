@@ -0,0 +1,114 @@
+package discovery
+
+import (
+	"math"
+	"sort"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// RefSample is one point of a known reference channel (e.g. vehicle
+// speed from OBD polling) used to hunt for the broadcast frame carrying
+// the same signal.
+type RefSample struct {
+	Timestamp int64 // unix nanoseconds
+	Value     float64
+}
+
+// Match ranks one candidate byte position against the reference channel.
+type Match struct {
+	ID          uint32  `json:"id"`
+	Byte        int     `json:"byte"`
+	Correlation float64 `json:"correlation"` // Pearson r, -1..1
+	Samples     int     `json:"samples"`
+}
+
+// minCorrelationSamples is the fewest paired observations worth ranking.
+const minCorrelationSamples = 20
+
+// Correlate compares every (ID, byte) series in the capture against the
+// reference channel and returns matches ordered by |r| descending. The
+// reference is linearly interpolated at each frame's timestamp.
+func Correlate(frames []capture.CANFrame, ref []RefSample) []Match {
+	if len(ref) < 2 {
+		return nil
+	}
+	type series struct {
+		candidate []float64
+		reference []float64
+	}
+	byKey := make(map[uint32]*[8]series)
+	for _, f := range frames {
+		rv, ok := interpolate(ref, f.Timestamp)
+		if !ok {
+			continue
+		}
+		cell := byKey[f.ID]
+		if cell == nil {
+			cell = &[8]series{}
+			byKey[f.ID] = cell
+		}
+		for i := 0; i < int(f.Length) && i < 8; i++ {
+			cell[i].candidate = append(cell[i].candidate, float64(f.Data[i]))
+			cell[i].reference = append(cell[i].reference, rv)
+		}
+	}
+	var matches []Match
+	for id, cell := range byKey {
+		for b := range cell {
+			if len(cell[b].candidate) < minCorrelationSamples {
+				continue
+			}
+			r := pearson(cell[b].candidate, cell[b].reference)
+			if math.IsNaN(r) {
+				continue // constant byte
+			}
+			matches = append(matches, Match{
+				ID: id, Byte: b, Correlation: r, Samples: len(cell[b].candidate),
+			})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return math.Abs(matches[i].Correlation) > math.Abs(matches[j].Correlation)
+	})
+	return matches
+}
+
+// interpolate returns the reference value at ts, linearly interpolated;
+// timestamps outside the reference window are rejected.
+func interpolate(ref []RefSample, ts int64) (float64, bool) {
+	if ts < ref[0].Timestamp || ts > ref[len(ref)-1].Timestamp {
+		return 0, false
+	}
+	i := sort.Search(len(ref), func(i int) bool { return ref[i].Timestamp >= ts })
+	if i == 0 {
+		return ref[0].Value, true
+	}
+	a, b := ref[i-1], ref[i]
+	if b.Timestamp == a.Timestamp {
+		return a.Value, true
+	}
+	frac := float64(ts-a.Timestamp) / float64(b.Timestamp-a.Timestamp)
+	return a.Value + frac*(b.Value-a.Value), true
+}
+
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	var sx, sy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+	}
+	mx, my := sx/n, sy/n
+	var cov, vx, vy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
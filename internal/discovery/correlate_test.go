@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/internal/fixtures"
+)
+
+func TestCorrelateFindsSpeedCarrier(t *testing.T) {
+	s := fixtures.Highway()
+	// Build the reference channel from the speed frames themselves, as
+	// if it came from OBD polling.
+	var ref []RefSample
+	for _, f := range s.Frames {
+		if f.ID == 0x4F0 {
+			ref = append(ref, RefSample{Timestamp: f.Timestamp, Value: float64(f.Data[0])})
+		}
+	}
+	matches := Correlate(s.Frames, ref)
+	if len(matches) == 0 {
+		t.Fatal("no matches")
+	}
+	top := matches[0]
+	if top.ID != 0x4F0 || top.Byte != 0 {
+		t.Fatalf("top match = 0x%03X byte %d (r=%.2f), want 0x4F0 byte 0",
+			top.ID, top.Byte, top.Correlation)
+	}
+	if top.Correlation < 0.99 {
+		t.Errorf("top correlation = %.3f, want ~1", top.Correlation)
+	}
+	// The engine frame tracks speed via gearing, so it should rank too.
+	var foundEngine bool
+	for _, m := range matches[:4] {
+		if m.ID == 0x316 {
+			foundEngine = true
+		}
+	}
+	if !foundEngine {
+		t.Error("engine frame not in top matches despite RPM tracking speed")
+	}
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"sort"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// Interval is a window within a capture, in unix nanoseconds.
+type Interval struct {
+	From, To int64
+}
+
+// ByteDiff reports one byte position whose steady-state value differs
+// between two intervals — the "what changed when I turned the headlights
+// on" output for reverse-engineering body control messages.
+type ByteDiff struct {
+	ID   uint32 `json:"id"`
+	Byte int    `json:"byte"`
+	// ValueA and ValueB are the dominant values in each interval.
+	ValueA byte `json:"value_a"`
+	ValueB byte `json:"value_b"`
+	// ChangedBits masks the bits that flipped between the intervals.
+	ChangedBits byte `json:"changed_bits"`
+	// Noisy marks bytes that weren't steady within one of the intervals
+	// (dominant value under 90% of frames) — likely counters or signals,
+	// not switches.
+	Noisy bool `json:"noisy"`
+}
+
+// byteModes returns, per (ID, byte), the dominant value and whether it
+// was steady across the interval.
+func byteModes(frames []capture.CANFrame, iv Interval) map[uint32]*[8]struct {
+	value  byte
+	steady bool
+	seen   bool
+} {
+	counts := make(map[uint32]*[8]map[byte]int)
+	totals := make(map[uint32]int)
+	for _, f := range frames {
+		if f.Timestamp < iv.From || f.Timestamp >= iv.To {
+			continue
+		}
+		cell := counts[f.ID]
+		if cell == nil {
+			cell = &[8]map[byte]int{}
+			for i := range cell {
+				cell[i] = make(map[byte]int)
+			}
+			counts[f.ID] = cell
+		}
+		totals[f.ID]++
+		for i := 0; i < int(f.Length) && i < 8; i++ {
+			cell[i][f.Data[i]]++
+		}
+	}
+	out := make(map[uint32]*[8]struct {
+		value  byte
+		steady bool
+		seen   bool
+	})
+	for id, cell := range counts {
+		res := &[8]struct {
+			value  byte
+			steady bool
+			seen   bool
+		}{}
+		for b := range cell {
+			best, bestN := byte(0), 0
+			for v, n := range cell[b] {
+				if n > bestN {
+					best, bestN = v, n
+				}
+			}
+			if bestN == 0 {
+				continue
+			}
+			res[b].seen = true
+			res[b].value = best
+			res[b].steady = float64(bestN) >= 0.9*float64(totals[id])
+		}
+		out[id] = res
+	}
+	return out
+}
+
+// Diff compares the steady-state byte values between two intervals of a
+// capture and returns the positions that changed, stable switches first.
+func Diff(frames []capture.CANFrame, a, b Interval) []ByteDiff {
+	modesA := byteModes(frames, a)
+	modesB := byteModes(frames, b)
+	var diffs []ByteDiff
+	for id, cellA := range modesA {
+		cellB, ok := modesB[id]
+		if !ok {
+			continue
+		}
+		for i := range cellA {
+			if !cellA[i].seen || !cellB[i].seen {
+				continue
+			}
+			if cellA[i].value == cellB[i].value {
+				continue
+			}
+			diffs = append(diffs, ByteDiff{
+				ID:          id,
+				Byte:        i,
+				ValueA:      cellA[i].value,
+				ValueB:      cellB[i].value,
+				ChangedBits: cellA[i].value ^ cellB[i].value,
+				Noisy:       !cellA[i].steady || !cellB[i].steady,
+			})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Noisy != diffs[j].Noisy {
+			return !diffs[i].Noisy // clean switches first
+		}
+		if diffs[i].ID != diffs[j].ID {
+			return diffs[i].ID < diffs[j].ID
+		}
+		return diffs[i].Byte < diffs[j].Byte
+	})
+	return diffs
+}
@@ -0,0 +1,31 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func TestDiffFindsToggledBit(t *testing.T) {
+	var frames []capture.CANFrame
+	// 0x2A0 byte 3 bit 2 flips on at t=10s (headlights on); byte 0 is a
+	// counter changing constantly in both intervals.
+	for i := 0; i < 200; i++ {
+		f := capture.CANFrame{ID: 0x2A0, Length: 8, Timestamp: int64(i) * 1e8}
+		f.Data[0] = byte(i)
+		if i >= 100 {
+			f.Data[3] = 0x04
+		}
+		frames = append(frames, f)
+	}
+	diffs := Diff(frames,
+		Interval{From: 0, To: 100 * 1e8},
+		Interval{From: 100 * 1e8, To: 200 * 1e8})
+	if len(diffs) == 0 {
+		t.Fatal("no diffs found")
+	}
+	top := diffs[0]
+	if top.ID != 0x2A0 || top.Byte != 3 || top.ChangedBits != 0x04 || top.Noisy {
+		t.Fatalf("top diff = %+v, want clean bit 2 flip on byte 3", top)
+	}
+}
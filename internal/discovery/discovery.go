@@ -0,0 +1,178 @@
+// Package discovery summarises raw CAN traffic by identifier — rates,
+// DLC distributions and per-byte change heatmaps — and flags IDs missing
+// from the label registry. It's the first step in reverse-engineering
+// the iLoad's proprietary broadcast frames.
+package discovery
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/canid"
+)
+
+// IDStats describes everything observed for one CAN identifier.
+type IDStats struct {
+	ID     uint32  `json:"id"`
+	Label  string  `json:"label,omitempty"`
+	Known  bool    `json:"known"`
+	Count  int     `json:"count"`
+	RateHz float64 `json:"rate_hz"`
+	// Extended marks 29-bit identifiers; a standard and an extended ID
+	// with the same number are tracked separately.
+	Extended bool `json:"extended,omitempty"`
+	// FDCount is how many of the frames were CAN-FD; a mix of classic
+	// and FD on one ID usually means two talkers.
+	FDCount int `json:"fd_count,omitempty"`
+	// DLCs counts frames per data length code.
+	DLCs map[uint8]int `json:"dlcs"`
+	// ByteChanges counts value changes per byte position; a byte that
+	// never changes is padding or a constant, a busy byte is a signal.
+	// The slice is as long as the widest payload seen — 8 for classic
+	// traffic, up to 64 for FD.
+	ByteChanges []int `json:"byte_changes"`
+	// ByteHeat normalises ByteChanges against the frame count, 0..1.
+	ByteHeat []float64 `json:"byte_heat"`
+}
+
+// Approximate wire cost per frame, stuff bits excluded. A classic
+// 11-bit frame is ~47 overhead bits plus 8 per data byte, all at the
+// arbitration rate; an FD frame spends ~30 bits arbitrating and
+// ~28+8n bits in the data phase, which runs at the faster data bitrate
+// when BRS is set.
+const (
+	classicOverheadBits = 47
+	fdArbOverheadBits   = 30
+	fdDataOverheadBits  = 28
+)
+
+// Report is the discovery output for one listening window.
+type Report struct {
+	Duration time.Duration `json:"duration"`
+	IDs      []IDStats     `json:"ids"`
+	// Unknown lists the IDs absent from the registry, busiest first.
+	Unknown []uint32 `json:"unknown"`
+	// ErrorCount is the number of error frames seen during the window.
+	// Anything above zero on a healthy vehicle points at wiring or
+	// termination problems.
+	ErrorCount int `json:"error_count"`
+	// ArbBits and DataBits are the estimated wire bits spent at the
+	// arbitration and (BRS-switched) data bitrates over the window;
+	// feed them to LoadPct for a utilisation figure.
+	ArbBits  int64 `json:"arb_bits"`
+	DataBits int64 `json:"data_bits"`
+}
+
+// LoadPct estimates bus utilisation over the window as a percentage,
+// given the bus bitrates. dataBitrate only matters when FD traffic
+// used bit-rate switching; pass the arbitration rate twice for a
+// classic-only bus. Stuff bits are not modelled, so real load runs a
+// few percent higher.
+func (r *Report) LoadPct(arbBitrate, dataBitrate int) float64 {
+	secs := r.Duration.Seconds()
+	if secs <= 0 || arbBitrate <= 0 {
+		return 0
+	}
+	busy := float64(r.ArbBits) / float64(arbBitrate)
+	if r.DataBits > 0 {
+		if dataBitrate <= 0 {
+			dataBitrate = arbBitrate
+		}
+		busy += float64(r.DataBits) / float64(dataBitrate)
+	}
+	return busy / secs * 100
+}
+
+// Analyze builds a Report from captured frames. reg may be nil, in which
+// case every ID is reported as unknown.
+func Analyze(frames []capture.CANFrame, reg *canid.Registry) *Report {
+	if len(frames) == 0 {
+		return &Report{}
+	}
+	stats := make(map[uint32]*IDStats)
+	last := make(map[uint32][]byte)
+	errorCount := 0
+	var arbBits, dataBits int64
+	for _, f := range frames {
+		if f.IsError() {
+			errorCount++
+			continue
+		}
+		payload := f.Payload()
+		if f.FD {
+			arbBits += fdArbOverheadBits
+			phase := int64(fdDataOverheadBits + 8*len(payload))
+			if f.BRS {
+				dataBits += phase
+			} else {
+				arbBits += phase
+			}
+		} else {
+			arbBits += int64(classicOverheadBits + 8*len(payload))
+		}
+		// Key on the raw ID so the EFF flag keeps standard and extended
+		// traffic apart; report the arbitration ID people recognise.
+		st, ok := stats[f.ID]
+		if !ok {
+			st = &IDStats{ID: f.Arbitration(), Extended: f.IsExtended(), DLCs: make(map[uint8]int)}
+			stats[f.ID] = st
+		} else {
+			prev := last[f.ID]
+			for len(st.ByteChanges) < len(payload) {
+				st.ByteChanges = append(st.ByteChanges, 0)
+			}
+			for i := 0; i < len(payload) && i < len(prev); i++ {
+				if payload[i] != prev[i] {
+					st.ByteChanges[i]++
+				}
+			}
+		}
+		for len(st.ByteChanges) < len(payload) {
+			st.ByteChanges = append(st.ByteChanges, 0)
+		}
+		st.Count++
+		if f.FD {
+			st.FDCount++
+		}
+		st.DLCs[f.Length]++
+		last[f.ID] = append(last[f.ID][:0], payload...)
+	}
+	dur := time.Duration(frames[len(frames)-1].Timestamp - frames[0].Timestamp)
+	rep := &Report{Duration: dur, ErrorCount: errorCount, ArbBits: arbBits, DataBits: dataBits}
+	for _, st := range stats {
+		if reg != nil {
+			st.Label = reg.Lookup(st.ID)
+			st.Known = reg.Known(st.ID)
+		}
+		if secs := dur.Seconds(); secs > 0 {
+			st.RateHz = float64(st.Count) / secs
+		}
+		st.ByteHeat = make([]float64, len(st.ByteChanges))
+		if st.Count > 1 {
+			for i := range st.ByteChanges {
+				st.ByteHeat[i] = float64(st.ByteChanges[i]) / float64(st.Count-1)
+			}
+		}
+		rep.IDs = append(rep.IDs, *st)
+	}
+	sort.Slice(rep.IDs, func(i, j int) bool {
+		a, b := rep.IDs[i], rep.IDs[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return !a.Extended && b.Extended
+	})
+	// Unknown IDs ordered busiest first: the interesting ones.
+	var unknown []IDStats
+	for _, st := range rep.IDs {
+		if !st.Known {
+			unknown = append(unknown, st)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Count > unknown[j].Count })
+	for _, st := range unknown {
+		rep.Unknown = append(rep.Unknown, st.ID)
+	}
+	return rep
+}
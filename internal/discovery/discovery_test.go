@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/fixtures"
+)
+
+func TestAnalyzeHighwayFixture(t *testing.T) {
+	s := fixtures.Highway()
+	rep := Analyze(s.Frames, nil)
+	if len(rep.IDs) != 3 {
+		t.Fatalf("got %d IDs, want 3 (engine, speed, temp)", len(rep.IDs))
+	}
+	for _, st := range rep.IDs {
+		if st.Known {
+			t.Errorf("0x%03X reported known with nil registry", st.ID)
+		}
+		if st.RateHz < 0.9 || st.RateHz > 1.1 {
+			t.Errorf("0x%03X rate = %.2f Hz, want ~1", st.ID, st.RateHz)
+		}
+	}
+	if len(rep.Unknown) != 3 {
+		t.Errorf("unknown list has %d entries, want 3", len(rep.Unknown))
+	}
+}
+
+func TestAnalyzeCountsErrorFrames(t *testing.T) {
+	s := fixtures.Highway()
+	frames := append([]capture.CANFrame{}, s.Frames...)
+	frames = append(frames, capture.CANFrame{
+		ID:        capture.ErrFlag | 0x04, // controller problem
+		Timestamp: frames[len(frames)-1].Timestamp,
+	})
+	rep := Analyze(frames, nil)
+	if rep.ErrorCount != 1 {
+		t.Fatalf("error count = %d, want 1", rep.ErrorCount)
+	}
+	if len(rep.IDs) != 3 {
+		t.Fatalf("error frame leaked into ID stats: %d IDs, want 3", len(rep.IDs))
+	}
+}
+
+func TestAnalyzeSplitsStandardAndExtended(t *testing.T) {
+	frames := []capture.CANFrame{
+		{ID: 0x100, Length: 1, Timestamp: 0},
+		{ID: 0x100 | capture.EFFFlag, Length: 1, Timestamp: 1e9},
+		{ID: 0x100, Length: 1, Timestamp: 2e9},
+	}
+	rep := Analyze(frames, nil)
+	if len(rep.IDs) != 2 {
+		t.Fatalf("got %d IDs, want standard and extended tracked apart", len(rep.IDs))
+	}
+	if rep.IDs[0].Extended || !rep.IDs[1].Extended {
+		t.Errorf("sort order: %+v", rep.IDs)
+	}
+	// Both report the arbitration ID, not the flagged wire value.
+	if rep.IDs[1].ID != 0x100 || rep.IDs[1].Count != 1 {
+		t.Errorf("extended stats = %+v", rep.IDs[1])
+	}
+}
+
+func TestAnalyzeFDFrames(t *testing.T) {
+	var frames []capture.CANFrame
+	for i := 0; i < 10; i++ {
+		f := capture.CANFrame{ID: 0x4A0, Timestamp: int64(i) * 1e9, BRS: true}
+		payload := make([]byte, 16)
+		payload[12] = byte(i) // one moving signal deep in the FD payload
+		f.SetPayload(payload)
+		frames = append(frames, f)
+	}
+	rep := Analyze(frames, nil)
+	if len(rep.IDs) != 1 {
+		t.Fatalf("got %d IDs, want 1", len(rep.IDs))
+	}
+	st := rep.IDs[0]
+	if st.FDCount != 10 {
+		t.Errorf("FD count = %d, want 10", st.FDCount)
+	}
+	if len(st.ByteHeat) != 16 {
+		t.Fatalf("heatmap covers %d bytes, want 16", len(st.ByteHeat))
+	}
+	if st.ByteHeat[12] != 1 || st.ByteHeat[15] != 0 {
+		t.Errorf("heat[12] = %.2f, heat[15] = %.2f", st.ByteHeat[12], st.ByteHeat[15])
+	}
+	if rep.DataBits == 0 {
+		t.Error("BRS frames accounted no data-phase bits")
+	}
+	// 10 frames over 9s is a near-idle bus at 500k/2M.
+	if load := rep.LoadPct(500_000, 2_000_000); load <= 0 || load > 1 {
+		t.Errorf("bus load = %.3f%%, want small but positive", load)
+	}
+}
+
+func TestByteHeatFlagsActiveBytes(t *testing.T) {
+	rep := Analyze(fixtures.Highway().Frames, nil)
+	for _, st := range rep.IDs {
+		if st.ID != 0x4F0 {
+			continue
+		}
+		// Byte 0 carries speed and changes constantly; byte 7 is padding.
+		if st.ByteHeat[0] < 0.3 {
+			t.Errorf("speed byte heat = %.2f, want active", st.ByteHeat[0])
+		}
+		if st.ByteHeat[7] != 0 {
+			t.Errorf("padding byte heat = %.2f, want 0", st.ByteHeat[7])
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package fixtures
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFixturesAreDeterministic(t *testing.T) {
+	a, b := Highway(), Highway()
+	if len(a.Frames) != len(b.Frames) {
+		t.Fatalf("frame counts differ: %d vs %d", len(a.Frames), len(b.Frames))
+	}
+	for i := range a.Frames {
+		if !reflect.DeepEqual(a.Frames[i], b.Frames[i]) {
+			t.Fatalf("frame %d differs between runs", i)
+		}
+	}
+}
+
+func TestFixtureInvariants(t *testing.T) {
+	for name, s := range All() {
+		if len(s.Frames) == 0 {
+			t.Errorf("%s: no frames", name)
+			continue
+		}
+		if s.VIN != VIN {
+			t.Errorf("%s: VIN = %q", name, s.VIN)
+		}
+		prev := s.Frames[0].Timestamp
+		for i, f := range s.Frames[1:] {
+			if f.Timestamp < prev {
+				t.Errorf("%s: frame %d timestamp went backwards", name, i+1)
+				break
+			}
+			prev = f.Timestamp
+		}
+		if s.EndTime < s.StartTime {
+			t.Errorf("%s: EndTime before StartTime", name)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package gps reads NMEA sentences from a serial GPS receiver and keeps
+// the latest fix for fusing into telemetry. The datastore's Location
+// struct is the output shape; the clock package consumes the satellite
+// time for drift correction on Pis without an RTC. gpsd users point the
+// receiver at gpsd's raw NMEA socket — the wire format is the same.
+package gps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// staleAfter is how old a fix may be and still be attached to a
+// telemetry sample; beyond it the receiver has lost the sky and stale
+// coordinates would be worse than none.
+const staleAfter = 5 * time.Second
+
+// knots converts NMEA speed-over-ground to km/h.
+const knots = 1.852
+
+// Receiver consumes an NMEA stream and keeps the most recent fix.
+type Receiver struct {
+	r io.ReadCloser
+	// OnTime is called with the satellite timestamp of each valid RMC
+	// sentence; wire it to the capture clock's CorrectGPS.
+	OnTime func(time.Time)
+
+	mu       sync.Mutex
+	fix      datastore.Location
+	fixAt    time.Time
+	hasFix   bool
+	altitude float64 // from GGA, merged into RMC fixes
+}
+
+// Open connects to a serial GPS at device (default 9600 baud — the NMEA
+// standard rate — when baud is zero).
+func Open(device string, baud int) (*Receiver, error) {
+	if baud == 0 {
+		baud = 9600
+	}
+	p, err := serial.Open(device, &serial.Mode{BaudRate: baud})
+	if err != nil {
+		return nil, fmt.Errorf("gps: open %s: %w", device, err)
+	}
+	return NewReceiver(p), nil
+}
+
+// NewReceiver wraps an open NMEA stream.
+func NewReceiver(r io.ReadCloser) *Receiver { return &Receiver{r: r} }
+
+// Run reads sentences until the stream closes. Malformed or
+// checksum-failed sentences are skipped — cheap receivers emit garbage
+// around power-up.
+func (g *Receiver) Run() {
+	sc := bufio.NewScanner(g.r)
+	for sc.Scan() {
+		g.handle(strings.TrimSpace(sc.Text()))
+	}
+}
+
+// Close closes the underlying stream, ending Run.
+func (g *Receiver) Close() error { return g.r.Close() }
+
+// Fix returns the latest position if it is fresh enough to use.
+func (g *Receiver) Fix() (datastore.Location, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.hasFix || time.Since(g.fixAt) > staleAfter {
+		return datastore.Location{}, false
+	}
+	return g.fix, true
+}
+
+// Annotate attaches the current fix to a telemetry sample, leaving it
+// untouched when there is no fresh fix or it already carries one.
+func (g *Receiver) Annotate(t *datastore.TelemetryData) {
+	if t.Location != nil {
+		return
+	}
+	if fix, ok := g.Fix(); ok {
+		t.Location = &fix
+	}
+}
+
+func (g *Receiver) handle(line string) {
+	fields, ok := checksumFields(line)
+	if !ok || len(fields) == 0 {
+		return
+	}
+	// The talker prefix varies (GP for GPS-only, GN for multi-
+	// constellation); the sentence type is the suffix.
+	switch {
+	case strings.HasSuffix(fields[0], "RMC"):
+		g.handleRMC(fields)
+	case strings.HasSuffix(fields[0], "GGA"):
+		g.handleGGA(fields)
+	}
+}
+
+// handleRMC takes position, speed, course and the satellite timestamp
+// from a Recommended Minimum sentence.
+func (g *Receiver) handleRMC(f []string) {
+	if len(f) < 10 || f[2] != "A" { // V means no fix
+		return
+	}
+	lat, err1 := parseCoord(f[3], f[4])
+	lon, err2 := parseCoord(f[5], f[6])
+	if err1 != nil || err2 != nil {
+		return
+	}
+	speed, _ := strconv.ParseFloat(f[7], 64)
+	course, _ := strconv.ParseFloat(f[8], 64)
+
+	g.mu.Lock()
+	g.fix = datastore.Location{
+		Latitude:  lat,
+		Longitude: lon,
+		Altitude:  g.altitude,
+		Speed:     speed * knots,
+		Course:    course,
+	}
+	g.fixAt = time.Now()
+	g.hasFix = true
+	onTime := g.OnTime
+	g.mu.Unlock()
+
+	if onTime != nil {
+		if ts, err := parseRMCTime(f[1], f[9]); err == nil {
+			onTime(ts)
+		}
+	}
+}
+
+// handleGGA keeps the antenna altitude, which RMC doesn't carry.
+func (g *Receiver) handleGGA(f []string) {
+	if len(f) < 10 || f[6] == "0" { // fix quality 0 means invalid
+		return
+	}
+	alt, err := strconv.ParseFloat(f[9], 64)
+	if err != nil {
+		return
+	}
+	g.mu.Lock()
+	g.altitude = alt
+	g.mu.Unlock()
+}
+
+// checksumFields validates "$...*hh" framing and the XOR checksum,
+// returning the comma-separated fields between them.
+func checksumFields(line string) ([]string, bool) {
+	if len(line) < 4 || line[0] != '$' {
+		return nil, false
+	}
+	star := strings.LastIndexByte(line, '*')
+	if star < 0 || len(line)-star != 3 {
+		return nil, false
+	}
+	want, err := strconv.ParseUint(line[star+1:], 16, 8)
+	if err != nil {
+		return nil, false
+	}
+	var sum byte
+	for i := 1; i < star; i++ {
+		sum ^= line[i]
+	}
+	if sum != byte(want) {
+		return nil, false
+	}
+	return strings.Split(line[1:star], ","), true
+}
+
+// parseCoord converts NMEA ddmm.mmmm / dddmm.mmmm plus hemisphere into
+// signed decimal degrees.
+func parseCoord(value, hemi string) (float64, error) {
+	dot := strings.IndexByte(value, '.')
+	if dot < 3 {
+		return 0, fmt.Errorf("gps: bad coordinate %q", value)
+	}
+	deg, err := strconv.ParseFloat(value[:dot-2], 64)
+	if err != nil {
+		return 0, err
+	}
+	min, err := strconv.ParseFloat(value[dot-2:], 64)
+	if err != nil {
+		return 0, err
+	}
+	coord := deg + min/60
+	switch hemi {
+	case "N", "E":
+	case "S", "W":
+		coord = -coord
+	default:
+		return 0, fmt.Errorf("gps: bad hemisphere %q", hemi)
+	}
+	return coord, nil
+}
+
+// parseRMCTime combines the hhmmss(.ss) and ddmmyy fields into a UTC
+// timestamp.
+func parseRMCTime(hms, dmy string) (time.Time, error) {
+	if i := strings.IndexByte(hms, '.'); i >= 0 {
+		hms = hms[:i]
+	}
+	return time.Parse("150405 020106", hms+" "+dmy)
+}
@@ -0,0 +1,101 @@
+package gps
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	rmcSentence = "$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A"
+	ggaSentence = "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-3 }
+
+func TestRMCFix(t *testing.T) {
+	g := NewReceiver(nil)
+	var gotTime time.Time
+	g.OnTime = func(ts time.Time) { gotTime = ts }
+	g.handle(ggaSentence)
+	g.handle(rmcSentence)
+
+	fix, ok := g.Fix()
+	if !ok {
+		t.Fatal("no fix after valid RMC")
+	}
+	if !near(fix.Latitude, 48.1173) || !near(fix.Longitude, 11.5167) {
+		t.Errorf("position = %v, %v", fix.Latitude, fix.Longitude)
+	}
+	if !near(fix.Speed, 22.4*1.852) {
+		t.Errorf("speed = %v km/h", fix.Speed)
+	}
+	if !near(fix.Course, 84.4) {
+		t.Errorf("course = %v", fix.Course)
+	}
+	if fix.Altitude != 545.4 {
+		t.Errorf("altitude = %v", fix.Altitude)
+	}
+	want := time.Date(1994, time.March, 23, 12, 35, 19, 0, time.UTC)
+	if !gotTime.Equal(want) {
+		t.Errorf("satellite time = %v, want %v", gotTime, want)
+	}
+}
+
+func TestRejectsBadSentences(t *testing.T) {
+	g := NewReceiver(nil)
+	for _, line := range []string{
+		"$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6B", // bad checksum
+		"$GPRMC,123519,V,,,,,,,230394,,*23",                                    // no fix (status V)
+		"not nmea at all",
+		"",
+	} {
+		g.handle(line)
+		if _, ok := g.Fix(); ok {
+			t.Errorf("fix accepted from %q", line)
+		}
+	}
+}
+
+func TestSouthernHemisphere(t *testing.T) {
+	// Sydney-ish coordinates; checksum computed for this sentence.
+	lat, err := parseCoord("3351.500", "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(lat, -33.8583) {
+		t.Errorf("latitude = %v", lat)
+	}
+	if _, err := parseCoord("3351.500", "Q"); err == nil {
+		t.Error("bad hemisphere accepted")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	g := NewReceiver(nil)
+	g.handle(rmcSentence)
+
+	var sample datastore.TelemetryData
+	g.Annotate(&sample)
+	if sample.Location == nil || !near(sample.Location.Latitude, 48.1173) {
+		t.Fatalf("annotated location = %+v", sample.Location)
+	}
+
+	// An existing location is left alone.
+	existing := &datastore.Location{Latitude: 1}
+	sample.Location = existing
+	g.Annotate(&sample)
+	if sample.Location != existing {
+		t.Error("existing location overwritten")
+	}
+
+	// A stale fix is not attached.
+	g.fixAt = time.Now().Add(-time.Minute)
+	var stale datastore.TelemetryData
+	g.Annotate(&stale)
+	if stale.Location != nil {
+		t.Error("stale fix attached")
+	}
+}
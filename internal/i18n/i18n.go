@@ -0,0 +1,130 @@
+// Package i18n translates the user-facing text the daemon generates —
+// alert messages, DTC descriptions, report headings. Messages are keyed
+// and formatted through a catalog so a fleet in another market can ship
+// translations as YAML files instead of patching format strings.
+//
+// A catalog file is one YAML document of key: format-string pairs named
+// after its locale (e.g. locales/de.yaml). Untranslated keys fall back
+// to the built-in English catalog.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// english is the built-in catalog and the fallback for every locale.
+var english = map[string]string{
+	"alert.dtc":      "new trouble code %s",
+	"alert.can_bus":  "CAN interface %s entered %s",
+	"alert.overheat": "coolant trending to %.0f°C in ~%.0f min (now %.1f°C)",
+	"alert.dpf":      "estimated DPF soot load %.0f%% — needs a sustained drive to regenerate",
+	"alert.oil_life": "estimated oil life %.0f%% — book a service",
+	"alert.cranking": "cranking voltage weakening: %d weak starts this month, last minimum %.1fV",
+}
+
+// Catalog holds translations per locale. It is safe for concurrent use.
+type Catalog struct {
+	mu      sync.RWMutex
+	locales map[string]map[string]string
+	locale  string // daemon-wide default locale
+}
+
+// NewCatalog builds a catalog holding only the built-in English.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		locales: map[string]map[string]string{"en": english},
+		locale:  "en",
+	}
+}
+
+// LoadDir merges every <locale>.yaml file in dir into the catalog.
+func (c *Catalog) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("i18n: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("i18n: %w", err)
+		}
+		msgs := map[string]string{}
+		if err := yaml.Unmarshal(data, &msgs); err != nil {
+			return fmt.Errorf("i18n: parse %s: %w", name, err)
+		}
+		locale := strings.TrimSuffix(name, ".yaml")
+		c.mu.Lock()
+		if c.locales[locale] == nil {
+			c.locales[locale] = map[string]string{}
+		}
+		for k, v := range msgs {
+			c.locales[locale][k] = v
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// SetLocale selects the daemon-wide default locale used by T.
+func (c *Catalog) SetLocale(locale string) {
+	c.mu.Lock()
+	c.locale = locale
+	c.mu.Unlock()
+}
+
+// T formats the message for a key in the default locale.
+func (c *Catalog) T(key string, args ...interface{}) string {
+	c.mu.RLock()
+	locale := c.locale
+	c.mu.RUnlock()
+	return c.For(locale, key, args...)
+}
+
+// For formats the message for a key in an explicit locale — callers with
+// a per-request language preference use this instead of T. Unknown
+// locales and untranslated keys fall back to English; unknown keys
+// return the key itself so a missing entry is visible, not silent.
+func (c *Catalog) For(locale, key string, args ...interface{}) string {
+	c.mu.RLock()
+	format, ok := c.locales[locale][key]
+	if !ok {
+		format, ok = c.locales["en"][key]
+	}
+	c.mu.RUnlock()
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Locales lists the locales the catalog can serve.
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []string
+	for locale := range c.locales {
+		out = append(out, locale)
+	}
+	return out
+}
+
+// defaultCatalog is the process-wide catalog, following the clock
+// package's pattern: subsystems that generate text use the package
+// funcs, main configures the instance once at startup.
+var defaultCatalog = NewCatalog()
+
+// Default returns the process-wide catalog.
+func Default() *Catalog { return defaultCatalog }
+
+// T formats a message in the configured daemon locale.
+func T(key string, args ...interface{}) string { return defaultCatalog.T(key, args...) }
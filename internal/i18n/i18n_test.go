@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestForFallsBackToEnglish(t *testing.T) {
+	c := NewCatalog()
+	if got := c.For("de", "alert.dtc", "P0301"); got != "new trouble code P0301" {
+		t.Errorf("got %q", got)
+	}
+	if got := c.For("en", "no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q, want the key itself", got)
+	}
+}
+
+func TestLoadDirAndLocaleSelection(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "de.yaml"),
+		[]byte("alert.dtc: \"neuer Fehlercode %s\"\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCatalog()
+	if err := c.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.For("de", "alert.dtc", "P0301"); got != "neuer Fehlercode P0301" {
+		t.Errorf("got %q", got)
+	}
+	// Untranslated keys in a loaded locale still fall back.
+	if got := c.For("de", "alert.oil_life", 8.0); got != "estimated oil life 8% — book a service" {
+		t.Errorf("fallback = %q", got)
+	}
+	c.SetLocale("de")
+	if got := c.T("alert.dtc", "P0301"); got != "neuer Fehlercode P0301" {
+		t.Errorf("T after SetLocale = %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+// Package j1939 decodes SAE J1939 parameter groups from 29-bit CAN
+// frames — the protocol larger diesel vans and trucks broadcast on
+// instead of answering ISO 15765 requests. Only the broadcast PGNs
+// useful for fleet logging are mapped; J1939 transport protocol
+// (multi-packet) sessions are not reassembled.
+package j1939
+
+import "github.com/anodyne74/iload-obd2/capture"
+
+// Header is the J1939 view of a 29-bit identifier.
+type Header struct {
+	Priority byte
+	// PGN is the parameter group number. PDU1 format (PF < 240)
+	// addresses a destination and excludes PS from the PGN; PDU2 is
+	// broadcast and includes it.
+	PGN    uint32
+	Source byte
+	// Dest is the destination address for PDU1 groups; 0xFF (global)
+	// for PDU2 broadcasts.
+	Dest byte
+}
+
+// pdu2Threshold splits PDU1 (destination-addressed) from PDU2
+// (broadcast) PDU formats.
+const pdu2Threshold = 240
+
+// ParseID splits a 29-bit identifier into its J1939 fields. The second
+// return is false for 11-bit frames, which cannot carry J1939.
+func ParseID(f capture.CANFrame) (Header, bool) {
+	if !f.IsExtended() || f.IsError() {
+		return Header{}, false
+	}
+	id := f.Arbitration()
+	h := Header{
+		Priority: byte(id >> 26 & 0x7),
+		Source:   byte(id),
+		Dest:     0xFF,
+	}
+	pf := byte(id >> 16)
+	ps := byte(id >> 8)
+	if pf < pdu2Threshold {
+		h.PGN = uint32(pf) << 8
+		h.Dest = ps
+	} else {
+		h.PGN = uint32(pf)<<8 | uint32(ps)
+	}
+	// Data page bit extends the PGN space.
+	h.PGN |= id >> 24 & 0x1 << 16
+	return h, true
+}
+
+// Signal is one decoded suspect parameter.
+type Signal struct {
+	SPN   int     `json:"spn"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// SPNDef places one SPN inside its parameter group: Start is the byte
+// offset in the 8-byte data field, raw values are little-endian over
+// Bytes, and value = raw*Scale + Add.
+type SPNDef struct {
+	SPN   int
+	Name  string
+	Unit  string
+	Start int
+	Bytes int
+	Scale float64
+	Add   float64
+}
+
+// Broadcast PGNs worth logging on a working diesel. Scaling follows
+// SAE J1939-71.
+const (
+	PGNEngineTemp       = 65262 // ET1
+	PGNEngineHours      = 65253 // HOURS
+	PGNFuelEconomy      = 65266 // LFE
+	PGNVehicleSpeed     = 65265 // CCVS1
+	PGNAftertreatment   = 64891 // AT1 DPF information
+	PGNEngineController = 61444 // EEC1
+)
+
+var pgnDefs = map[uint32][]SPNDef{
+	PGNEngineController: {
+		{SPN: 190, Name: "engine_speed", Unit: "rpm", Start: 3, Bytes: 2, Scale: 0.125},
+	},
+	PGNEngineTemp: {
+		{SPN: 110, Name: "coolant_temp", Unit: "°C", Start: 0, Bytes: 1, Scale: 1, Add: -40},
+	},
+	PGNEngineHours: {
+		{SPN: 247, Name: "engine_hours", Unit: "h", Start: 0, Bytes: 4, Scale: 0.05},
+	},
+	PGNFuelEconomy: {
+		{SPN: 183, Name: "fuel_rate", Unit: "L/h", Start: 0, Bytes: 2, Scale: 0.05},
+	},
+	PGNVehicleSpeed: {
+		{SPN: 84, Name: "wheel_speed", Unit: "km/h", Start: 1, Bytes: 2, Scale: 1.0 / 256},
+	},
+	PGNAftertreatment: {
+		{SPN: 3719, Name: "dpf_soot_load", Unit: "%", Start: 0, Bytes: 1, Scale: 1},
+	},
+}
+
+// available reports whether a raw value carries data: J1939 reserves
+// the top of each range (0xFF, 0xFE00 and up, …) for "not available"
+// and error indicators.
+func available(raw uint64, bytes int) bool {
+	return raw>>(8*(bytes-1)) < 0xFE
+}
+
+// Decode extracts the known signals from one frame. ok is false when
+// the frame isn't a mapped J1939 parameter group; a mapped group whose
+// values are all "not available" decodes to an empty slice with ok
+// true.
+func Decode(f capture.CANFrame) (Header, []Signal, bool) {
+	h, isJ1939 := ParseID(f)
+	if !isJ1939 {
+		return Header{}, nil, false
+	}
+	defs, ok := pgnDefs[h.PGN]
+	if !ok {
+		return h, nil, false
+	}
+	payload := f.Payload()
+	var sigs []Signal
+	for _, d := range defs {
+		if d.Start+d.Bytes > len(payload) {
+			continue
+		}
+		var raw uint64
+		for i := d.Bytes - 1; i >= 0; i-- {
+			raw = raw<<8 | uint64(payload[d.Start+i])
+		}
+		if !available(raw, d.Bytes) {
+			continue
+		}
+		sigs = append(sigs, Signal{
+			SPN:   d.SPN,
+			Name:  d.Name,
+			Value: float64(raw)*d.Scale + d.Add,
+			Unit:  d.Unit,
+		})
+	}
+	return h, sigs, true
+}
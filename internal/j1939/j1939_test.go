@@ -0,0 +1,77 @@
+package j1939
+
+import (
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// id builds a 29-bit wire identifier from priority, PF, PS and source.
+func id(prio, pf, ps, sa byte) uint32 {
+	return capture.EFFFlag | uint32(prio)<<26 | uint32(pf)<<16 | uint32(ps)<<8 | uint32(sa)
+}
+
+func TestParseIDFormats(t *testing.T) {
+	// PDU2: PF 0xFE includes PS in the PGN, destination is global.
+	h, ok := ParseID(capture.CANFrame{ID: id(6, 0xFE, 0xE5, 0x00)})
+	if !ok || h.PGN != PGNEngineHours || h.Dest != 0xFF || h.Source != 0 {
+		t.Errorf("PDU2 header = %+v", h)
+	}
+	// PDU1: PF 0xEA is destination-addressed, PS excluded from the PGN.
+	h, ok = ParseID(capture.CANFrame{ID: id(6, 0xEA, 0x21, 0xF9)})
+	if !ok || h.PGN != 0xEA00 || h.Dest != 0x21 || h.Source != 0xF9 {
+		t.Errorf("PDU1 header = %+v", h)
+	}
+	// 11-bit frames cannot carry J1939.
+	if _, ok := ParseID(capture.CANFrame{ID: 0x7E8}); ok {
+		t.Error("standard frame parsed as J1939")
+	}
+}
+
+func TestDecodeEngineHours(t *testing.T) {
+	f := capture.CANFrame{ID: id(6, 0xFE, 0xE5, 0x00), Length: 8}
+	// 123456 raw * 0.05 = 6172.8 hours, little-endian.
+	f.Data = [8]byte{0x40, 0xE2, 0x01, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}
+	_, sigs, ok := Decode(f)
+	if !ok || len(sigs) != 1 {
+		t.Fatalf("sigs = %+v, ok %v", sigs, ok)
+	}
+	if sigs[0].SPN != 247 || sigs[0].Value != 6172.8 || sigs[0].Unit != "h" {
+		t.Errorf("engine hours = %+v", sigs[0])
+	}
+}
+
+func TestDecodeFuelRateAndSpeed(t *testing.T) {
+	f := capture.CANFrame{ID: id(6, 0xFE, 0xF2, 0x00), Length: 8}
+	f.Data = [8]byte{0xE8, 0x03} // 1000 * 0.05 = 50 L/h
+	_, sigs, ok := Decode(f)
+	if !ok || len(sigs) != 1 || sigs[0].Value != 50 {
+		t.Fatalf("fuel rate sigs = %+v", sigs)
+	}
+
+	f = capture.CANFrame{ID: id(6, 0xFE, 0xF1, 0x00), Length: 8}
+	f.Data = [8]byte{0x00, 0x00, 0x50} // 0x5000/256 = 80 km/h
+	_, sigs, ok = Decode(f)
+	if !ok || len(sigs) != 1 || sigs[0].Value != 80 {
+		t.Fatalf("wheel speed sigs = %+v", sigs)
+	}
+}
+
+func TestDecodeSkipsNotAvailable(t *testing.T) {
+	f := capture.CANFrame{ID: id(6, 0xFE, 0xEE, 0x00), Length: 8}
+	f.Data = [8]byte{0xFF} // coolant temp not available
+	_, sigs, ok := Decode(f)
+	if !ok {
+		t.Fatal("mapped PGN not recognised")
+	}
+	if len(sigs) != 0 {
+		t.Errorf("unavailable value decoded: %+v", sigs)
+	}
+}
+
+func TestDecodeUnmappedPGN(t *testing.T) {
+	f := capture.CANFrame{ID: id(6, 0xFE, 0xCA, 0x00), Length: 8} // DM1, unmapped
+	if _, _, ok := Decode(f); ok {
+		t.Error("unmapped PGN reported as decoded")
+	}
+}
@@ -0,0 +1,39 @@
+// Package mdns advertises the daemon's API endpoint on the local
+// network as _iload-obd2._tcp, so companion mobile and web apps can
+// discover the Pi without anyone typing an IP address.
+package mdns
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/grandcat/zeroconf"
+)
+
+// serviceType is the mDNS service type companion apps browse for.
+const serviceType = "_iload-obd2._tcp"
+
+// Advertise registers the service and keeps it registered until ctx is
+// cancelled. instance is the human-visible name; empty uses the
+// hostname. The TXT records tell clients where the API and WebSocket
+// live without hardcoding paths in every app.
+func Advertise(ctx context.Context, instance string, port int) error {
+	if instance == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			host = "iload-obd2"
+		}
+		instance = host
+	}
+	txt := []string{"api=/api/v1", "ws=/ws", "openapi=/api/openapi.json"}
+	server, err := zeroconf.Register(instance, serviceType, "local.", port, txt, nil)
+	if err != nil {
+		return fmt.Errorf("mdns: register: %w", err)
+	}
+	log.Printf("mdns: advertising %s as %q on port %d", serviceType, instance, port)
+	<-ctx.Done()
+	server.Shutdown()
+	return nil
+}
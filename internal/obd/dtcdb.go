@@ -0,0 +1,156 @@
+package obd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Severity buckets for triage: "critical" means stop the van, "service"
+// means book it in, "info" is worth knowing before the next service.
+const (
+	SeverityCritical = "critical"
+	SeverityService  = "service"
+	SeverityInfo     = "info"
+)
+
+// genericDTCs covers the SAE-defined codes this fleet actually sees —
+// diesel fuel system, air path, sensors and comms. Not an attempt at
+// the full standard; unknown codes fall back to a family description.
+var genericDTCs = map[string]datastore.DTCInfo{
+	"P0087": {Description: "Fuel rail pressure too low", Severity: SeverityCritical},
+	"P0088": {Description: "Fuel rail pressure too high", Severity: SeverityCritical},
+	"P0090": {Description: "Fuel pressure regulator control circuit", Severity: SeverityService},
+	"P0093": {Description: "Fuel system large leak detected", Severity: SeverityCritical},
+	"P0101": {Description: "Mass air flow sensor range/performance", Severity: SeverityService},
+	"P0102": {Description: "Mass air flow sensor circuit low", Severity: SeverityService},
+	"P0103": {Description: "Mass air flow sensor circuit high", Severity: SeverityService},
+	"P0112": {Description: "Intake air temperature sensor circuit low", Severity: SeverityInfo},
+	"P0113": {Description: "Intake air temperature sensor circuit high", Severity: SeverityInfo},
+	"P0116": {Description: "Coolant temperature sensor range/performance", Severity: SeverityService},
+	"P0117": {Description: "Coolant temperature sensor circuit low", Severity: SeverityService},
+	"P0118": {Description: "Coolant temperature sensor circuit high", Severity: SeverityService},
+	"P0121": {Description: "Throttle position sensor range/performance", Severity: SeverityService},
+	"P0128": {Description: "Coolant temperature below thermostat regulating temperature", Severity: SeverityInfo},
+	"P0191": {Description: "Fuel rail pressure sensor range/performance", Severity: SeverityService},
+	"P0201": {Description: "Injector circuit - cylinder 1", Severity: SeverityCritical},
+	"P0202": {Description: "Injector circuit - cylinder 2", Severity: SeverityCritical},
+	"P0203": {Description: "Injector circuit - cylinder 3", Severity: SeverityCritical},
+	"P0204": {Description: "Injector circuit - cylinder 4", Severity: SeverityCritical},
+	"P0234": {Description: "Turbocharger overboost condition", Severity: SeverityCritical},
+	"P0299": {Description: "Turbocharger underboost condition", Severity: SeverityService},
+	"P0300": {Description: "Random/multiple cylinder misfire detected", Severity: SeverityCritical},
+	"P0301": {Description: "Cylinder 1 misfire detected", Severity: SeverityService},
+	"P0302": {Description: "Cylinder 2 misfire detected", Severity: SeverityService},
+	"P0303": {Description: "Cylinder 3 misfire detected", Severity: SeverityService},
+	"P0304": {Description: "Cylinder 4 misfire detected", Severity: SeverityService},
+	"P0335": {Description: "Crankshaft position sensor circuit", Severity: SeverityCritical},
+	"P0340": {Description: "Camshaft position sensor circuit", Severity: SeverityService},
+	"P0380": {Description: "Glow plug heater circuit", Severity: SeverityService},
+	"P0401": {Description: "EGR flow insufficient", Severity: SeverityService},
+	"P0402": {Description: "EGR flow excessive", Severity: SeverityService},
+	"P0403": {Description: "EGR control circuit", Severity: SeverityService},
+	"P0420": {Description: "Catalyst efficiency below threshold", Severity: SeverityInfo},
+	"P0470": {Description: "Exhaust pressure sensor circuit", Severity: SeverityService},
+	"P0500": {Description: "Vehicle speed sensor circuit", Severity: SeverityService},
+	"P0562": {Description: "System voltage low", Severity: SeverityService},
+	"P0563": {Description: "System voltage high", Severity: SeverityService},
+	"P0670": {Description: "Glow plug module control circuit", Severity: SeverityService},
+	"P2002": {Description: "Diesel particulate filter efficiency below threshold", Severity: SeverityService},
+	"P2263": {Description: "Turbo boost system performance", Severity: SeverityService},
+	"P2453": {Description: "DPF differential pressure sensor range/performance", Severity: SeverityService},
+	"U0001": {Description: "High-speed CAN communication bus fault", Severity: SeverityCritical},
+	"U0100": {Description: "Lost communication with engine control module", Severity: SeverityCritical},
+	"U0121": {Description: "Lost communication with ABS control module", Severity: SeverityService},
+	"U0155": {Description: "Lost communication with instrument cluster", Severity: SeverityInfo},
+}
+
+// hyundaiDTCs extends the generic table with manufacturer-range codes
+// seen on the iLoad/H-1 platform.
+var hyundaiDTCs = map[string]datastore.DTCInfo{
+	"P1120": {Description: "Accelerator position sensor circuit", Severity: SeverityService},
+	"P1145": {Description: "Rail pressure regulator valve stuck", Severity: SeverityCritical},
+	"P1186": {Description: "Fuel pressure monitoring below minimum", Severity: SeverityCritical},
+	"P1586": {Description: "Clutch switch signal circuit", Severity: SeverityInfo},
+	"P1690": {Description: "Immobilizer SMARTRA communication fault", Severity: SeverityService},
+	"P2264": {Description: "Water-in-fuel sensor circuit", Severity: SeverityService},
+}
+
+// familyNames back the fallback description for codes in neither table.
+var familyNames = map[byte]string{
+	'P': "powertrain",
+	'C': "chassis",
+	'B': "body",
+	'U': "network",
+}
+
+var dtcMu sync.RWMutex // guards the extension entries merged at startup
+var extraDTCs = map[string]datastore.DTCInfo{}
+
+// Describe looks a code up in the extension, Hyundai and generic tables
+// in that order, falling back to a family placeholder so callers always
+// get something printable.
+func Describe(code string) datastore.DTCInfo {
+	dtcMu.RLock()
+	info, ok := extraDTCs[code]
+	dtcMu.RUnlock()
+	if !ok {
+		if info, ok = hyundaiDTCs[code]; !ok {
+			info, ok = genericDTCs[code]
+		}
+	}
+	if !ok {
+		var family string
+		if code != "" {
+			family = familyNames[code[0]]
+		}
+		if family == "" {
+			family = "unknown"
+		}
+		info = datastore.DTCInfo{
+			Description: fmt.Sprintf("Unrecognised %s code", family),
+			Severity:    "unknown",
+		}
+	}
+	info.Code = code
+	return info
+}
+
+// DescribeAll maps a code list through Describe.
+func DescribeAll(codes []string) []datastore.DTCInfo {
+	if len(codes) == 0 {
+		return nil
+	}
+	out := make([]datastore.DTCInfo, len(codes))
+	for i, code := range codes {
+		out[i] = Describe(code)
+	}
+	return out
+}
+
+// LoadDescriptions merges a YAML file of extra or overriding code
+// descriptions, keyed by code:
+//
+//	P1186:
+//	  description: Fuel pressure monitoring below minimum
+//	  severity: critical
+func LoadDescriptions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var entries map[string]datastore.DTCInfo
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("obd: parse %s: %w", path, err)
+	}
+	dtcMu.Lock()
+	for code, info := range entries {
+		extraDTCs[code] = info
+	}
+	dtcMu.Unlock()
+	return nil
+}
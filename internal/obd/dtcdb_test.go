@@ -0,0 +1,50 @@
+package obd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestDescribeKnownCodes(t *testing.T) {
+	if info := Describe("P0087"); info.Description == "" || info.Severity != SeverityCritical {
+		t.Errorf("P0087 = %+v", info)
+	}
+	// Hyundai extension table.
+	if info := Describe("P1690"); info.Description == "" || info.Code != "P1690" {
+		t.Errorf("P1690 = %+v", info)
+	}
+}
+
+func TestDescribeFallsBackToFamily(t *testing.T) {
+	info := Describe("C0999")
+	if info.Severity != "unknown" || info.Description != "Unrecognised chassis code" {
+		t.Errorf("C0999 = %+v", info)
+	}
+}
+
+func TestLoadDescriptionsMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dtc.yaml")
+	doc := "P3123:\n  description: Workshop-specific retrofit fault\n  severity: info\n" +
+		"P0087:\n  description: Overridden rail pressure text\n  severity: service\n"
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDescriptions(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		dtcMu.Lock()
+		extraDTCs = map[string]datastore.DTCInfo{}
+		dtcMu.Unlock()
+	})
+	if info := Describe("P3123"); info.Description != "Workshop-specific retrofit fault" {
+		t.Errorf("P3123 = %+v", info)
+	}
+	// The file wins over the built-in tables.
+	if info := Describe("P0087"); info.Severity != SeverityService {
+		t.Errorf("override lost: %+v", info)
+	}
+}
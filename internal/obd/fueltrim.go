@@ -0,0 +1,102 @@
+package obd
+
+import "fmt"
+
+// Fuel system PIDs (mode 01).
+const (
+	pidShortFuelTrim1 = 0x06
+	pidLongFuelTrim1  = 0x07
+	pidShortFuelTrim2 = 0x08
+	pidLongFuelTrim2  = 0x09
+	pidEquivRatio     = 0x44
+	pidO2Wideband1    = 0x34
+)
+
+// FuelTrims are the percent corrections the ECU applies to base
+// fuelling; positive means it is adding fuel. Long term trims drifting
+// past ±10% are the classic early sign of a leaking injector or air
+// path fault.
+type FuelTrims struct {
+	ShortBank1 float64
+	LongBank1  float64
+	ShortBank2 float64
+	LongBank2  float64
+}
+
+// ReadFuelTrims reads PIDs 06–09. Bank 1 must answer; bank 2 is left
+// zero on single-bank engines like the iLoad's D4CB, which simply
+// doesn't support PIDs 08/09.
+func (c *Client) ReadFuelTrims() (FuelTrims, error) {
+	var ft FuelTrims
+	for _, p := range []struct {
+		pid  byte
+		dst  *float64
+		bank int
+	}{
+		{pidShortFuelTrim1, &ft.ShortBank1, 1},
+		{pidLongFuelTrim1, &ft.LongBank1, 1},
+		{pidShortFuelTrim2, &ft.ShortBank2, 2},
+		{pidLongFuelTrim2, &ft.LongBank2, 2},
+	} {
+		data, err := c.uds.Request([]byte{modeLiveData, p.pid})
+		if err != nil {
+			if p.bank == 1 {
+				return ft, err
+			}
+			continue
+		}
+		if len(data) < 2 || data[0] != p.pid {
+			return ft, fmt.Errorf("obd: bad fuel trim response for %02X", p.pid)
+		}
+		*p.dst = DecodeFuelTrim(data[1])
+	}
+	return ft, nil
+}
+
+// DecodeFuelTrim converts a trim byte to percent: 128 is no correction,
+// 0 is -100% and 255 is +99.2%.
+func DecodeFuelTrim(b byte) float64 {
+	return (float64(b) - 128) * 100 / 128
+}
+
+// ReadEquivRatio reads the commanded equivalence ratio λ (PID 0x44):
+// what mixture the ECU is asking for, as a multiple of stoichiometric.
+func (c *Client) ReadEquivRatio() (float64, error) {
+	data, err := c.uds.Request([]byte{modeLiveData, pidEquivRatio})
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 3 || data[0] != pidEquivRatio {
+		return 0, fmt.Errorf("obd: bad equivalence ratio response")
+	}
+	return float64(uint16(data[1])<<8|uint16(data[2])) * 2 / 65536, nil
+}
+
+// WidebandO2 is one wideband oxygen sensor reading: the measured λ and
+// the pump current driving the cell. A healthy diesel idles well lean
+// of λ=1, so values around 2–5 are normal, not a fault.
+type WidebandO2 struct {
+	Lambda    float64
+	CurrentMA float64
+}
+
+// ReadWidebandO2 reads wideband sensor 1 (PID 0x34).
+func (c *Client) ReadWidebandO2() (WidebandO2, error) {
+	data, err := c.uds.Request([]byte{modeLiveData, pidO2Wideband1})
+	if err != nil {
+		return WidebandO2{}, err
+	}
+	if len(data) < 5 || data[0] != pidO2Wideband1 {
+		return WidebandO2{}, fmt.Errorf("obd: bad wideband O2 response")
+	}
+	return DecodeWidebandO2(data[1:5]), nil
+}
+
+// DecodeWidebandO2 decodes the four payload bytes of PIDs 0x34–0x3B:
+// λ over the first pair, pump current in mA over the second.
+func DecodeWidebandO2(data []byte) WidebandO2 {
+	return WidebandO2{
+		Lambda:    float64(uint16(data[0])<<8|uint16(data[1])) * 2 / 65536,
+		CurrentMA: float64(uint16(data[2])<<8|uint16(data[3]))/256 - 128,
+	}
+}
@@ -0,0 +1,52 @@
+package obd
+
+import "testing"
+
+func TestReadFuelTrimsSingleBank(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		// Bank 1 only — PIDs 08/09 go unanswered like on the D4CB.
+		"\x01\x06": {0x41, 0x06, 0x80}, // 0%
+		"\x01\x07": {0x41, 0x07, 0x90}, // +12.5%
+	})
+	ft, err := NewClient(ecu).ReadFuelTrims()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.ShortBank1 != 0 || ft.LongBank1 != 12.5 {
+		t.Errorf("bank 1 = %+v", ft)
+	}
+	if ft.ShortBank2 != 0 || ft.LongBank2 != 0 {
+		t.Errorf("bank 2 should stay zero: %+v", ft)
+	}
+}
+
+func TestDecodeFuelTrim(t *testing.T) {
+	for b, want := range map[byte]float64{0x80: 0, 0x00: -100, 0x70: -12.5} {
+		if got := DecodeFuelTrim(b); got != want {
+			t.Errorf("DecodeFuelTrim(%#x) = %v, want %v", b, got, want)
+		}
+	}
+}
+
+func TestReadEquivRatio(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x01\x44": {0x41, 0x44, 0x80, 0x00}, // λ = 1.0
+	})
+	lambda, err := NewClient(ecu).ReadEquivRatio()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lambda != 1 {
+		t.Errorf("lambda = %v, want 1", lambda)
+	}
+}
+
+func TestDecodeWidebandO2(t *testing.T) {
+	o2 := DecodeWidebandO2([]byte{0xC0, 0x00, 0x80, 0x80})
+	if o2.Lambda != 1.5 {
+		t.Errorf("lambda = %v, want 1.5", o2.Lambda)
+	}
+	if o2.CurrentMA != 0.5 {
+		t.Errorf("current = %v, want 0.5", o2.CurrentMA)
+	}
+}
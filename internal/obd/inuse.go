@@ -0,0 +1,79 @@
+package obd
+
+import (
+	"fmt"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Mode 09 vehicle information, in-use performance tracking infotypes.
+// Spark and compression engines report different monitor sets under
+// different infotypes.
+const (
+	modeVehicleInfo = 0x09
+	infoInUseSpark  = 0x08
+	infoInUseDiesel = 0x0B
+)
+
+// Monitor pair order per SAE J1979, after the OBDCOND/IGNCNTR header.
+var (
+	sparkCounterNames = []string{
+		"catalyst_bank1", "catalyst_bank2",
+		"o2_sensor_bank1", "o2_sensor_bank2",
+		"egr", "secondary_air", "evap",
+		"secondary_o2_bank1", "secondary_o2_bank2",
+	}
+	dieselCounterNames = []string{
+		"nmhc_catalyst", "nox_catalyst", "nox_adsorber",
+		"pm_filter", "exhaust_gas_sensor", "egr_vvt", "boost_pressure",
+	}
+)
+
+// ReadInUsePerformance reads the in-use performance tracking counters,
+// trying the compression-ignition infotype first — the iLoad is a
+// diesel — and falling back to the spark set. The caller stamps VIN
+// and timestamp before persisting.
+func (c *Client) ReadInUsePerformance() (*datastore.InUsePerformance, error) {
+	data, err := c.uds.Request([]byte{modeVehicleInfo, infoInUseDiesel})
+	names := dieselCounterNames
+	if err != nil {
+		if data, err = c.uds.Request([]byte{modeVehicleInfo, infoInUseSpark}); err != nil {
+			return nil, err
+		}
+		names = sparkCounterNames
+	}
+	return DecodeInUsePerformance(data, names)
+}
+
+// DecodeInUsePerformance decodes a mode 09 infotype 08/0B payload
+// (after the mode echo): the infotype echo, a data-item count, then
+// 16-bit big-endian values — OBDCOND and IGNCNTR first, followed by
+// completed/conditions pairs in the given monitor name order.
+func DecodeInUsePerformance(data []byte, names []string) (*datastore.InUsePerformance, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("obd: in-use performance response too short")
+	}
+	n := int(data[1])
+	vals := data[2:]
+	if n < 2 || len(vals) < 2*n {
+		return nil, fmt.Errorf("obd: in-use performance carries %d of %d values", len(vals)/2, n)
+	}
+	u16 := func(i int) int { return int(vals[2*i])<<8 | int(vals[2*i+1]) }
+	p := &datastore.InUsePerformance{
+		OBDConditions:  u16(0),
+		IgnitionCycles: u16(1),
+	}
+	for i := 2; i+1 < n; i += 2 {
+		idx := (i - 2) / 2
+		name := fmt.Sprintf("monitor_%d", idx)
+		if idx < len(names) {
+			name = names[idx]
+		}
+		p.Monitors = append(p.Monitors, datastore.PerformanceCounter{
+			Name:        name,
+			Completions: u16(i),
+			Conditions:  u16(i + 1),
+		})
+	}
+	return p, nil
+}
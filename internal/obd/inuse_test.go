@@ -0,0 +1,54 @@
+package obd
+
+import "testing"
+
+func TestDecodeInUsePerformanceDiesel(t *testing.T) {
+	// Infotype 0x0B echo, 8 values: OBDCOND=500, IGNCNTR=812, then
+	// three monitor pairs.
+	payload := []byte{0x0B, 0x08,
+		0x01, 0xF4, // OBDCOND 500
+		0x03, 0x2C, // IGNCNTR 812
+		0x01, 0x90, 0x01, 0xF0, // nmhc_catalyst 400/496
+		0x01, 0x2C, 0x01, 0xF0, // nox_catalyst 300/496
+		0x00, 0x00, 0x01, 0xF0, // nox_adsorber 0/496
+	}
+	p, err := DecodeInUsePerformance(payload, dieselCounterNames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.OBDConditions != 500 || p.IgnitionCycles != 812 {
+		t.Errorf("header = %d/%d, want 500/812", p.OBDConditions, p.IgnitionCycles)
+	}
+	if len(p.Monitors) != 3 {
+		t.Fatalf("got %d monitors, want 3", len(p.Monitors))
+	}
+	if m := p.Monitors[0]; m.Name != "nmhc_catalyst" || m.Completions != 400 || m.Conditions != 496 {
+		t.Errorf("first monitor = %+v", m)
+	}
+	if m := p.Monitors[2]; m.Name != "nox_adsorber" || m.Completions != 0 {
+		t.Errorf("third monitor = %+v", m)
+	}
+}
+
+func TestDecodeInUsePerformanceNamesRunOut(t *testing.T) {
+	payload := []byte{0x0B, 0x04,
+		0x00, 0x01, 0x00, 0x02,
+		0x00, 0x03, 0x00, 0x04,
+	}
+	p, err := DecodeInUsePerformance(payload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Monitors) != 1 || p.Monitors[0].Name != "monitor_0" {
+		t.Errorf("monitors = %+v", p.Monitors)
+	}
+}
+
+func TestDecodeInUsePerformanceShort(t *testing.T) {
+	if _, err := DecodeInUsePerformance([]byte{0x0B, 0x08, 0x00, 0x01}, nil); err == nil {
+		t.Error("truncated payload accepted")
+	}
+	if _, err := DecodeInUsePerformance([]byte{0x0B}, nil); err == nil {
+		t.Error("headerless payload accepted")
+	}
+}
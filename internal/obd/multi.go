@@ -0,0 +1,147 @@
+package obd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// A functional request is answered by every emissions ECU, each on its
+// own slot in 0x7E8–0x7EF. The uds client keeps only the engine ECU's
+// slot; the collector here keeps them all.
+const (
+	responseIDMin = 0x7E8
+	responseIDMax = 0x7EF
+	// physicalOffset converts a response ID to the responder's physical
+	// request ID (0x7E8 → 0x7E0), needed to address flow control.
+	physicalOffset = 8
+)
+
+// collectQuiet is how long to keep listening after the last response
+// frame; ECUs answer a broadcast within 50ms of each other, so a gap
+// this long means everyone who will answer has.
+const collectQuiet = 150 * time.Millisecond
+
+// ECUName names the well-known responder slots. Only the first two are
+// pinned down by convention; the rest vary by vehicle.
+func ECUName(respID uint32) string {
+	switch respID {
+	case 0x7E8:
+		return "engine"
+	case 0x7E9:
+		return "transmission"
+	default:
+		return fmt.Sprintf("ecu_%d", respID-responseIDMin)
+	}
+}
+
+// MultiRequest broadcasts a legacy-mode request on the functional
+// address and collects every ECU's positive response, keyed by
+// responder ID. Negative responses and foreign traffic are ignored;
+// an empty map with a nil error means nobody answered.
+func MultiRequest(bus transport.Transport, req []byte) (map[uint32][]byte, error) {
+	return MultiRequestContext(context.Background(), bus, req)
+}
+
+// MultiRequestContext is MultiRequest bounded by ctx: cancellation ends
+// collection early, returning whatever responses arrived.
+func MultiRequestContext(ctx context.Context, bus transport.Transport, req []byte) (map[uint32][]byte, error) {
+	if len(req) > 7 {
+		return nil, fmt.Errorf("obd: functional request of %d bytes does not fit a single frame", len(req))
+	}
+	f := capture.CANFrame{ID: FunctionalRequestID, Length: 8, Timestamp: time.Now().UnixNano()}
+	f.Data[0] = byte(len(req))
+	copy(f.Data[1:], req)
+	if err := bus.Send(f); err != nil {
+		return nil, err
+	}
+
+	type reassembly struct {
+		buf  []byte
+		want int
+		seq  byte
+	}
+	open := make(map[uint32]*reassembly)
+	done := make(map[uint32][]byte)
+	// Overall deadline bounds a bus that never goes quiet; the quiet
+	// timer does the real work of ending collection.
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case rf, ok := <-bus.Frames():
+			if !ok {
+				return done, nil
+			}
+			id := rf.Arbitration()
+			if id < responseIDMin || id > responseIDMax {
+				continue
+			}
+			switch rf.Data[0] >> 4 {
+			case 0x0: // single frame
+				n := int(rf.Data[0] & 0x0F)
+				if n >= 1 && rf.Data[1] == req[0]+0x40 {
+					done[id] = append([]byte(nil), rf.Data[2:1+n]...)
+				}
+			case 0x1: // first frame: open reassembly, clear the sender to send
+				r := &reassembly{
+					want: int(rf.Data[0]&0x0F)<<8 | int(rf.Data[1]),
+					buf:  append([]byte(nil), rf.Data[2:8]...),
+					seq:  1,
+				}
+				open[id] = r
+				fc := capture.CANFrame{ID: id - physicalOffset, Length: 8}
+				fc.Data[0] = 0x30
+				if err := bus.Send(fc); err != nil {
+					return nil, err
+				}
+			case 0x2: // consecutive frame
+				r, ok := open[id]
+				if !ok || rf.Data[0]&0x0F != r.seq {
+					delete(open, id)
+					continue
+				}
+				r.seq = (r.seq + 1) & 0x0F
+				r.buf = append(r.buf, rf.Data[1:8]...)
+				if len(r.buf) >= r.want {
+					delete(open, id)
+					if r.buf[0] == req[0]+0x40 {
+						done[id] = r.buf[1:r.want]
+					}
+				}
+			}
+		case <-time.After(collectQuiet):
+			return done, nil
+		case <-deadline:
+			return done, nil
+		case <-ctx.Done():
+			return done, nil
+		}
+	}
+}
+
+// ECUDTCs is one responder's view of its stored trouble codes.
+type ECUDTCs struct {
+	ID    uint32   `json:"id"`
+	Name  string   `json:"name"`
+	Codes []string `json:"codes"`
+}
+
+// ReadDTCsByECU broadcasts a mode 03 read and reports each answering
+// ECU separately — a transmission fault and an engine fault stop
+// blurring into one list.
+func ReadDTCsByECU(bus transport.Transport) ([]ECUDTCs, error) {
+	resps, err := MultiRequest(bus, []byte{modeReadDTCs})
+	if err != nil {
+		return nil, err
+	}
+	var out []ECUDTCs
+	for id, data := range resps {
+		out = append(out, ECUDTCs{ID: id, Name: ECUName(id), Codes: DecodeDTCs(data)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
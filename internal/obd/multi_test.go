@@ -0,0 +1,77 @@
+package obd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// multiECUBus answers a functional broadcast from several responder
+// slots at once, including one multi-frame answer gated on flow
+// control.
+type multiECUBus struct {
+	out chan capture.CANFrame
+	// fcTo records where the collector addressed its flow control.
+	fcTo []uint32
+	// tx holds the transmission ECU's consecutive frames, released by
+	// flow control.
+	tx [][8]byte
+}
+
+func (b *multiECUBus) Frames() <-chan capture.CANFrame { return b.out }
+func (b *multiECUBus) Close() error                    { close(b.out); return nil }
+
+func (b *multiECUBus) Send(f capture.CANFrame) error {
+	if f.ID == FunctionalRequestID && f.Data[1] == modeReadDTCs {
+		// Engine: two codes in a single frame (43 02 01 01 03 00).
+		b.out <- frame(0x7E8, [8]byte{0x06, 0x43, 0x02, 0x01, 0x01, 0x03, 0x00})
+		// Transmission: three codes (8-byte message) need a
+		// first/consecutive pair.
+		b.out <- frame(0x7E9, [8]byte{0x10, 0x08, 0x43, 0x03, 0x07, 0x13, 0x07, 0x18})
+		b.tx = [][8]byte{{0x21, 0x07, 0x1C, 0x00, 0x00}}
+		// A foreign broadcast frame the collector must ignore.
+		b.out <- frame(0x316, [8]byte{0x00})
+		return nil
+	}
+	if f.Data[0]>>4 == 0x3 { // flow control
+		b.fcTo = append(b.fcTo, f.ID)
+		for _, d := range b.tx {
+			b.out <- frame(0x7E9, d)
+		}
+		b.tx = nil
+	}
+	return nil
+}
+
+func frame(id uint32, d [8]byte) capture.CANFrame {
+	return capture.CANFrame{ID: id, Length: 8, Data: d}
+}
+
+func TestReadDTCsByECU(t *testing.T) {
+	bus := &multiECUBus{out: make(chan capture.CANFrame, 16)}
+	views, err := ReadDTCsByECU(bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 2 {
+		t.Fatalf("got %d responders, want 2: %+v", len(views), views)
+	}
+	if views[0].Name != "engine" || !reflect.DeepEqual(views[0].Codes, []string{"P0101", "P0300"}) {
+		t.Errorf("engine view = %+v", views[0])
+	}
+	if views[1].Name != "transmission" ||
+		!reflect.DeepEqual(views[1].Codes, []string{"P0713", "P0718", "P071C"}) {
+		t.Errorf("transmission view = %+v", views[1])
+	}
+	// Flow control must go to the transmission's physical request ID.
+	if len(bus.fcTo) != 1 || bus.fcTo[0] != 0x7E1 {
+		t.Errorf("flow control sent to %#v, want [0x7E1]", bus.fcTo)
+	}
+}
+
+func TestECUName(t *testing.T) {
+	if ECUName(0x7E8) != "engine" || ECUName(0x7E9) != "transmission" || ECUName(0x7EB) != "ecu_3" {
+		t.Error("responder slot names wrong")
+	}
+}
@@ -0,0 +1,244 @@
+// Package obd speaks the legacy OBD-II service set (ISO 15031 modes)
+// on the functional diagnostic address. Where the uds package targets
+// one ECU's physical pair, requests here go to 0x7DF — every emissions
+// ECU hears them — which is how generic scan tools read and clear
+// trouble codes.
+package obd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+	"github.com/anodyne74/iload-obd2/internal/uds"
+)
+
+// FunctionalRequestID is the OBD-II broadcast address every emissions
+// ECU listens on.
+const FunctionalRequestID = 0x7DF
+
+// Legacy service modes.
+const (
+	modeLiveData          = 0x01
+	modeFreezeFrame       = 0x02
+	modeReadDTCs          = 0x03
+	modeClearDTCs         = 0x04
+	modeReadPendingDTCs   = 0x07
+	modeReadPermanentDTCs = 0x0A
+)
+
+// PIDs shared between the live-data and freeze-frame modes.
+const (
+	pidReadiness   = 0x01
+	pidEngineLoad  = 0x04
+	pidCoolantTemp = 0x05
+	pidRPM         = 0x0C
+	pidSpeed       = 0x0D
+)
+
+// Client issues legacy OBD-II requests over a CAN transport.
+type Client struct {
+	uds *uds.Client
+}
+
+// NewClient builds a functional-addressing client on bus. Responses are
+// read from the engine ECU's slot; multi-ECU collection is layered
+// above this.
+func NewClient(bus transport.Transport) *Client {
+	return &Client{uds: uds.NewClient(bus, FunctionalRequestID, uds.DefaultResponseID)}
+}
+
+// NewClient29 builds a functional-addressing client using 29-bit OBD
+// identifiers (ISO 15765-4) for vehicles that don't answer on the
+// 11-bit set.
+func NewClient29(bus transport.Transport) *Client {
+	return &Client{uds: uds.NewClient(bus, uds.FunctionalRequestID29, uds.DefaultResponseID29)}
+}
+
+// WithContext returns a client whose requests are bounded by ctx, so a
+// poll cycle can carry a deadline and a shutdown cancels a wait
+// mid-flight. The receiver is unchanged.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{uds: c.uds.WithContext(ctx)}
+}
+
+// WithScheduler returns a client whose requests queue on s at the given
+// priority, taking turns with every other consumer of the bus. The
+// receiver is unchanged.
+func (c *Client) WithScheduler(s *transport.Scheduler, p transport.Priority) *Client {
+	return &Client{uds: c.uds.WithScheduler(s, p)}
+}
+
+// ReadDTCs reads the stored trouble codes (mode 03).
+func (c *Client) ReadDTCs() ([]string, error) {
+	data, err := c.uds.Request([]byte{modeReadDTCs})
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDTCs(data), nil
+}
+
+// ReadPendingDTCs reads codes seen during the current or last drive
+// cycle but not yet confirmed (mode 07) — faults that haven't lit the
+// MIL, useful for catching intermittent problems early.
+func (c *Client) ReadPendingDTCs() ([]string, error) {
+	data, err := c.uds.Request([]byte{modeReadPendingDTCs})
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDTCs(data), nil
+}
+
+// ReadPermanentDTCs reads codes the ECU refuses to forget (mode 0A):
+// they survive a mode 04 clear until a clean drive cycle proves the
+// fault gone.
+func (c *Client) ReadPermanentDTCs() ([]string, error) {
+	data, err := c.uds.Request([]byte{modeReadPermanentDTCs})
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDTCs(data), nil
+}
+
+// Readiness monitor names, in bit order. The continuous three live in
+// byte B; the non-continuous set in bytes C/D reads differently for
+// spark and compression ignition engines — the iLoad's D4CB is the
+// latter. Blank slots are reserved bits.
+var (
+	continuousMonitors = [3]string{"misfire", "fuel_system", "comprehensive_component"}
+	sparkMonitors      = [8]string{"catalyst", "heated_catalyst", "evap_system", "secondary_air",
+		"ac_refrigerant", "oxygen_sensor", "oxygen_sensor_heater", "egr_system"}
+	dieselMonitors = [8]string{"nmhc_catalyst", "nox_scr", "", "boost_pressure",
+		"", "exhaust_gas_sensor", "pm_filter", "egr_vvt"}
+)
+
+// ReadReadiness reads the I/M readiness snapshot (mode 01 PID 01).
+func (c *Client) ReadReadiness() (*datastore.Readiness, error) {
+	data, err := c.uds.Request([]byte{modeLiveData, pidReadiness})
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 1 || data[0] != pidReadiness {
+		return nil, fmt.Errorf("obd: response for wrong PID")
+	}
+	return DecodeReadiness(data[1:])
+}
+
+// DecodeReadiness decodes the four readiness bytes: MIL state and DTC
+// count, then per-monitor supported/complete bits. Only monitors the
+// ECU reports as supported appear in the result.
+func DecodeReadiness(data []byte) (*datastore.Readiness, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("obd: readiness payload is %d bytes, want 4", len(data))
+	}
+	r := &datastore.Readiness{
+		MILOn:    data[0]&0x80 != 0,
+		DTCCount: int(data[0] & 0x7F),
+	}
+	b := data[1]
+	for i, name := range continuousMonitors {
+		if b&(1<<i) == 0 {
+			continue
+		}
+		r.Monitors = append(r.Monitors, datastore.MonitorStatus{
+			Name:     name,
+			Complete: b&(1<<(i+4)) == 0,
+		})
+	}
+	names := sparkMonitors
+	if b&0x08 != 0 {
+		names = dieselMonitors
+	}
+	for i, name := range names {
+		if name == "" || data[2]&(1<<i) == 0 {
+			continue
+		}
+		r.Monitors = append(r.Monitors, datastore.MonitorStatus{
+			Name:     name,
+			Complete: data[3]&(1<<i) == 0,
+		})
+	}
+	return r, nil
+}
+
+// ReadFreezeFrame reads the operating snapshot latched when a fault set
+// (mode 02, usually frame 0). PIDs the ECU didn't latch are skipped; an
+// error means the ECU holds no freeze frame at all.
+func (c *Client) ReadFreezeFrame(frame byte) (*datastore.FreezeFrame, error) {
+	ff := &datastore.FreezeFrame{}
+	got := false
+	for _, p := range []struct {
+		pid    byte
+		decode func([]byte)
+	}{
+		{pidRPM, func(d []byte) {
+			if len(d) >= 2 {
+				ff.RPM = float64(uint16(d[0])<<8|uint16(d[1])) / 4
+			}
+		}},
+		{pidSpeed, func(d []byte) {
+			if len(d) >= 1 {
+				ff.Speed = float64(d[0])
+			}
+		}},
+		{pidCoolantTemp, func(d []byte) {
+			if len(d) >= 1 {
+				ff.CoolantTemp = float64(d[0]) - 40
+			}
+		}},
+		{pidEngineLoad, func(d []byte) {
+			if len(d) >= 1 {
+				ff.EngineLoad = float64(d[0]) * 100 / 255
+			}
+		}},
+	} {
+		data, err := c.uds.Request([]byte{modeFreezeFrame, p.pid, frame})
+		if err != nil {
+			continue // this PID isn't in the frame
+		}
+		// The response echoes the PID and frame number before the data.
+		if len(data) < 2 || data[0] != p.pid {
+			continue
+		}
+		p.decode(data[2:])
+		got = true
+	}
+	if !got {
+		return nil, fmt.Errorf("obd: no freeze frame %d stored", frame)
+	}
+	return ff, nil
+}
+
+// ClearDTCs erases stored trouble codes and emissions test results
+// (mode 04). The MIL goes out and readiness monitors reset — confirm
+// with a mode 03 re-read and expect the monitors to need a drive cycle.
+func (c *Client) ClearDTCs() error {
+	_, err := c.uds.Request([]byte{modeClearDTCs})
+	return err
+}
+
+// DecodeDTCs decodes a mode 03 response payload (after the 0x43 echo)
+// into P/C/B/U code strings. CAN responses carry a leading count byte,
+// K-line ones don't; an odd payload length is treated as counted.
+func DecodeDTCs(data []byte) []string {
+	if len(data)%2 == 1 {
+		data = data[1:]
+	}
+	var codes []string
+	for i := 0; i+1 < len(data); i += 2 {
+		hi, lo := data[i], data[i+1]
+		if hi == 0 && lo == 0 {
+			continue // padding slot
+		}
+		codes = append(codes, dtcString(hi, lo))
+	}
+	return codes
+}
+
+// dtcString formats one two-byte DTC: the system letter and first digit
+// come from the top nibble, the rest is hex.
+func dtcString(hi, lo byte) string {
+	letters := [4]byte{'P', 'C', 'B', 'U'}
+	return fmt.Sprintf("%c%d%03X", letters[hi>>6], (hi>>4)&0x03, uint16(hi&0x0F)<<8|uint16(lo))
+}
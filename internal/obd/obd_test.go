@@ -0,0 +1,178 @@
+package obd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// singleFrameECU answers functional requests with canned single-frame
+// responses, keyed by the full request payload.
+type singleFrameECU struct {
+	responses map[string][]byte
+	out       chan capture.CANFrame
+}
+
+func newSingleFrameECU(responses map[string][]byte) *singleFrameECU {
+	return &singleFrameECU{responses: responses, out: make(chan capture.CANFrame, 8)}
+}
+
+func (e *singleFrameECU) Frames() <-chan capture.CANFrame { return e.out }
+func (e *singleFrameECU) Close() error                    { close(e.out); return nil }
+
+func (e *singleFrameECU) Send(f capture.CANFrame) error {
+	if f.ID != FunctionalRequestID {
+		return nil
+	}
+	n := int(f.Data[0] & 0x0F)
+	resp, ok := e.responses[string(f.Data[1:1+n])]
+	if !ok {
+		return nil
+	}
+	var d [8]byte
+	d[0] = byte(len(resp))
+	copy(d[1:], resp)
+	e.out <- capture.CANFrame{ID: 0x7E8, Length: 8, Data: d}
+	return nil
+}
+
+func TestReadDTCs(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		// Two stored codes: P0143 and C1234.
+		"\x03": {0x43, 0x02, 0x01, 0x43, 0x52, 0x34},
+	})
+	codes, err := NewClient(ecu).ReadDTCs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"P0143", "C1234"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("codes = %v, want %v", codes, want)
+	}
+}
+
+func TestClearDTCs(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x04": {0x44},
+		"\x03": {0x43, 0x00},
+	})
+	c := NewClient(ecu)
+	if err := c.ClearDTCs(); err != nil {
+		t.Fatal(err)
+	}
+	codes, err := c.ReadDTCs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 0 {
+		t.Errorf("codes after clear = %v", codes)
+	}
+}
+
+func TestPendingAndPermanentDTCs(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x07": {0x47, 0x01, 0x03, 0x00},
+		"\x0A": {0x4A, 0x01, 0x04, 0x20},
+	})
+	c := NewClient(ecu)
+	pending, err := c.ReadPendingDTCs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"P0300"}; !reflect.DeepEqual(pending, want) {
+		t.Errorf("pending = %v, want %v", pending, want)
+	}
+	permanent, err := c.ReadPermanentDTCs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"P0420"}; !reflect.DeepEqual(permanent, want) {
+		t.Errorf("permanent = %v, want %v", permanent, want)
+	}
+}
+
+func TestReadFreezeFrame(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x02\x0C\x00": {0x42, 0x0C, 0x00, 0x1A, 0xF8}, // 1726 rpm
+		"\x02\x0D\x00": {0x42, 0x0D, 0x00, 0x50},       // 80 km/h
+		"\x02\x05\x00": {0x42, 0x05, 0x00, 0x8C},       // 100 °C
+		// Engine load PID deliberately absent from the frame.
+	})
+	ff, err := NewClient(ecu).ReadFreezeFrame(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff.RPM != 1726 || ff.Speed != 80 || ff.CoolantTemp != 100 {
+		t.Errorf("freeze frame = %+v", ff)
+	}
+	if ff.EngineLoad != 0 {
+		t.Errorf("missing PID decoded to %v", ff.EngineLoad)
+	}
+}
+
+func TestReadFreezeFrameAbsent(t *testing.T) {
+	// Every PID rejected: nothing latched.
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x02\x0C\x00": {0x7F, 0x02, 0x31},
+		"\x02\x0D\x00": {0x7F, 0x02, 0x31},
+		"\x02\x05\x00": {0x7F, 0x02, 0x31},
+		"\x02\x04\x00": {0x7F, 0x02, 0x31},
+	})
+	if _, err := NewClient(ecu).ReadFreezeFrame(0); err == nil {
+		t.Error("no error when the ECU holds no freeze frame")
+	}
+}
+
+func TestReadReadinessDiesel(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		// MIL on with 2 codes; compression ignition; all continuous
+		// monitors complete; exhaust gas sensor not yet run.
+		"\x01\x01": {0x41, 0x01, 0x82, 0x0F, 0x6B, 0x20},
+	})
+	r, err := NewClient(ecu).ReadReadiness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.MILOn || r.DTCCount != 2 {
+		t.Errorf("MIL = %v, count = %d", r.MILOn, r.DTCCount)
+	}
+	status := map[string]bool{}
+	for _, m := range r.Monitors {
+		status[m.Name] = m.Complete
+	}
+	if len(status) != 8 {
+		t.Errorf("decoded %d monitors: %v", len(status), status)
+	}
+	if !status["misfire"] || !status["pm_filter"] {
+		t.Errorf("complete monitors misread: %v", status)
+	}
+	if complete, ok := status["exhaust_gas_sensor"]; !ok || complete {
+		t.Errorf("exhaust gas sensor should be supported and incomplete: %v", status)
+	}
+	if _, ok := status["catalyst"]; ok {
+		t.Error("spark-engine monitor decoded for a diesel")
+	}
+}
+
+func TestDecodeReadinessShortPayload(t *testing.T) {
+	if _, err := DecodeReadiness([]byte{0x00, 0x07}); err == nil {
+		t.Error("short payload accepted")
+	}
+}
+
+func TestDecodeDTCs(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{"counted with padding", []byte{0x01, 0x01, 0x43, 0x00, 0x00}, []string{"P0143"}},
+		{"uncounted k-line pairs", []byte{0x81, 0x23, 0xC1, 0x23}, []string{"B0123", "U0123"}},
+		{"empty", nil, nil},
+	}
+	for _, tc := range cases {
+		if got := DecodeDTCs(tc.data); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: DecodeDTCs = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package obd
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PIDDef defines one live-data channel: how to request it and how to
+// turn the response bytes into engineering units. Raw is read big-endian
+// over Bytes; value = raw*Scale + Offset. Mode 0x01 PIDs are the
+// standard set; mode 0x22 entries are manufacturer DIDs read via
+// ReadDataByIdentifier.
+type PIDDef struct {
+	Name   string  `yaml:"name" json:"name"`
+	Mode   byte    `yaml:"mode" json:"mode"`
+	PID    uint16  `yaml:"pid" json:"pid"`
+	Unit   string  `yaml:"unit" json:"unit"`
+	Bytes  int     `yaml:"bytes" json:"bytes"`
+	Scale  float64 `yaml:"scale" json:"scale"`
+	Offset float64 `yaml:"offset" json:"offset"`
+}
+
+// Decode converts a response payload (after the PID echo) to the
+// channel's engineering unit.
+func (d PIDDef) Decode(data []byte) (float64, error) {
+	if len(data) < d.Bytes {
+		return 0, fmt.Errorf("obd: %s response is %d bytes, want %d", d.Name, len(data), d.Bytes)
+	}
+	raw := 0
+	for _, b := range data[:d.Bytes] {
+		raw = raw<<8 | int(b)
+	}
+	return float64(raw)*d.Scale + d.Offset, nil
+}
+
+// Request returns the service request bytes for this PID.
+func (d PIDDef) Request() []byte {
+	if d.Mode == 0x22 {
+		return []byte{d.Mode, byte(d.PID >> 8), byte(d.PID)}
+	}
+	return []byte{d.Mode, byte(d.PID)}
+}
+
+// iloadExtendedPIDs are the Hyundai/Kia manufacturer DIDs known to work
+// on the iLoad/H-1 (community reverse engineering; the factory never
+// published them). All read through mode 0x22.
+var iloadExtendedPIDs = []PIDDef{
+	{Name: "transmission_temp", Mode: 0x22, PID: 0x0146, Unit: "°C", Bytes: 1, Scale: 1, Offset: -40},
+	{Name: "dpf_soot_load", Mode: 0x22, PID: 0x01A2, Unit: "%", Bytes: 1, Scale: 100.0 / 255},
+	{Name: "rail_pressure", Mode: 0x22, PID: 0x012D, Unit: "bar", Bytes: 2, Scale: 0.1},
+	{Name: "egr_position", Mode: 0x22, PID: 0x0132, Unit: "%", Bytes: 1, Scale: 100.0 / 255},
+	{Name: "dpf_diff_pressure", Mode: 0x22, PID: 0x01A3, Unit: "kPa", Bytes: 2, Scale: 0.1},
+}
+
+// standardDieselPIDs are the mode 01 channels that matter on a diesel
+// and that the basic telemetry poll doesn't cover. Manifold pressure is
+// absolute — subtract barometric for boost. EGR error is commanded
+// minus actual as the ECU sees it.
+var standardDieselPIDs = []PIDDef{
+	{Name: "manifold_pressure", Mode: 0x01, PID: 0x0B, Unit: "kPa", Bytes: 1, Scale: 1},
+	{Name: "rail_gauge_pressure", Mode: 0x01, PID: 0x23, Unit: "kPa", Bytes: 2, Scale: 10},
+	{Name: "commanded_egr", Mode: 0x01, PID: 0x2C, Unit: "%", Bytes: 1, Scale: 100.0 / 255},
+	{Name: "egr_error", Mode: 0x01, PID: 0x2D, Unit: "%", Bytes: 1, Scale: 100.0 / 128, Offset: -100},
+}
+
+// fuelSystemPIDs are the mode 01 fuelling channels: the four trims
+// (PIDs 06–09, same decode as egr_error) and the commanded equivalence
+// ratio. The wideband sensor (PID 0x34) packs two values into one
+// response, so it reads through ReadWidebandO2 rather than a catalog
+// entry; the poller special-cases its channel name.
+var fuelSystemPIDs = []PIDDef{
+	{Name: "short_fuel_trim_1", Mode: 0x01, PID: 0x06, Unit: "%", Bytes: 1, Scale: 100.0 / 128, Offset: -100},
+	{Name: "long_fuel_trim_1", Mode: 0x01, PID: 0x07, Unit: "%", Bytes: 1, Scale: 100.0 / 128, Offset: -100},
+	{Name: "short_fuel_trim_2", Mode: 0x01, PID: 0x08, Unit: "%", Bytes: 1, Scale: 100.0 / 128, Offset: -100},
+	{Name: "long_fuel_trim_2", Mode: 0x01, PID: 0x09, Unit: "%", Bytes: 1, Scale: 100.0 / 128, Offset: -100},
+	{Name: "equiv_ratio", Mode: 0x01, PID: 0x44, Unit: "λ", Bytes: 2, Scale: 2.0 / 65536},
+}
+
+// PIDCatalog holds PID definitions by name: the built-in extended set
+// plus whatever the user loads from YAML.
+type PIDCatalog struct {
+	defs  map[string]PIDDef
+	order []string
+}
+
+// DefaultCatalog returns a catalog seeded with the iLoad extended PIDs
+// and the standard diesel set.
+func DefaultCatalog() *PIDCatalog {
+	c := &PIDCatalog{defs: make(map[string]PIDDef)}
+	for _, d := range iloadExtendedPIDs {
+		c.add(d)
+	}
+	for _, d := range standardDieselPIDs {
+		c.add(d)
+	}
+	for _, d := range fuelSystemPIDs {
+		c.add(d)
+	}
+	return c
+}
+
+func (c *PIDCatalog) add(d PIDDef) {
+	if _, exists := c.defs[d.Name]; !exists {
+		c.order = append(c.order, d.Name)
+	}
+	c.defs[d.Name] = d
+}
+
+// LoadFile merges a YAML list of user-defined PIDs; entries with a name
+// already in the catalog replace it.
+func (c *PIDCatalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var defs []PIDDef
+	if err := yaml.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("obd: parse %s: %w", path, err)
+	}
+	for _, d := range defs {
+		if d.Name == "" || d.Mode == 0 || d.Bytes < 1 {
+			return fmt.Errorf("obd: %s: PID entry needs name, mode and bytes", path)
+		}
+		if d.Scale == 0 {
+			d.Scale = 1
+		}
+		c.add(d)
+	}
+	return nil
+}
+
+// Lookup finds a definition by channel name.
+func (c *PIDCatalog) Lookup(name string) (PIDDef, bool) {
+	d, ok := c.defs[name]
+	return d, ok
+}
+
+// Defs lists every definition in load order.
+func (c *PIDCatalog) Defs() []PIDDef {
+	out := make([]PIDDef, 0, len(c.order))
+	for _, name := range c.order {
+		out = append(out, c.defs[name])
+	}
+	return out
+}
+
+// ReadPID requests one catalog channel and decodes the value.
+func (c *Client) ReadPID(d PIDDef) (float64, error) {
+	data, err := c.uds.Request(d.Request())
+	if err != nil {
+		return 0, err
+	}
+	// Strip the PID/DID echo.
+	echo := len(d.Request()) - 1
+	if len(data) < echo {
+		return 0, fmt.Errorf("obd: short response for %s", d.Name)
+	}
+	return d.Decode(data[echo:])
+}
@@ -0,0 +1,103 @@
+package obd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPIDDefDecode(t *testing.T) {
+	rail, ok := DefaultCatalog().Lookup("rail_pressure")
+	if !ok {
+		t.Fatal("rail_pressure missing from default catalog")
+	}
+	// 0x0578 = 1400 raw -> 140.0 bar at 0.1 scale.
+	v, err := rail.Decode([]byte{0x05, 0x78})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 140 {
+		t.Errorf("rail pressure = %v, want 140", v)
+	}
+	if _, err := rail.Decode([]byte{0x05}); err == nil {
+		t.Error("short payload accepted")
+	}
+}
+
+func TestFuelTrimCatalogMatchesDecode(t *testing.T) {
+	trim, ok := DefaultCatalog().Lookup("short_fuel_trim_1")
+	if !ok {
+		t.Fatal("short_fuel_trim_1 missing from default catalog")
+	}
+	// The linear catalog decode must agree with DecodeFuelTrim across
+	// the byte's range.
+	for _, b := range []byte{0x00, 0x80, 0xA0, 0xFF} {
+		v, err := trim.Decode([]byte{b})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := DecodeFuelTrim(b); v != want {
+			t.Errorf("trim(%#02x) = %v, want %v", b, v, want)
+		}
+	}
+}
+
+func TestReadExtendedPID(t *testing.T) {
+	trans, _ := DefaultCatalog().Lookup("transmission_temp")
+	ecu := newSingleFrameECU(map[string][]byte{
+		"\x22\x01\x46": {0x62, 0x01, 0x46, 0x82}, // 0x82-40 = 90 °C
+	})
+	v, err := NewClient(ecu).ReadPID(trans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 90 {
+		t.Errorf("transmission temp = %v, want 90", v)
+	}
+}
+
+func TestCatalogLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pids.yaml")
+	doc := `
+- name: oil_temp
+  mode: 0x22
+  pid: 0x01B3
+  unit: °C
+  bytes: 1
+  offset: -40
+- name: rail_pressure
+  mode: 0x22
+  pid: 0x012D
+  unit: MPa
+  bytes: 2
+  scale: 0.01
+`
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := DefaultCatalog()
+	if err := c.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	oil, ok := c.Lookup("oil_temp")
+	if !ok || oil.Scale != 1 {
+		t.Errorf("oil_temp = %+v, ok %v (scale should default to 1)", oil, ok)
+	}
+	// User entries override built-ins by name.
+	if rail, _ := c.Lookup("rail_pressure"); rail.Unit != "MPa" {
+		t.Errorf("rail_pressure not overridden: %+v", rail)
+	}
+	if n := len(c.Defs()); n != 15 {
+		t.Errorf("catalog has %d defs, want 15", n)
+	}
+}
+
+func TestCatalogRejectsIncompleteEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pids.yaml")
+	if err := os.WriteFile(path, []byte("- name: broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefaultCatalog().LoadFile(path); err == nil {
+		t.Error("entry without mode/bytes accepted")
+	}
+}
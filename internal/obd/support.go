@@ -0,0 +1,57 @@
+package obd
+
+import "fmt"
+
+// Mode 01 support bitmaps: PID 0x00 reports which of PIDs 0x01–0x20
+// the vehicle answers, PID 0x20 covers 0x21–0x40, and so on. The last
+// bit of each window says whether the next window exists.
+const (
+	supportStride  = 0x20
+	lastSupportPID = 0xE0
+)
+
+// ScanSupportedPIDs walks the mode 01 support bitmaps and returns
+// every live-data PID the vehicle claims to answer, ascending. Polling
+// against this list instead of trying everything keeps the bus quiet
+// and the failure counters flat.
+func (c *Client) ScanSupportedPIDs() ([]uint8, error) {
+	var out []uint8
+	for base := uint8(0x00); ; base += supportStride {
+		data, err := c.uds.Request([]byte{modeLiveData, base})
+		if err != nil {
+			// The first window must exist on any OBD-II vehicle; later
+			// windows legitimately stop answering.
+			if base == 0 {
+				return nil, err
+			}
+			break
+		}
+		if len(data) < 5 || data[0] != base {
+			return nil, fmt.Errorf("obd: bad support bitmap response for %02X", base)
+		}
+		pids, more := DecodeSupportBitmap(base, data[1:5])
+		out = append(out, pids...)
+		if !more || base == lastSupportPID {
+			break
+		}
+	}
+	return out, nil
+}
+
+// DecodeSupportBitmap expands one 4-byte window: bit 7 of the first
+// byte is PID base+1 through bit 0 of the last byte at base+32. more
+// reports whether the next window's support PID is flagged.
+func DecodeSupportBitmap(base uint8, bits []byte) (pids []uint8, more bool) {
+	for i := 0; i < 32 && i/8 < len(bits); i++ {
+		if bits[i/8]>>(7-i%8)&1 == 0 {
+			continue
+		}
+		pid := base + uint8(i) + 1
+		if pid == base+supportStride {
+			more = true
+			continue // the continuation flag isn't a data PID
+		}
+		pids = append(pids, pid)
+	}
+	return pids, more
+}
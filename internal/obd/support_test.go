@@ -0,0 +1,38 @@
+package obd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanSupportedPIDs(t *testing.T) {
+	ecu := newSingleFrameECU(map[string][]byte{
+		// Window 0x00 with the continuation bit set; window 0x20
+		// advertises only PID 0x21 and stops there.
+		"\x01\x00": {0x41, 0x00, 0xBE, 0x1F, 0xA8, 0x13},
+		"\x01\x20": {0x41, 0x20, 0x80, 0x00, 0x00, 0x00},
+	})
+	pids, err := NewClient(ecu).ScanSupportedPIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint8{
+		0x01, 0x03, 0x04, 0x05, 0x06, 0x07, 0x0C, 0x0D,
+		0x0E, 0x0F, 0x10, 0x11, 0x13, 0x15, 0x1C, 0x1F,
+		0x21,
+	}
+	if !reflect.DeepEqual(pids, want) {
+		t.Errorf("pids = %02X, want %02X", pids, want)
+	}
+}
+
+func TestDecodeSupportBitmap(t *testing.T) {
+	pids, more := DecodeSupportBitmap(0x00, []byte{0x00, 0x00, 0x00, 0x01})
+	if len(pids) != 0 || !more {
+		t.Errorf("continuation-only bitmap = %02X, more %v", pids, more)
+	}
+	pids, more = DecodeSupportBitmap(0x20, []byte{0x80, 0x00, 0x00, 0x00})
+	if want := []uint8{0x21}; !reflect.DeepEqual(pids, want) || more {
+		t.Errorf("pids = %02X, more %v", pids, more)
+	}
+}
@@ -0,0 +1,90 @@
+package pcap
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// Bridge serves the live frame stream as pcap over TCP, so Wireshark can
+// attach to the running daemon (`wireshark -k -i TCP@host:port`) for
+// protocol debugging without stopping capture.
+type Bridge struct {
+	addr string
+
+	mu      sync.Mutex
+	clients map[net.Conn]chan capture.CANFrame
+}
+
+// NewBridge serves on addr once Run is called.
+func NewBridge(addr string) *Bridge {
+	return &Bridge{addr: addr, clients: make(map[net.Conn]chan capture.CANFrame)}
+}
+
+// Publish fans a frame out to every attached client. Slow clients are
+// dropped rather than allowed to stall the capture path.
+func (b *Bridge) Publish(f capture.CANFrame) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn, ch := range b.clients {
+		select {
+		case ch <- f:
+		default:
+			log.Printf("pcap: dropping slow client %s", conn.RemoteAddr())
+			delete(b.clients, conn)
+			close(ch)
+		}
+	}
+}
+
+// Run listens and serves until ctx is cancelled.
+func (b *Bridge) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", b.addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	log.Printf("pcap: serving live capture on %s", b.addr)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go b.serve(conn)
+	}
+}
+
+func (b *Bridge) serve(conn net.Conn) {
+	ch := make(chan capture.CANFrame, 256)
+	b.mu.Lock()
+	b.clients[conn] = ch
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		if _, ok := b.clients[conn]; ok {
+			delete(b.clients, conn)
+			close(ch)
+		}
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	w := NewWriter(conn)
+	if err := w.WriteHeader(); err != nil {
+		return
+	}
+	for f := range ch {
+		if err := w.WriteFrame(f); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package pcap writes CAN traffic in the classic pcap format with the
+// LINKTYPE_CAN_SOCKETCAN link layer, which Wireshark and tcpdump decode
+// natively.
+package pcap
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// LinkTypeSocketCAN is the pcap link-layer type for SocketCAN frames.
+const LinkTypeSocketCAN = 227
+
+const (
+	magicMicros  = 0xa1b2c3d4
+	versionMajor = 2
+	versionMinor = 4
+	// socketCAN pseudo-header: 4 bytes ID+flags (big-endian), 1 byte
+	// length, 3 bytes padding, then 8 data bytes. FD frames use the
+	// same header but a 64-byte data field; dissectors tell the two
+	// apart by record length.
+	frameLen   = 16
+	fdFrameLen = 72
+	// FD flag bits in the byte after the length, matching struct
+	// canfd_frame.flags.
+	canfdBRS = 0x01
+	canfdESI = 0x02
+	canfdFDF = 0x04
+)
+
+// Writer emits a pcap stream. Write the header once, then any number of
+// frames.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter wraps w; no bytes are written until WriteHeader.
+func NewWriter(w io.Writer) *Writer { return &Writer{w: w} }
+
+// WriteHeader writes the pcap global header.
+func (p *Writer) WriteHeader() error {
+	var hdr [24]byte
+	binary.LittleEndian.PutUint32(hdr[0:], magicMicros)
+	binary.LittleEndian.PutUint16(hdr[4:], versionMajor)
+	binary.LittleEndian.PutUint16(hdr[6:], versionMinor)
+	// thiszone, sigfigs: zero.
+	binary.LittleEndian.PutUint32(hdr[16:], fdFrameLen) // snaplen
+	binary.LittleEndian.PutUint32(hdr[20:], LinkTypeSocketCAN)
+	_, err := p.w.Write(hdr[:])
+	return err
+}
+
+// WriteFrame writes one captured frame as a pcap record. Classic
+// frames become 16-byte can_frame records, FD frames 72-byte
+// canfd_frame records with the BRS/ESI flags set.
+func (p *Writer) WriteFrame(f capture.CANFrame) error {
+	link := linkLayer(f)
+	var hdr [16]byte
+	sec := f.Timestamp / 1e9
+	usec := (f.Timestamp % 1e9) / 1e3
+	binary.LittleEndian.PutUint32(hdr[0:], uint32(sec))
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(usec))
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(link)))  // captured length
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(link))) // original length
+	if _, err := p.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := p.w.Write(link)
+	return err
+}
+
+// linkLayer encodes a frame as the LINKTYPE_CAN_SOCKETCAN packet data
+// shared by the pcap and pcapng writers.
+func linkLayer(f capture.CANFrame) []byte {
+	length := frameLen
+	if f.FD {
+		length = fdFrameLen
+	}
+	rec := make([]byte, length)
+	// The CAN ID in SocketCAN captures is big-endian, unlike the rest
+	// of the pcap encapsulation.
+	binary.BigEndian.PutUint32(rec[0:], f.ID)
+	rec[4] = f.Length
+	if f.FD {
+		rec[5] = canfdFDF
+		if f.BRS {
+			rec[5] |= canfdBRS
+		}
+		if f.ESI {
+			rec[5] |= canfdESI
+		}
+	}
+	copy(rec[8:], f.Payload())
+	return rec
+}
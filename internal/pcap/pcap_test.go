@@ -0,0 +1,84 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func TestWriterLayout(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	f := capture.CANFrame{
+		ID:        0x316,
+		Length:    8,
+		Data:      [8]byte{0, 0, 0x0B, 0xB8},
+		Timestamp: 1_700_000_000_123_456_000,
+	}
+	if err := w.WriteFrame(f); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.Bytes()
+	if len(out) != 24+16+frameLen {
+		t.Fatalf("stream length = %d, want %d", len(out), 24+16+frameLen)
+	}
+	if got := binary.LittleEndian.Uint32(out[0:]); got != magicMicros {
+		t.Fatalf("magic = %#x", got)
+	}
+	if got := binary.LittleEndian.Uint32(out[20:]); got != LinkTypeSocketCAN {
+		t.Fatalf("linktype = %d, want %d", got, LinkTypeSocketCAN)
+	}
+	rec := out[24:]
+	if got := binary.LittleEndian.Uint32(rec[0:]); got != 1_700_000_000 {
+		t.Fatalf("record seconds = %d", got)
+	}
+	if got := binary.LittleEndian.Uint32(rec[4:]); got != 123_456 {
+		t.Fatalf("record microseconds = %d", got)
+	}
+	// CAN ID is big-endian in the SocketCAN encapsulation.
+	if got := binary.BigEndian.Uint32(rec[16:]); got != 0x316 {
+		t.Fatalf("CAN ID = %#x", got)
+	}
+	if rec[20] != 8 || rec[24+2] != 0x0B || rec[24+3] != 0xB8 {
+		t.Fatal("frame payload mangled")
+	}
+}
+
+func TestWriterFDRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	f := capture.CANFrame{ID: 0x4A0, BRS: true, ESI: true}
+	payload := make([]byte, 12)
+	payload[11] = 0xEE
+	f.FD = true
+	f.SetPayload(payload)
+	if err := w.WriteFrame(f); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := buf.Bytes()[24:]
+	if len(rec) != 16+fdFrameLen {
+		t.Fatalf("FD record length = %d, want %d", len(rec), 16+fdFrameLen)
+	}
+	if got := binary.LittleEndian.Uint32(rec[8:]); got != fdFrameLen {
+		t.Fatalf("captured length = %d, want %d", got, fdFrameLen)
+	}
+	if rec[20] != 12 {
+		t.Fatalf("payload length byte = %d", rec[20])
+	}
+	if rec[21] != canfdFDF|canfdBRS|canfdESI {
+		t.Fatalf("flags byte = %#x", rec[21])
+	}
+	if rec[24+11] != 0xEE {
+		t.Fatal("FD payload mangled")
+	}
+}
@@ -0,0 +1,95 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func TestNGWriterLayout(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewNGWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	f := capture.CANFrame{
+		ID:        0x316,
+		Length:    8,
+		Data:      [8]byte{0, 0, 0x0B, 0xB8},
+		Timestamp: 1_700_000_000_123_456_789,
+		Bus:       "can0",
+	}
+	if err := w.WriteFrame(f); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.Bytes()
+	if got := binary.LittleEndian.Uint32(out[0:]); got != blockSectionHeader {
+		t.Fatalf("first block type = %#x", got)
+	}
+	if got := binary.LittleEndian.Uint32(out[8:]); got != byteOrderMagic {
+		t.Fatalf("byte-order magic = %#x", got)
+	}
+	shbLen := binary.LittleEndian.Uint32(out[4:])
+	if got := binary.LittleEndian.Uint32(out[shbLen-4:]); got != shbLen {
+		t.Fatalf("SHB trailing length = %d, want %d", got, shbLen)
+	}
+
+	idb := out[shbLen:]
+	if got := binary.LittleEndian.Uint32(idb[0:]); got != blockInterfaceDesc {
+		t.Fatalf("second block type = %#x", got)
+	}
+	if got := binary.LittleEndian.Uint16(idb[8:]); got != LinkTypeSocketCAN {
+		t.Fatalf("IDB linktype = %d, want %d", got, LinkTypeSocketCAN)
+	}
+	if !bytes.Contains(idb, []byte("can0")) {
+		t.Error("IDB missing if_name option")
+	}
+	idbLen := binary.LittleEndian.Uint32(idb[4:])
+
+	epb := idb[idbLen:]
+	if got := binary.LittleEndian.Uint32(epb[0:]); got != blockEnhancedPacket {
+		t.Fatalf("third block type = %#x", got)
+	}
+	if got := binary.LittleEndian.Uint32(epb[8:]); got != 0 {
+		t.Fatalf("EPB interface ID = %d", got)
+	}
+	ts := uint64(binary.LittleEndian.Uint32(epb[12:]))<<32 |
+		uint64(binary.LittleEndian.Uint32(epb[16:]))
+	if ts != 1_700_000_000_123_456_789 {
+		t.Fatalf("EPB timestamp = %d", ts)
+	}
+	if got := binary.LittleEndian.Uint32(epb[20:]); got != frameLen {
+		t.Fatalf("EPB captured length = %d, want %d", got, frameLen)
+	}
+	// Packet data follows the fixed EPB fields: big-endian CAN ID first.
+	if got := binary.BigEndian.Uint32(epb[28:]); got != 0x316 {
+		t.Fatalf("CAN ID = %#x", got)
+	}
+}
+
+func TestNGWriterInterfacePerBus(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewNGWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	for _, bus := range []string{"can0", "can1", "can0"} {
+		if err := w.WriteFrame(capture.CANFrame{ID: 0x100, Bus: bus}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := 0
+	out := buf.Bytes()
+	for len(out) >= 12 {
+		if binary.LittleEndian.Uint32(out[0:]) == blockInterfaceDesc {
+			count++
+		}
+		out = out[binary.LittleEndian.Uint32(out[4:]):]
+	}
+	if count != 2 {
+		t.Errorf("wrote %d interface blocks, want 2", count)
+	}
+}
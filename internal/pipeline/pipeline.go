@@ -0,0 +1,151 @@
+// Package pipeline decouples the rates at which live telemetry moves
+// through the daemon. The poller offers samples at whatever rate it
+// reads the bus; the pipeline re-emits them to the dashboard broadcast
+// and the datastore at their own configured intervals, aggregating
+// between layers so persisting at 1Hz from a 10Hz poll keeps the means
+// rather than dropping nine samples in ten.
+package pipeline
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Pipeline fans polled telemetry out to its consumers at independent
+// rates. Pacing follows sample timestamps, not the wall clock, so
+// replayed and live data behave identically.
+type Pipeline struct {
+	broadcastEvery time.Duration
+	persistEvery   time.Duration
+
+	// OnBroadcast receives the latest sample at most once per broadcast
+	// interval; OnPersist receives one aggregated sample per persist
+	// interval. Either may be nil.
+	OnBroadcast func(datastore.TelemetryData)
+	OnPersist   func(datastore.TelemetryData)
+
+	mu            sync.Mutex
+	lastBroadcast time.Time
+	agg           aggregate
+}
+
+// New builds a pipeline. A zero interval passes every sample through
+// that layer unchanged.
+func New(broadcastEvery, persistEvery time.Duration) *Pipeline {
+	return &Pipeline{broadcastEvery: broadcastEvery, persistEvery: persistEvery}
+}
+
+// Offer feeds one polled sample through the pipeline.
+func (p *Pipeline) Offer(t datastore.TelemetryData) {
+	p.mu.Lock()
+	broadcast := p.broadcastEvery == 0 || t.Timestamp.Sub(p.lastBroadcast) >= p.broadcastEvery
+	if broadcast {
+		p.lastBroadcast = t.Timestamp
+	}
+	var persist *datastore.TelemetryData
+	if p.persistEvery == 0 {
+		persist = &t
+	} else {
+		p.agg.add(t)
+		if t.Timestamp.Sub(p.agg.start) >= p.persistEvery {
+			out := p.agg.result()
+			p.agg = aggregate{}
+			persist = &out
+		}
+	}
+	p.mu.Unlock()
+
+	if broadcast && p.OnBroadcast != nil {
+		p.OnBroadcast(t)
+	}
+	if persist != nil && p.OnPersist != nil {
+		p.OnPersist(*persist)
+	}
+}
+
+// Flush emits any partially aggregated sample, for shutdown.
+func (p *Pipeline) Flush() {
+	p.mu.Lock()
+	var persist *datastore.TelemetryData
+	if p.agg.count > 0 {
+		out := p.agg.result()
+		p.agg = aggregate{}
+		persist = &out
+	}
+	p.mu.Unlock()
+	if persist != nil && p.OnPersist != nil {
+		p.OnPersist(*persist)
+	}
+}
+
+// aggregate folds a persist interval's samples into one. Continuous
+// channels are averaged; identity and event-like fields (VIN, location,
+// trouble codes, brake) keep their latest or any-true value.
+type aggregate struct {
+	start time.Time
+	count int
+	last  datastore.TelemetryData
+
+	rpm, speed, coolant, load, throttle float64
+	intake, maf, fuelLevel, fuelRate    float64
+	ambient, baro, voltage, soot        float64
+	brake                               bool
+	dtcs                                map[string]bool
+}
+
+func (a *aggregate) add(t datastore.TelemetryData) {
+	if a.count == 0 {
+		a.start = t.Timestamp
+		a.dtcs = make(map[string]bool)
+	}
+	a.count++
+	a.last = t
+	a.rpm += t.RPM
+	a.speed += t.Speed
+	a.coolant += t.CoolantTemp
+	a.load += t.EngineLoad
+	a.throttle += t.ThrottlePos
+	a.intake += t.IntakeTemp
+	a.maf += t.MAF
+	a.fuelLevel += t.FuelLevel
+	a.fuelRate += t.FuelRate
+	a.ambient += t.AmbientTemp
+	a.baro += t.BaroKPa
+	a.voltage += t.Voltage
+	a.soot += t.DPFSootPct
+	a.brake = a.brake || t.Brake
+	for _, code := range t.DTCs {
+		a.dtcs[code] = true
+	}
+}
+
+func (a *aggregate) result() datastore.TelemetryData {
+	out := a.last
+	n := float64(a.count)
+	out.RPM = a.rpm / n
+	out.Speed = a.speed / n
+	out.CoolantTemp = a.coolant / n
+	out.EngineLoad = a.load / n
+	out.ThrottlePos = a.throttle / n
+	out.IntakeTemp = a.intake / n
+	out.MAF = a.maf / n
+	out.FuelLevel = a.fuelLevel / n
+	out.FuelRate = a.fuelRate / n
+	out.AmbientTemp = a.ambient / n
+	out.BaroKPa = a.baro / n
+	out.Voltage = a.voltage / n
+	out.DPFSootPct = a.soot / n
+	out.Brake = a.brake
+	if len(a.dtcs) > 0 {
+		codes := make([]string, 0, len(a.dtcs))
+		for code := range a.dtcs {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		out.DTCs = codes
+	}
+	return out
+}
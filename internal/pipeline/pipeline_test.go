@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func offerRun(p *Pipeline, n int, step time.Duration) {
+	base := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		p.Offer(datastore.TelemetryData{
+			VIN:       "TESTVIN",
+			Timestamp: base.Add(time.Duration(i) * step),
+			RPM:       2000 + float64(i%2)*200, // alternates 2000/2200
+			Speed:     60,
+		})
+	}
+}
+
+func TestPipelineDecouplesRates(t *testing.T) {
+	p := New(500*time.Millisecond, time.Second)
+	var broadcasts, persists int
+	p.OnBroadcast = func(datastore.TelemetryData) { broadcasts++ }
+	p.OnPersist = func(datastore.TelemetryData) { persists++ }
+
+	// 10Hz poll for 3 seconds.
+	offerRun(p, 30, 100*time.Millisecond)
+	if broadcasts < 6 || broadcasts > 7 {
+		t.Errorf("broadcasts = %d, want ~6 at 2Hz over 3s", broadcasts)
+	}
+	if persists != 2 {
+		t.Errorf("persists = %d, want 2 full 1s aggregates", persists)
+	}
+	p.Flush()
+	if persists != 3 {
+		t.Errorf("persists after flush = %d, want 3", persists)
+	}
+}
+
+func TestPipelineAggregatesPersistedSamples(t *testing.T) {
+	p := New(0, time.Second)
+	var got []datastore.TelemetryData
+	p.OnPersist = func(t datastore.TelemetryData) { got = append(got, t) }
+
+	offerRun(p, 11, 100*time.Millisecond)
+	if len(got) != 1 {
+		t.Fatalf("got %d persisted samples, want 1", len(got))
+	}
+	// Alternating 2000/2200 averages near 2100.
+	if got[0].RPM < 2080 || got[0].RPM > 2120 {
+		t.Errorf("aggregated RPM = %.0f, want ~2100", got[0].RPM)
+	}
+	if got[0].Speed != 60 {
+		t.Errorf("aggregated speed = %.0f, want 60", got[0].Speed)
+	}
+}
+
+func TestPipelineZeroIntervalsPassThrough(t *testing.T) {
+	p := New(0, 0)
+	var broadcasts, persists int
+	p.OnBroadcast = func(datastore.TelemetryData) { broadcasts++ }
+	p.OnPersist = func(datastore.TelemetryData) { persists++ }
+	offerRun(p, 5, 100*time.Millisecond)
+	if broadcasts != 5 || persists != 5 {
+		t.Errorf("got %d broadcasts, %d persists, want 5 of each", broadcasts, persists)
+	}
+}
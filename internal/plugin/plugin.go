@@ -0,0 +1,257 @@
+// Package plugin embeds a Lua interpreter so advanced users can extend
+// the daemon at runtime — custom CAN decoders, derived channels and
+// alert logic — by dropping scripts into a directory instead of
+// recompiling. A script defines any of these hooks:
+//
+//	function decode_frame(id, data)  -- data is a byte array table
+//	  if id == 0x620 then return { aux_temp = data[2] - 40 } end
+//	end
+//
+//	function derive(sample)          -- sample is a channel→value table
+//	  return { rpm_per_kmh = sample.rpm / sample.speed }
+//	end
+//
+//	function check_alert(sample)
+//	  if sample.coolant_temp > 115 then
+//	    return { type = "lua_overheat", message = "coolant critical" }
+//	  end
+//	end
+//
+//	function compute_key(level, seed) -- SecurityAccess seed→key
+//	  return { seed[1] ~ 0xFF, seed[2] ~ 0xFF }
+//	end
+//
+// Values returned from decode_frame and derive land in the sample's
+// Derived map and resolve like any other channel.
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// sampleChannels is the channel set exposed to scripts, resolved
+// through ChannelValue so scripts and alert rules agree on names.
+var sampleChannels = []string{
+	"rpm", "speed", "coolant_temp", "engine_load", "throttle_pos",
+	"intake_temp", "maf", "fuel_level", "fuel_rate", "ambient_temp",
+	"baro_kpa", "voltage", "dpf_soot",
+}
+
+// script is one loaded Lua file with its own interpreter state.
+type script struct {
+	name                                   string
+	L                                      *lua.LState
+	hasDecode, hasDerive, hasCheck, hasKey bool
+}
+
+// Engine runs every loaded script against live data. Lua states are not
+// safe for concurrent use, so calls are serialised.
+type Engine struct {
+	mu      sync.Mutex
+	scripts []*script
+}
+
+// Load reads every .lua file in dir. A script that fails to parse is
+// fatal — a typo should stop the daemon at startup, not silently drop a
+// user's alert logic.
+func Load(dir string) (*Engine, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: %w", err)
+	}
+	e := &Engine{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lua") {
+			continue
+		}
+		L := lua.NewState()
+		path := filepath.Join(dir, entry.Name())
+		if err := L.DoFile(path); err != nil {
+			L.Close()
+			e.Close()
+			return nil, fmt.Errorf("plugin: %s: %w", entry.Name(), err)
+		}
+		s := &script{
+			name:      entry.Name(),
+			L:         L,
+			hasDecode: L.GetGlobal("decode_frame") != lua.LNil,
+			hasDerive: L.GetGlobal("derive") != lua.LNil,
+			hasCheck:  L.GetGlobal("check_alert") != lua.LNil,
+			hasKey:    L.GetGlobal("compute_key") != lua.LNil,
+		}
+		e.scripts = append(e.scripts, s)
+		log.Printf("plugin: loaded %s", entry.Name())
+	}
+	return e, nil
+}
+
+// Scripts returns the names of the loaded scripts.
+func (e *Engine) Scripts() []string {
+	var names []string
+	for _, s := range e.scripts {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// DecodeFrame offers a raw CAN frame to every decode_frame hook and
+// merges the channels they return.
+func (e *Engine) DecodeFrame(id uint32, data []byte) map[string]float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out map[string]float64
+	for _, s := range e.scripts {
+		if !s.hasDecode {
+			continue
+		}
+		bytes := s.L.NewTable()
+		for i, b := range data {
+			bytes.RawSetInt(i+1, lua.LNumber(b))
+		}
+		ret := s.call(s.L.GetGlobal("decode_frame"), lua.LNumber(id), bytes)
+		out = mergeTable(out, ret)
+	}
+	return out
+}
+
+// Derive offers a telemetry sample to every derive hook and merges the
+// channels they return; the caller stamps them into the sample's
+// Derived map.
+func (e *Engine) Derive(t datastore.TelemetryData) map[string]float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out map[string]float64
+	for _, s := range e.scripts {
+		if !s.hasDerive {
+			continue
+		}
+		ret := s.call(s.L.GetGlobal("derive"), s.sampleTable(t))
+		out = mergeTable(out, ret)
+	}
+	return out
+}
+
+// CheckAlert offers a sample to every check_alert hook, returning one
+// alert per script that raised one.
+func (e *Engine) CheckAlert(t datastore.TelemetryData) []datastore.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out []datastore.Alert
+	for _, s := range e.scripts {
+		if !s.hasCheck {
+			continue
+		}
+		tbl, ok := s.call(s.L.GetGlobal("check_alert"), s.sampleTable(t)).(*lua.LTable)
+		if !ok {
+			continue
+		}
+		a := datastore.Alert{
+			VIN:       t.VIN,
+			Type:      lua.LVAsString(tbl.RawGetString("type")),
+			Message:   lua.LVAsString(tbl.RawGetString("message")),
+			Timestamp: t.Timestamp,
+		}
+		if a.Type == "" {
+			a.Type = "plugin"
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// ComputeKey implements uds.KeyProvider with the first script that
+// defines a compute_key hook. Seed bytes arrive as a 1-indexed table;
+// the hook returns the key the same way.
+func (e *Engine) ComputeKey(level byte, seed []byte) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range e.scripts {
+		if !s.hasKey {
+			continue
+		}
+		bytes := s.L.NewTable()
+		for i, b := range seed {
+			bytes.RawSetInt(i+1, lua.LNumber(b))
+		}
+		tbl, ok := s.call(s.L.GetGlobal("compute_key"), lua.LNumber(level), bytes).(*lua.LTable)
+		if !ok {
+			return nil, fmt.Errorf("plugin: %s: compute_key returned no key", s.name)
+		}
+		var key []byte
+		for i := 1; ; i++ {
+			v, ok := tbl.RawGetInt(i).(lua.LNumber)
+			if !ok {
+				break
+			}
+			key = append(key, byte(v))
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("plugin: no script defines compute_key")
+}
+
+// Close releases every interpreter.
+func (e *Engine) Close() {
+	for _, s := range e.scripts {
+		s.L.Close()
+	}
+	e.scripts = nil
+}
+
+// call invokes a hook, logging rather than propagating script errors so
+// one bad plugin can't take the pipeline down.
+func (s *script) call(fn lua.LValue, args ...lua.LValue) lua.LValue {
+	if err := s.L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, args...); err != nil {
+		log.Printf("plugin: %s: %v", s.name, err)
+		return lua.LNil
+	}
+	ret := s.L.Get(-1)
+	s.L.Pop(1)
+	return ret
+}
+
+// sampleTable exposes a telemetry sample to Lua as a channel→value
+// table, including derived channels.
+func (s *script) sampleTable(t datastore.TelemetryData) *lua.LTable {
+	tbl := s.L.NewTable()
+	tbl.RawSetString("vin", lua.LString(t.VIN))
+	for _, name := range sampleChannels {
+		if v, ok := datastore.ChannelValue(t, name); ok {
+			tbl.RawSetString(name, lua.LNumber(v))
+		}
+	}
+	for name, v := range t.Derived {
+		tbl.RawSetString(name, lua.LNumber(v))
+	}
+	return tbl
+}
+
+// mergeTable folds a hook's returned table into the accumulated channel
+// map, ignoring non-table returns and non-numeric values.
+func mergeTable(into map[string]float64, ret lua.LValue) map[string]float64 {
+	tbl, ok := ret.(*lua.LTable)
+	if !ok {
+		return into
+	}
+	tbl.ForEach(func(k, v lua.LValue) {
+		name := lua.LVAsString(k)
+		num, ok := v.(lua.LNumber)
+		if name == "" || !ok {
+			return
+		}
+		if into == nil {
+			into = make(map[string]float64)
+		}
+		into[name] = float64(num)
+	})
+	return into
+}
@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const testScript = `
+function decode_frame(id, data)
+  if id == 0x620 then
+    return { aux_temp = data[2] - 40 }
+  end
+end
+
+function derive(sample)
+  if sample.speed > 0 then
+    return { rpm_per_kmh = sample.rpm / sample.speed }
+  end
+end
+
+function check_alert(sample)
+  if sample.coolant_temp > 115 then
+    return { type = "lua_overheat", message = "coolant critical" }
+  end
+end
+
+function compute_key(level, seed)
+  local key = {}
+  for i, b in ipairs(seed) do
+    key[i] = 255 - b
+  end
+  return key
+end
+`
+
+func loadTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.lua"), []byte(testScript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+	return e
+}
+
+func TestDecodeFrame(t *testing.T) {
+	e := loadTestEngine(t)
+	got := e.DecodeFrame(0x620, []byte{0x00, 0x78, 0x00})
+	if v, ok := got["aux_temp"]; !ok || v != 80 {
+		t.Errorf("aux_temp = %v, %v; want 80", v, ok)
+	}
+	if got := e.DecodeFrame(0x316, []byte{0x00, 0x78}); got != nil {
+		t.Errorf("unmatched ID decoded channels: %v", got)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	e := loadTestEngine(t)
+	got := e.Derive(datastore.TelemetryData{RPM: 2000, Speed: 100})
+	if v := got["rpm_per_kmh"]; math.Abs(v-20) > 0.001 {
+		t.Errorf("rpm_per_kmh = %.2f, want 20", v)
+	}
+}
+
+func TestCheckAlert(t *testing.T) {
+	e := loadTestEngine(t)
+	now := time.Now()
+	alerts := e.CheckAlert(datastore.TelemetryData{VIN: "TESTVIN", Timestamp: now, CoolantTemp: 120})
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(alerts))
+	}
+	if alerts[0].Type != "lua_overheat" || alerts[0].VIN != "TESTVIN" {
+		t.Errorf("alert = %+v", alerts[0])
+	}
+	if got := e.CheckAlert(datastore.TelemetryData{CoolantTemp: 90}); len(got) != 0 {
+		t.Errorf("alert raised at 90°C: %+v", got)
+	}
+}
+
+func TestComputeKey(t *testing.T) {
+	e := loadTestEngine(t)
+	key, err := e.ComputeKey(0x01, []byte{0xDE, 0xAD})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 2 || key[0] != 0x21 || key[1] != 0x52 {
+		t.Errorf("key = % X, want 21 52", key)
+	}
+}
+
+func TestComputeKeyWithoutHook(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "empty.lua"), []byte("-- no hooks"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+	if _, err := e.ComputeKey(0x01, []byte{0x01}); err == nil {
+		t.Error("missing compute_key hook succeeded")
+	}
+}
+
+func TestLoadRejectsBrokenScript(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.lua"), []byte("function ("), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("broken script loaded without error")
+	}
+}
@@ -0,0 +1,328 @@
+// Package poll drives the live OBD-II telemetry loop: one request
+// cycle per interval over the shared bus, each cycle assembled into a
+// TelemetryData sample and handed to the daemon's ingest path. The
+// pipeline package downstream decouples broadcast and persist rates
+// from the cycle rate configured here.
+package poll
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/clock"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/obd"
+	"github.com/anodyne74/iload-obd2/internal/power"
+	"github.com/anodyne74/iload-obd2/internal/uds"
+)
+
+// coreChannels are the mode 01 PIDs every cycle reads; they fill the
+// named TelemetryData fields the rest of the daemon keys off.
+var coreChannels = []struct {
+	def   obd.PIDDef
+	apply func(*datastore.TelemetryData, float64)
+}{
+	{obd.PIDDef{Name: "rpm", Mode: 0x01, PID: 0x0C, Bytes: 2, Scale: 0.25},
+		func(t *datastore.TelemetryData, v float64) { t.RPM = v }},
+	{obd.PIDDef{Name: "speed", Mode: 0x01, PID: 0x0D, Bytes: 1, Scale: 1},
+		func(t *datastore.TelemetryData, v float64) { t.Speed = v }},
+	{obd.PIDDef{Name: "coolant_temp", Mode: 0x01, PID: 0x05, Bytes: 1, Scale: 1, Offset: -40},
+		func(t *datastore.TelemetryData, v float64) { t.CoolantTemp = v }},
+	{obd.PIDDef{Name: "engine_load", Mode: 0x01, PID: 0x04, Bytes: 1, Scale: 100.0 / 255},
+		func(t *datastore.TelemetryData, v float64) { t.EngineLoad = v }},
+	{obd.PIDDef{Name: "throttle_pos", Mode: 0x01, PID: 0x11, Bytes: 1, Scale: 100.0 / 255},
+		func(t *datastore.TelemetryData, v float64) { t.ThrottlePos = v }},
+	{obd.PIDDef{Name: "intake_temp", Mode: 0x01, PID: 0x0F, Bytes: 1, Scale: 1, Offset: -40},
+		func(t *datastore.TelemetryData, v float64) { t.IntakeTemp = v }},
+	{obd.PIDDef{Name: "maf", Mode: 0x01, PID: 0x10, Bytes: 2, Scale: 0.01},
+		func(t *datastore.TelemetryData, v float64) { t.MAF = v }},
+	{obd.PIDDef{Name: "fuel_level", Mode: 0x01, PID: 0x2F, Bytes: 1, Scale: 100.0 / 255},
+		func(t *datastore.TelemetryData, v float64) { t.FuelLevel = v }},
+	{obd.PIDDef{Name: "voltage", Mode: 0x01, PID: 0x42, Bytes: 2, Scale: 0.001},
+		func(t *datastore.TelemetryData, v float64) { t.Voltage = v }},
+}
+
+// extraFields maps catalog channel names onto TelemetryData fields;
+// configured channels without a field land in the Derived map so
+// dashboards still see them by name.
+var extraFields = map[string]func(*datastore.TelemetryData, float64){
+	"rail_pressure":     func(t *datastore.TelemetryData, v float64) { t.RailBar = v },
+	"egr_position":      func(t *datastore.TelemetryData, v float64) { t.EGRActual = v },
+	"commanded_egr":     func(t *datastore.TelemetryData, v float64) { t.EGRCommanded = v },
+	"manifold_pressure": func(t *datastore.TelemetryData, v float64) { t.ManifoldKPa = v },
+	"dpf_diff_pressure": func(t *datastore.TelemetryData, v float64) { t.DPFDiffKPa = v },
+	"short_fuel_trim_1": func(t *datastore.TelemetryData, v float64) { t.ShortFuelTrim1 = v },
+	"long_fuel_trim_1":  func(t *datastore.TelemetryData, v float64) { t.LongFuelTrim1 = v },
+	"short_fuel_trim_2": func(t *datastore.TelemetryData, v float64) { t.ShortFuelTrim2 = v },
+	"long_fuel_trim_2":  func(t *datastore.TelemetryData, v float64) { t.LongFuelTrim2 = v },
+	"equiv_ratio":       func(t *datastore.TelemetryData, v float64) { t.EquivRatio = v },
+}
+
+// widebandChannel is the configured channel name that reads the
+// wideband O2 sensor. It can't be a catalog entry — PID 0x34 packs λ
+// and pump current into one response — so the cycle special-cases it.
+const widebandChannel = "wideband_o2"
+
+// Poller reads the core channel set plus the configured extras once per
+// interval and emits one sample per cycle.
+type Poller struct {
+	// OnSample receives each assembled sample; set it before Run.
+	OnSample func(datastore.TelemetryData)
+	// Power, when set, classifies each sample's ignition state; while it
+	// reports the engine off the loop drops to its wake-check cadence so
+	// an adapter left in the port doesn't flatten the battery.
+	Power *power.Monitor
+	// StatusEvery is how often the slow status cycle runs: trouble code
+	// reads that change over minutes, not poll intervals, and don't
+	// deserve a bus request every cycle. Zero uses a minute.
+	StatusEvery time.Duration
+	// ReadByECU, when set, attributes stored codes to the ECUs that
+	// reported them during the status cycle. It is wired from main
+	// rather than read through the client because the broadcast needs
+	// the bus handle and a scheduler slot of its own.
+	ReadByECU func() ([]obd.ECUDTCs, error)
+
+	client  *obd.Client
+	catalog *obd.PIDCatalog
+
+	// mu guards the fields a config push can swap mid-run.
+	mu       sync.Mutex
+	extra    []string
+	interval time.Duration
+	// dead lists channels the vehicle answered "not supported" for, so
+	// they stop costing a request every cycle.
+	dead map[string]bool
+	// quiet suppresses repeated whole-cycle failure logs until a cycle
+	// succeeds again — a parked van would otherwise fill the journal.
+	quiet bool
+
+	// Slow-cycle results, stamped onto every sample until the next
+	// refresh. Touched only from the Run goroutine.
+	lastStatus    time.Time
+	dtcs          []string
+	pendingDTCs   []string
+	permanentDTCs []string
+	dtcsByECU     map[string][]string
+	readiness     *datastore.Readiness
+}
+
+// New builds a poller over the given client. A zero interval uses one
+// second.
+func New(client *obd.Client, catalog *obd.PIDCatalog, extra []string, interval time.Duration) *Poller {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Poller{
+		client:   client,
+		catalog:  catalog,
+		extra:    extra,
+		interval: interval,
+		dead:     make(map[string]bool),
+	}
+}
+
+// Update swaps the extra channel set and poll interval in place — the
+// hot-reload path for a pushed configuration. The dead list is reset so
+// previously refused channels get a fresh chance; the push may follow a
+// firmware change that added them.
+func (p *Poller) Update(pids []string, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.extra = append([]string(nil), pids...)
+	p.interval = interval
+	p.dead = make(map[string]bool)
+}
+
+// Run polls until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	for {
+		if t, ok := p.cycle(ctx); ok {
+			// The bus answered, so the slow status reads stand a chance;
+			// refresh them before the sample is stamped and handed on.
+			p.maybeStatus(ctx)
+			p.stampStatus(&t)
+			if p.Power != nil {
+				p.Power.Observe(t)
+			}
+			if p.OnSample != nil {
+				p.OnSample(t)
+			}
+		}
+		p.mu.Lock()
+		wait := p.interval
+		p.mu.Unlock()
+		if p.Power != nil && p.Power.State() == power.StateSleeping {
+			wait = p.Power.WakeCheckInterval()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// maybeStatus runs the slow status cycle when it is due. The timestamp
+// advances even on failure so a refusing ECU is retried at the status
+// cadence, not every poll interval.
+func (p *Poller) maybeStatus(ctx context.Context) {
+	every := p.StatusEvery
+	if every <= 0 {
+		every = time.Minute
+	}
+	if time.Since(p.lastStatus) < every {
+		return
+	}
+	p.lastStatus = time.Now()
+	p.statusCycle(ctx)
+}
+
+// statusCycle reads the stored, pending and permanent trouble codes
+// (modes 03, 07, 0A) and the I/M readiness snapshot. The stored read
+// must answer; the rest are requests some ECUs simply refuse, which
+// isn't worth a log line.
+func (p *Poller) statusCycle(ctx context.Context) {
+	c := p.client.WithContext(ctx)
+	codes, err := c.ReadDTCs()
+	if err != nil {
+		log.Printf("poll: read DTCs: %v", err)
+		return
+	}
+	p.dtcs = codes
+	if r, err := c.ReadReadiness(); err == nil {
+		p.readiness = r
+	} else if !uds.IsNotSupported(err) {
+		log.Printf("poll: read readiness: %v", err)
+	}
+	if pending, err := c.ReadPendingDTCs(); err == nil {
+		p.pendingDTCs = pending
+	} else if !uds.IsNotSupported(err) {
+		log.Printf("poll: read pending DTCs: %v", err)
+	}
+	if permanent, err := c.ReadPermanentDTCs(); err == nil {
+		p.permanentDTCs = permanent
+	} else if !uds.IsNotSupported(err) {
+		log.Printf("poll: read permanent DTCs: %v", err)
+	}
+	if p.ReadByECU != nil {
+		byECU, err := p.ReadByECU()
+		if err != nil {
+			log.Printf("poll: read DTCs by ECU: %v", err)
+			return
+		}
+		m := make(map[string][]string, len(byECU))
+		for _, e := range byECU {
+			if len(e.Codes) > 0 {
+				m[e.Name] = e.Codes
+			}
+		}
+		p.dtcsByECU = m
+	}
+}
+
+// stampStatus copies the latest slow-cycle results onto one sample.
+func (p *Poller) stampStatus(t *datastore.TelemetryData) {
+	t.DTCs = p.dtcs
+	t.PendingDTCs = p.pendingDTCs
+	t.PermanentDTCs = p.permanentDTCs
+	if len(p.dtcsByECU) > 0 {
+		t.DTCsByECU = p.dtcsByECU
+	}
+	t.Readiness = p.readiness
+}
+
+// cycle runs one request round over every live channel. It reports
+// false when nothing answered — the bus is down or the engine is off —
+// so no empty sample enters the pipeline.
+func (p *Poller) cycle(ctx context.Context) (datastore.TelemetryData, bool) {
+	t := datastore.TelemetryData{Timestamp: clock.Now().UTC()}
+	c := p.client.WithContext(ctx)
+	p.mu.Lock()
+	extra := append([]string(nil), p.extra...)
+	p.mu.Unlock()
+	got := false
+	var lastErr error
+	for _, ch := range coreChannels {
+		if p.isDead(ch.def.Name) {
+			continue
+		}
+		v, err := c.ReadPID(ch.def)
+		if err != nil {
+			lastErr = p.noteErr(ch.def.Name, err)
+			continue
+		}
+		got = true
+		ch.apply(&t, v)
+	}
+	for _, name := range extra {
+		if p.isDead(name) {
+			continue
+		}
+		if name == widebandChannel {
+			o2, err := c.ReadWidebandO2()
+			if err != nil {
+				lastErr = p.noteErr(name, err)
+				continue
+			}
+			got = true
+			t.O2Lambda = o2.Lambda
+			t.O2Current = o2.CurrentMA
+			continue
+		}
+		def, ok := p.catalog.Lookup(name)
+		if !ok {
+			log.Printf("poll: no PID named %q in the catalog, dropping it", name)
+			p.markDead(name)
+			continue
+		}
+		v, err := c.ReadPID(def)
+		if err != nil {
+			lastErr = p.noteErr(name, err)
+			continue
+		}
+		got = true
+		if apply, ok := extraFields[name]; ok {
+			apply(&t, v)
+		} else {
+			if t.Derived == nil {
+				t.Derived = make(map[string]float64)
+			}
+			t.Derived[name] = v
+		}
+	}
+	if got {
+		p.quiet = false
+	} else if lastErr != nil && !p.quiet {
+		log.Printf("poll: no channel answered: %v (suppressing until the bus answers)", lastErr)
+		p.quiet = true
+	}
+	return t, got
+}
+
+// noteErr classifies one channel's failure: a "not supported" refusal
+// permanently drops the channel, anything else is transient.
+func (p *Poller) noteErr(name string, err error) error {
+	if uds.IsNotSupported(err) {
+		log.Printf("poll: %s not supported by this vehicle, dropping it", name)
+		p.markDead(name)
+		return nil
+	}
+	return err
+}
+
+func (p *Poller) isDead(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dead[name]
+}
+
+func (p *Poller) markDead(name string) {
+	p.mu.Lock()
+	p.dead[name] = true
+	p.mu.Unlock()
+}
@@ -0,0 +1,104 @@
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/obd"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+func TestCycleReadsMockVehicle(t *testing.T) {
+	m := transport.NewMock()
+	defer m.Close()
+	m.SetLive(1500, 60, 88, 40)
+
+	p := New(obd.NewClient(m), obd.DefaultCatalog(), nil, time.Second)
+	sample, ok := p.cycle(context.Background())
+	if !ok {
+		t.Fatal("cycle read nothing from the mock vehicle")
+	}
+	if sample.RPM != 1500 {
+		t.Errorf("RPM = %v, want 1500", sample.RPM)
+	}
+	if sample.Speed != 60 {
+		t.Errorf("Speed = %v, want 60", sample.Speed)
+	}
+	if sample.CoolantTemp != 88 {
+		t.Errorf("CoolantTemp = %v, want 88", sample.CoolantTemp)
+	}
+	if sample.Timestamp.IsZero() {
+		t.Error("sample has no timestamp")
+	}
+}
+
+func TestCycleDropsUnsupportedChannels(t *testing.T) {
+	m := transport.NewMock()
+	defer m.Close()
+
+	p := New(obd.NewClient(m), obd.DefaultCatalog(), []string{"rail_pressure", "no_such_channel"}, time.Second)
+	if _, ok := p.cycle(context.Background()); !ok {
+		t.Fatal("cycle read nothing from the mock vehicle")
+	}
+	// The mock doesn't implement the MAF PID or any mode 22 DID; both
+	// should be dropped rather than re-asked every cycle, as should a
+	// configured name the catalog doesn't know.
+	for _, name := range []string{"maf", "rail_pressure", "no_such_channel"} {
+		if !p.dead[name] {
+			t.Errorf("%s not dropped after an unsupported response", name)
+		}
+	}
+}
+
+func TestUpdateSwapsChannelsAndRevivesDead(t *testing.T) {
+	m := transport.NewMock()
+	defer m.Close()
+
+	p := New(obd.NewClient(m), obd.DefaultCatalog(), []string{"rail_pressure"}, time.Second)
+	if _, ok := p.cycle(context.Background()); !ok {
+		t.Fatal("cycle read nothing from the mock vehicle")
+	}
+	if !p.dead["rail_pressure"] {
+		t.Fatal("rail_pressure not dropped after an unsupported response")
+	}
+	p.Update([]string{"boost_pressure"}, 250*time.Millisecond)
+	if len(p.dead) != 0 {
+		t.Errorf("dead list not reset by Update: %v", p.dead)
+	}
+	if p.interval != 250*time.Millisecond {
+		t.Errorf("interval = %v after Update, want 250ms", p.interval)
+	}
+	if len(p.extra) != 1 || p.extra[0] != "boost_pressure" {
+		t.Errorf("extra = %v after Update, want [boost_pressure]", p.extra)
+	}
+}
+
+func TestStatusCycleStampsTroubleCodes(t *testing.T) {
+	m := transport.NewMock()
+	defer m.Close()
+	m.AddDTC(0x01, 0x23)
+
+	p := New(obd.NewClient(m), obd.DefaultCatalog(), nil, time.Second)
+	p.ReadByECU = func() ([]obd.ECUDTCs, error) { return obd.ReadDTCsByECU(m) }
+	p.statusCycle(context.Background())
+	var tt datastore.TelemetryData
+	p.stampStatus(&tt)
+	if len(tt.DTCs) != 1 || tt.DTCs[0] != "P0123" {
+		t.Errorf("DTCs = %v, want [P0123]", tt.DTCs)
+	}
+	// The mock holds no pending or permanent codes.
+	if len(tt.PendingDTCs) != 0 || len(tt.PermanentDTCs) != 0 {
+		t.Errorf("pending %v permanent %v, want both empty", tt.PendingDTCs, tt.PermanentDTCs)
+	}
+	// The mock answers functional broadcasts from the engine slot, so
+	// the same code comes back attributed.
+	if got := tt.DTCsByECU["engine"]; len(got) != 1 || got[0] != "P0123" {
+		t.Errorf("DTCsByECU = %v, want engine:[P0123]", tt.DTCsByECU)
+	}
+	// One stored code lights the MIL in the readiness snapshot.
+	if tt.Readiness == nil || !tt.Readiness.MILOn || tt.Readiness.DTCCount != 1 {
+		t.Errorf("Readiness = %+v, want MIL on with one code", tt.Readiness)
+	}
+}
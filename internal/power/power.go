@@ -0,0 +1,145 @@
+// Package power detects ignition state from live telemetry so the
+// polling loop can idle when the van is parked. An adapter left in the
+// OBD port draws from the battery whether the engine runs or not; a
+// weekend at the depot should not mean a flat start on Monday. The
+// Monitor classifies each sample as engine-running or not and, after a
+// quiet period, fires OnSleep so consumers pause polling, flush any
+// open capture (Recorder.Flush, Pipeline.Flush) and drop to the slow
+// wake-check cadence; the first running sample fires OnWake.
+package power
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+const (
+	// runningRPM is the lowest RPM treated as a running engine. The
+	// D4CB idles around 750, so anything past cranking clears it.
+	runningRPM = 300.0
+	// chargingVoltage marks the alternator as active: a resting battery
+	// sits near 12.6 V, charging holds 13.8-14.4 V. At or above this the
+	// engine is running even when the RPM channel is missing.
+	chargingVoltage = 13.2
+	// defaultOffDelay is how long after the last running sample the
+	// engine is declared off — long enough to ride out stop-start at
+	// the lights without closing the session.
+	defaultOffDelay = 90 * time.Second
+	// defaultWakeCheck is how often a sleeping poller should probe for
+	// ignition: one request every 30s costs nothing and wakes the
+	// pipeline within half a minute of key-on.
+	defaultWakeCheck = 30 * time.Second
+)
+
+// State is the monitor's view of the vehicle.
+type State int
+
+const (
+	// StateActive means the engine is (or recently was) running; poll at
+	// full rate.
+	StateActive State = iota
+	// StateSleeping means the ignition is off; poll at the wake-check
+	// cadence only.
+	StateSleeping
+)
+
+func (s State) String() string {
+	if s == StateSleeping {
+		return "sleeping"
+	}
+	return "active"
+}
+
+// Monitor tracks engine state from telemetry samples. Off-delay timing
+// uses sample timestamps; a wall-clock timer additionally catches the
+// adapter going silent entirely (ELM clones power down when the bus
+// sleeps, and the samples just stop).
+type Monitor struct {
+	// OnSleep runs once when the engine is declared off; OnWake once
+	// when it comes back. Both are called without the monitor lock held.
+	OnSleep func()
+	OnWake  func()
+
+	offDelay  time.Duration
+	wakeCheck time.Duration
+
+	mu          sync.Mutex
+	state       State
+	lastRunning time.Time
+	silence     *time.Timer
+}
+
+// NewMonitor builds a monitor. Zero durations use the 90s off delay and
+// 30s wake check.
+func NewMonitor(offDelay, wakeCheck time.Duration) *Monitor {
+	if offDelay <= 0 {
+		offDelay = defaultOffDelay
+	}
+	if wakeCheck <= 0 {
+		wakeCheck = defaultWakeCheck
+	}
+	return &Monitor{offDelay: offDelay, wakeCheck: wakeCheck}
+}
+
+// State reports the current classification.
+func (m *Monitor) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// WakeCheckInterval is how often a sleeping consumer should probe for
+// ignition before resuming full-rate polling.
+func (m *Monitor) WakeCheckInterval() time.Duration { return m.wakeCheck }
+
+// Observe classifies one sample and fires any state transition.
+func (m *Monitor) Observe(t datastore.TelemetryData) {
+	running := t.RPM >= runningRPM || t.Voltage >= chargingVoltage
+	m.mu.Lock()
+	// Any sample at all proves the adapter is awake; rearm the silence
+	// fallback.
+	if m.silence == nil {
+		m.silence = time.AfterFunc(m.offDelay, m.silenceExpired)
+	} else {
+		m.silence.Reset(m.offDelay)
+	}
+	var fire func()
+	if running {
+		m.lastRunning = t.Timestamp
+		if m.state == StateSleeping {
+			m.state = StateActive
+			fire = m.OnWake
+		}
+	} else {
+		if m.lastRunning.IsZero() {
+			// First sample with the engine off: start the clock here
+			// rather than sleeping immediately.
+			m.lastRunning = t.Timestamp
+		}
+		if m.state == StateActive && t.Timestamp.Sub(m.lastRunning) >= m.offDelay {
+			m.state = StateSleeping
+			fire = m.OnSleep
+		}
+	}
+	m.mu.Unlock()
+	if fire != nil {
+		fire()
+	}
+}
+
+// silenceExpired fires when no sample has arrived for a whole off
+// delay — the adapter or bus has gone to sleep under us.
+func (m *Monitor) silenceExpired() {
+	m.mu.Lock()
+	var fire func()
+	if m.state == StateActive {
+		m.state = StateSleeping
+		fire = m.OnSleep
+	}
+	m.mu.Unlock()
+	if fire != nil {
+		fire()
+	}
+}
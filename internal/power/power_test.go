@@ -0,0 +1,94 @@
+package power
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func sample(ts time.Time, rpm, voltage float64) datastore.TelemetryData {
+	return datastore.TelemetryData{VIN: "TESTVIN", Timestamp: ts, RPM: rpm, Voltage: voltage}
+}
+
+func TestSleepsAfterOffDelay(t *testing.T) {
+	m := NewMonitor(time.Minute, 0)
+	slept := 0
+	m.OnSleep = func() { slept++ }
+	t0 := time.Now()
+	m.Observe(sample(t0, 800, 14.2))
+	m.Observe(sample(t0.Add(30*time.Second), 0, 12.5))
+	if m.State() != StateActive {
+		t.Fatal("slept before the off delay elapsed")
+	}
+	m.Observe(sample(t0.Add(61*time.Second), 0, 12.5))
+	if m.State() != StateSleeping || slept != 1 {
+		t.Errorf("state = %v, slept %d times; want sleeping once", m.State(), slept)
+	}
+	// Further off samples must not re-fire the callback.
+	m.Observe(sample(t0.Add(2*time.Minute), 0, 12.4))
+	if slept != 1 {
+		t.Errorf("OnSleep fired %d times, want 1", slept)
+	}
+}
+
+func TestStopStartStaysActive(t *testing.T) {
+	m := NewMonitor(time.Minute, 0)
+	m.OnSleep = func() { t.Error("slept through a stop-start pause") }
+	t0 := time.Now()
+	for i := 0; i < 10; i++ {
+		ts := t0.Add(time.Duration(i) * 20 * time.Second)
+		rpm := 0.0
+		if i%3 == 0 { // engine restarts before the delay elapses
+			rpm = 800
+		}
+		m.Observe(sample(ts, rpm, 12.6))
+	}
+	if m.State() != StateActive {
+		t.Errorf("state = %v, want active", m.State())
+	}
+}
+
+func TestWakesOnFirstRunningSample(t *testing.T) {
+	m := NewMonitor(time.Minute, 0)
+	woke := 0
+	m.OnWake = func() { woke++ }
+	t0 := time.Now()
+	m.Observe(sample(t0, 0, 12.5))
+	m.Observe(sample(t0.Add(2*time.Minute), 0, 12.5))
+	if m.State() != StateSleeping {
+		t.Fatal("monitor never slept")
+	}
+	m.Observe(sample(t0.Add(3*time.Minute), 750, 14.1))
+	if m.State() != StateActive || woke != 1 {
+		t.Errorf("state = %v, woke %d times; want active once", m.State(), woke)
+	}
+}
+
+func TestChargingVoltageCountsAsRunning(t *testing.T) {
+	m := NewMonitor(time.Minute, 0)
+	m.OnSleep = func() { t.Error("slept while the alternator was charging") }
+	t0 := time.Now()
+	for i := 0; i < 5; i++ {
+		// RPM channel missing (0) but the alternator says otherwise.
+		m.Observe(sample(t0.Add(time.Duration(i)*time.Minute), 0, 14.3))
+	}
+	if m.State() != StateActive {
+		t.Errorf("state = %v, want active", m.State())
+	}
+}
+
+func TestSilenceTriggersSleep(t *testing.T) {
+	m := NewMonitor(50*time.Millisecond, 0)
+	slept := make(chan struct{}, 1)
+	m.OnSleep = func() { slept <- struct{}{} }
+	m.Observe(sample(time.Now(), 800, 14.2))
+	select {
+	case <-slept:
+	case <-time.After(time.Second):
+		t.Fatal("no sleep after the adapter went silent")
+	}
+	if m.State() != StateSleeping {
+		t.Errorf("state = %v, want sleeping", m.State())
+	}
+}
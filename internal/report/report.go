@@ -0,0 +1,109 @@
+// Package report renders per-vehicle analysis reports and delivers them
+// on a schedule.
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+var reportTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{.Vehicle.VIN}} — iload-obd2 report</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em; }
+    table { border-collapse: collapse; }
+    th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+    th { background: #f0f0f0; }
+    td:first-child, th:first-child { text-align: left; }
+  </style>
+</head>
+<body>
+  <h1>{{.Vehicle.Make}} {{.Vehicle.Model}} — {{.Vehicle.VIN}}</h1>
+  <p>Generated {{.GeneratedAt.Format "2006-01-02 15:04 -0700"}}</p>
+
+  {{if .Alerts}}
+  <h2>Open alerts</h2>
+  <ul>
+  {{range .Alerts}}<li>{{.Timestamp.Format "2006-01-02 15:04"}} — {{.Message}}</li>{{end}}
+  </ul>
+  {{end}}
+
+  {{if .Braking}}
+  <h2>Driving style (last 7 days)</h2>
+  <table>
+    <tr><th>Brake events</th><th>Energy braked away (kJ)</th><th>Coasting share of decelerations</th></tr>
+    <tr>
+      <td>{{.Braking.BrakeEvents}}</td>
+      <td>{{printf "%.0f" .Braking.WastedKJ}}</td>
+      <td>{{printf "%.0f%%" .Braking.CoastPct}}</td>
+    </tr>
+  </table>
+  {{end}}
+
+  <h2>Weekly summary</h2>
+  {{if .Rollups}}
+  <table>
+    <tr><th>Week</th><th>Distance (km)</th><th>Fuel (L)</th><th>L/100km</th><th>Idle %</th><th>Harsh</th><th>DTCs</th></tr>
+    {{range .Rollups}}
+    <tr>
+      <td>{{.PeriodStart.Format "2006-01-02"}}</td>
+      <td>{{printf "%.0f" .DistanceKm}}</td>
+      <td>{{printf "%.1f" .FuelL}}</td>
+      <td>{{printf "%.1f" .AvgEconomy}}</td>
+      <td>{{printf "%.0f" .IdlePct}}</td>
+      <td>{{.HarshEvents}}</td>
+      <td>{{.DTCCount}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{else}}
+  <p>No driving recorded yet.</p>
+  {{end}}
+</body>
+</html>
+`))
+
+type reportData struct {
+	Vehicle     datastore.Vehicle
+	Alerts      []datastore.Alert
+	Rollups     []datastore.Rollup
+	Braking     *analysis.BrakeReport
+	GeneratedAt time.Time
+}
+
+// Generate renders the HTML analysis report for one vehicle.
+func Generate(store datastore.Store, vin string) ([]byte, error) {
+	v, err := store.Vehicle(vin)
+	if err != nil {
+		return nil, fmt.Errorf("report: vehicle %s: %w", vin, err)
+	}
+	alerts, err := store.Alerts(vin, true)
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := store.Rollups(vin, "weekly", 8)
+	if err != nil {
+		return nil, err
+	}
+	data := reportData{
+		Vehicle:     v,
+		Alerts:      alerts,
+		Rollups:     rollups,
+		GeneratedAt: time.Now(),
+	}
+	now := time.Now().UTC()
+	if samples, err := store.Telemetry(vin, now.AddDate(0, 0, -7), now); err == nil && len(samples) > 1 {
+		data.Braking = analysis.AnalyzeBraking(samples)
+	}
+	var buf bytes.Buffer
+	err = reportTmpl.Execute(&buf, data)
+	return buf.Bytes(), err
+}
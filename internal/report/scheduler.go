@@ -0,0 +1,191 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/config"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/i18n"
+)
+
+// Scheduler generates each vehicle's report at the configured local time
+// of day and delivers it to a directory and/or by email.
+type Scheduler struct {
+	store datastore.Store
+	cfg   config.Reports
+}
+
+// NewScheduler builds a Scheduler from the reports config section.
+func NewScheduler(store datastore.Store, cfg config.Reports) *Scheduler {
+	return &Scheduler{store: store, cfg: cfg}
+}
+
+// Run fires at the configured time each day until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		next := s.nextRun(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+		if err := s.RunOnce(); err != nil {
+			log.Printf("report: %v", err)
+		}
+	}
+}
+
+// nextRun returns the next occurrence of the configured HH:MM local time.
+func (s *Scheduler) nextRun(now time.Time) time.Time {
+	hh, mm := 6, 0
+	if t, err := time.Parse("15:04", s.cfg.Time); err == nil {
+		hh, mm = t.Hour(), t.Minute()
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), hh, mm, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunOnce generates and delivers a report for every vehicle.
+func (s *Scheduler) RunOnce() error {
+	vehicles, err := s.store.Vehicles()
+	if err != nil {
+		return err
+	}
+	for _, v := range vehicles {
+		html, err := Generate(s.store, v.VIN)
+		if err != nil {
+			log.Printf("report: generate %s: %v", v.VIN, err)
+			continue
+		}
+		if err := s.deliver(v.VIN, html); err != nil {
+			log.Printf("report: deliver %s: %v", v.VIN, err)
+		}
+		s.checkOilLife(v)
+		s.checkCranking(v)
+	}
+	return nil
+}
+
+// checkCranking warns when the last month of start events shows a
+// weakening battery or starter, before it strands someone.
+func (s *Scheduler) checkCranking(v datastore.Vehicle) {
+	open, err := s.store.Alerts(v.VIN, true)
+	if err != nil {
+		return
+	}
+	for _, a := range open {
+		if a.Type == "battery_health" {
+			return
+		}
+	}
+	now := time.Now().UTC()
+	samples, err := s.store.Telemetry(v.VIN, now.AddDate(0, -1, 0), now)
+	if err != nil || len(samples) < 2 {
+		return
+	}
+	rep := analysis.AnalyzeCranking(samples)
+	if len(rep.Events) < 5 {
+		return
+	}
+	weak := rep.WeakStarts >= 2
+	declining := rep.MinVoltageTrend < -0.02 // ~0.6V lost across 30 starts
+	if !weak && !declining {
+		return
+	}
+	last := rep.Events[len(rep.Events)-1]
+	a := &datastore.Alert{
+		VIN:       v.VIN,
+		Type:      "battery_health",
+		Message:   i18n.T("alert.cranking", rep.WeakStarts, last.MinVoltage),
+		Value:     last.MinVoltage,
+		Threshold: 9.6,
+		Timestamp: now,
+	}
+	if err := s.store.SaveAlert(a); err != nil {
+		log.Printf("report: save battery alert: %v", err)
+	}
+}
+
+// checkOilLife raises a service reminder once a vehicle's estimated oil
+// life runs out; the open alert doubles as the dedup flag until it's
+// acknowledged after the service.
+func (s *Scheduler) checkOilLife(v datastore.Vehicle) {
+	open, err := s.store.Alerts(v.VIN, true)
+	if err != nil {
+		log.Printf("report: oil check %s: %v", v.VIN, err)
+		return
+	}
+	for _, a := range open {
+		if a.Type == "oil_life" {
+			return
+		}
+	}
+	samples, err := s.store.Telemetry(v.VIN, v.Registered, time.Now().UTC())
+	if err != nil || len(samples) < 2 {
+		return
+	}
+	rep := analysis.AnalyzeOilLife(samples)
+	if rep.LifePct > 10 {
+		return
+	}
+	a := &datastore.Alert{
+		VIN:       v.VIN,
+		Type:      "oil_life",
+		Message:   i18n.T("alert.oil_life", rep.LifePct),
+		Value:     rep.LifePct,
+		Threshold: 10,
+		Timestamp: time.Now().UTC(),
+	}
+	if err := s.store.SaveAlert(a); err != nil {
+		log.Printf("report: save oil alert: %v", err)
+	}
+}
+
+func (s *Scheduler) deliver(vin string, html []byte) error {
+	name := fmt.Sprintf("%s-%s.html", vin, time.Now().Format("2006-01-02"))
+	if s.cfg.OutputDir != "" {
+		if err := os.MkdirAll(s.cfg.OutputDir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(s.cfg.OutputDir, name), html, 0o644); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Email.SMTPAddr != "" && len(s.cfg.Email.To) > 0 {
+		return s.email(vin, name, html)
+	}
+	return nil
+}
+
+func (s *Scheduler) email(vin, name string, html []byte) error {
+	e := s.cfg.Email
+	var auth smtp.Auth
+	if e.Username != "" {
+		host := e.SMTPAddr
+		if i := strings.IndexByte(host, ':'); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+	msg := strings.Join([]string{
+		"From: " + e.From,
+		"To: " + strings.Join(e.To, ", "),
+		"Subject: iload-obd2 report for " + vin,
+		"MIME-Version: 1.0",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		string(html),
+	}, "\r\n")
+	return smtp.SendMail(e.SMTPAddr, auth, e.From, e.To, []byte(msg))
+}
@@ -0,0 +1,93 @@
+package rollup
+
+import (
+	"sort"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Ranking is one vehicle's position in a fleet comparison, for coaching.
+type Ranking struct {
+	Rank        int     `json:"rank"`
+	VIN         string  `json:"vin"`
+	Score       float64 `json:"score"`         // 0-100, higher is better
+	Economy     float64 `json:"economy"`       // L/100km over the window
+	IdlePct     float64 `json:"idle_pct"`      // % of engine-on time stationary
+	HarshPer100 float64 `json:"harsh_per_100"` // harsh events per 100 km
+	DistanceKm  float64 `json:"distance_km"`
+	// MassRatio is the distance-weighted load estimate for the window;
+	// the economy reference scales with it so a van towing all week
+	// isn't ranked against an empty one.
+	MassRatio float64 `json:"mass_ratio"`
+}
+
+// Rank aggregates the latest buckets per vehicle and orders the fleet by
+// a combined efficiency score. Vehicles with under minDistanceKm of
+// driving in the window are excluded — there isn't enough data to judge
+// them fairly.
+const minDistanceKm = 10
+
+func Rank(store datastore.Store, vins []string, period string, buckets int) ([]Ranking, error) {
+	if buckets <= 0 {
+		buckets = 4
+	}
+	var rankings []Ranking
+	for _, vin := range vins {
+		rollups, err := store.Rollups(vin, period, buckets)
+		if err != nil {
+			return nil, err
+		}
+		var agg datastore.Rollup
+		var massSum float64
+		for _, r := range rollups {
+			agg.DistanceKm += r.DistanceKm
+			agg.FuelL += r.FuelL
+			agg.HarshEvents += r.HarshEvents
+			agg.IdleSec += r.IdleSec
+			agg.DriveSec += r.DriveSec
+			massSum += r.MassRatio * r.DistanceKm
+		}
+		if agg.DistanceKm < minDistanceKm {
+			continue
+		}
+		rk := Ranking{
+			VIN:         vin,
+			Economy:     agg.FuelL * 100 / agg.DistanceKm,
+			IdlePct:     agg.IdlePct(),
+			HarshPer100: float64(agg.HarshEvents) * 100 / agg.DistanceKm,
+			DistanceKm:  agg.DistanceKm,
+			MassRatio:   massSum / agg.DistanceKm,
+		}
+		rk.Score = score(rk)
+		rankings = append(rankings, rk)
+	}
+	sort.Slice(rankings, func(i, j int) bool { return rankings[i].Score > rankings[j].Score })
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+	return rankings, nil
+}
+
+// score combines the three metrics into 0-100. The reference points are
+// loose expectations for a loaded iLoad: 9 L/100km, 20% idle, 2 harsh
+// events per 100 km each cost a third of the scale when doubled.
+func score(r Ranking) float64 {
+	s := 100.0
+	// A heavily loaded or towing van is allowed proportionally more
+	// fuel before losing points, up to 1.8x the unladen reference.
+	ecoRef := 9.0 * clamp(r.MassRatio, 1, 1.8)
+	s -= clamp((r.Economy-ecoRef)/ecoRef*33, 0, 33)
+	s -= clamp((r.IdlePct-20)/20*33, 0, 33)
+	s -= clamp((r.HarshPer100-2)/2*34, 0, 34)
+	return clamp(s, 0, 100)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+// Package rollup aggregates raw telemetry into weekly and monthly
+// summaries per VIN, cached in SQLite so trend queries stay cheap.
+package rollup
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// harshAccel is the longitudinal acceleration magnitude, in m/s², above
+// which a sample-to-sample speed change counts as a harsh event.
+const harshAccel = 3.0
+
+// dieselAFR approximates the stoichiometric air/fuel ratio used to
+// estimate fuel mass flow from MAF; good enough for trends.
+const dieselAFR = 14.5
+
+// dieselDensity is fuel density in g/L.
+const dieselDensity = 832.0
+
+// Job recomputes rollups for every vehicle on an interval.
+type Job struct {
+	store    datastore.Store
+	interval time.Duration
+}
+
+// NewJob builds the aggregation job; interval defaults to an hour.
+func NewJob(store datastore.Store, interval time.Duration) *Job {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Job{store: store, interval: interval}
+}
+
+// Run recomputes rollups until ctx is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(); err != nil {
+			log.Printf("rollup: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce recomputes the current and previous weekly and monthly buckets
+// for every vehicle. Older buckets never change, so they are left alone.
+func (j *Job) RunOnce() error {
+	vehicles, err := j.store.Vehicles()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, v := range vehicles {
+		for _, period := range []string{"weekly", "monthly"} {
+			for _, start := range bucketStarts(now, period) {
+				if err := j.compute(v.VIN, period, start); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// bucketStarts returns the start of the current and previous bucket.
+func bucketStarts(now time.Time, period string) []time.Time {
+	switch period {
+	case "monthly":
+		cur := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return []time.Time{cur, cur.AddDate(0, -1, 0)}
+	default: // weekly, ISO weeks starting Monday
+		day := int(now.Weekday())
+		if day == 0 {
+			day = 7
+		}
+		cur := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).
+			AddDate(0, 0, -(day - 1))
+		return []time.Time{cur, cur.AddDate(0, 0, -7)}
+	}
+}
+
+func bucketEnd(start time.Time, period string) time.Time {
+	if period == "monthly" {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+func (j *Job) compute(vin, period string, start time.Time) error {
+	samples, err := j.store.Telemetry(vin, start, bucketEnd(start, period))
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	r := Summarize(samples)
+	r.VIN = vin
+	r.Period = period
+	r.PeriodStart = start
+	return j.store.SaveRollup(r)
+}
+
+// Summarize computes the rollup metrics for a slice of samples ordered
+// by time.
+func Summarize(samples []datastore.TelemetryData) datastore.Rollup {
+	var r datastore.Rollup
+	seenDTCs := map[string]bool{}
+	for i, t := range samples {
+		for _, code := range t.DTCs {
+			if !seenDTCs[code] {
+				seenDTCs[code] = true
+				r.DTCCount++
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		prev := samples[i-1]
+		dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 || dt > 60 {
+			// Gap between trips; don't integrate across it.
+			continue
+		}
+		avgSpeed := (t.Speed + prev.Speed) / 2 // km/h
+		r.DistanceKm += avgSpeed * dt / 3600
+		if t.RPM > 300 {
+			r.DriveSec += dt
+			if t.Speed < 2 {
+				r.IdleSec += dt
+			}
+		}
+		if t.FuelRate > 0 && prev.FuelRate > 0 {
+			// Measured fuel rate (PID 0x5E), L/h.
+			r.FuelL += (t.FuelRate + prev.FuelRate) / 2 * dt / 3600
+		} else {
+			// Fuel mass flow from MAF assuming stoichiometric burn.
+			avgMAF := (t.MAF + prev.MAF) / 2 // g/s
+			r.FuelL += avgMAF / dieselAFR * dt / dieselDensity
+		}
+		accel := (t.Speed - prev.Speed) / 3.6 / dt // m/s²
+		if math.Abs(accel) > harshAccel {
+			r.HarshEvents++
+		}
+	}
+	if r.DistanceKm > 1 {
+		// Normalised to sea-level standard air so mountain weeks rank
+		// fairly against coastal ones.
+		r.AvgEconomy = analysis.CompensateEconomy(r.FuelL*100/r.DistanceKm, samples)
+	}
+	r.IdleQuality = analysis.AnalyzeIdle(samples).Score
+	load := analysis.AnalyzeLoad(samples)
+	r.MassRatio = load.MassRatio
+	r.HighLoadSec = load.HighLoadSec
+	return r
+}
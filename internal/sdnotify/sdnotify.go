@@ -0,0 +1,88 @@
+// Package sdnotify implements the systemd notification protocol
+// (sd_notify) without linking libsystemd: readiness signaling for
+// Type=notify units and watchdog keepalives so systemd restarts the
+// daemon when the pipeline wedges. Everything is a no-op outside a
+// systemd unit (no NOTIFY_SOCKET in the environment), so the daemon
+// behaves identically on a dev machine.
+package sdnotify
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// notify writes one state datagram to the socket systemd passed us.
+func notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Ready tells systemd the daemon has finished starting up.
+func Ready() {
+	if err := notify("READY=1"); err != nil {
+		log.Printf("sdnotify: %v", err)
+	}
+}
+
+// Stopping tells systemd a shutdown is deliberate.
+func Stopping() {
+	if err := notify("STOPPING=1"); err != nil {
+		log.Printf("sdnotify: %v", err)
+	}
+}
+
+// watchdogInterval returns the keepalive deadline systemd configured
+// via WatchdogSec, or false when no watchdog is armed for this process.
+func watchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pidStr := os.Getenv("WATCHDOG_PID"); pidStr != "" {
+		if pid, err := strconv.Atoi(pidStr); err == nil && pid != os.Getpid() {
+			return 0, false
+		}
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// RunWatchdog sends WATCHDOG=1 at half the configured interval for as
+// long as healthy reports true. Keepalives stop — and systemd restarts
+// the unit — when the pipeline wedges. Returns immediately when no
+// watchdog is armed.
+func RunWatchdog(ctx context.Context, healthy func() bool) {
+	interval, ok := watchdogInterval()
+	if !ok {
+		return
+	}
+	log.Printf("sdnotify: watchdog armed, keepalive every %s", interval/2)
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !healthy() {
+				log.Print("sdnotify: pipeline unhealthy, withholding watchdog keepalive")
+				continue
+			}
+			if err := notify("WATCHDOG=1"); err != nil {
+				log.Printf("sdnotify: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+type contextKey string
+
+const userKey contextKey = "user"
+
+// currentUser returns the authenticated user for a request, or nil when
+// the instance runs in open single-owner mode.
+func currentUser(r *http.Request) *datastore.User {
+	u, _ := r.Context().Value(userKey).(*datastore.User)
+	return u
+}
+
+// withAuth resolves HTTP basic auth against the user table. When no users
+// exist the instance is open and every request passes through unauthenticated.
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := s.store.UserCount()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if n == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Edge agents authenticate with the configured sync bearer token.
+		// It is scoped to the sync ingest endpoints, so a token lifted
+		// from a van's config can't read the dashboard API.
+		if tok, ok := bearerToken(r); ok {
+			if s.syncToken != "" && strings.HasPrefix(r.URL.Path, "/api/v1/sync/") &&
+				subtle.ConstantTimeCompare([]byte(tok), []byte(s.syncToken)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeError(w, http.StatusUnauthorized, "invalid sync token")
+			return
+		}
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="iload-obd2"`)
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		u, err := s.store.UserByName(username)
+		if err != nil || bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+			writeError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey, &u)))
+	})
+}
+
+// bearerToken extracts an Authorization: Bearer credential, reporting
+// whether one was presented.
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(h, prefix), true
+}
+
+// requireRole checks that the requesting user holds at least the given
+// role, writing a 403 response if not. Open single-owner mode implies
+// admin. It returns false when the caller must stop handling the request.
+func (s *Server) requireRole(w http.ResponseWriter, r *http.Request, min datastore.Role) bool {
+	u := currentUser(r)
+	if u == nil {
+		// A nil user is only a grant in genuine open mode. Confirm it
+		// against the user table rather than trusting the request
+		// context — a route that skipped auth must not inherit admin.
+		n, err := s.store.UserCount()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return false
+		}
+		if n > 0 {
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return false
+		}
+		return true // open mode: local single owner has full control
+	}
+	if !u.Role.AtLeast(min) {
+		writeError(w, http.StatusForbidden, string(min)+" role required")
+		return false
+	}
+	return true
+}
+
+// visibleVehicles returns the vehicles the requesting user may see; in open
+// mode this is every registered vehicle.
+func (s *Server) visibleVehicles(r *http.Request) ([]datastore.Vehicle, error) {
+	if u := currentUser(r); u != nil {
+		return s.store.UserVehicles(u.ID)
+	}
+	return s.store.Vehicles()
+}
+
+// canAccess reports whether the requesting user may see the given VIN.
+func (s *Server) canAccess(r *http.Request, vin string) (bool, error) {
+	u := currentUser(r)
+	if u == nil {
+		return true, nil
+	}
+	return s.store.CanAccessVehicle(u.ID, vin)
+}
+
+// handleCreateUser registers an account. The first account may be created
+// unauthenticated (bootstrap); after that the caller must be authenticated.
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		Username string         `json:"username"`
+		Password string         `json:"password"`
+		Role     datastore.Role `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" || len(req.Password) < 8 {
+		writeError(w, http.StatusBadRequest, "username required and password must be at least 8 characters")
+		return
+	}
+	n, err := s.store.UserCount()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	role := req.Role
+	if n == 0 {
+		// Bootstrap: the first account always administers the instance.
+		role = datastore.RoleAdmin
+	} else {
+		if !s.requireRole(w, r, datastore.RoleAdmin) {
+			return
+		}
+		if role == "" {
+			role = datastore.RoleReadOnly
+		}
+		if !role.Valid() {
+			writeError(w, http.StatusBadRequest, "unknown role")
+			return
+		}
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	u := datastore.User{Username: req.Username, PasswordHash: string(hash), Role: role}
+	if err := s.store.CreateUser(&u); err != nil {
+		writeError(w, http.StatusConflict, "username already taken")
+		return
+	}
+	writeJSON(w, http.StatusCreated, u)
+}
+
+// handleShareVehicle grants or revokes another user's access to a VIN:
+// POST /api/v1/vehicles/share {"vin": ..., "username": ..., "revoke": bool}.
+func (s *Server) handleShareVehicle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		VIN      string `json:"vin"`
+		Username string `json:"username"`
+		Revoke   bool   `json:"revoke"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if ok, err := s.canAccess(r, req.VIN); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	target, err := s.store.UserByName(req.Username)
+	if errors.Is(err, datastore.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "no such user")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.Revoke {
+		err = s.store.RevokeVehicle(target.ID, req.VIN)
+	} else {
+		err = s.store.GrantVehicle(target.ID, req.VIN)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
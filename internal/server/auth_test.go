@@ -0,0 +1,201 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+func newTestTrafficLog(t *testing.T) (*transport.TrafficLog, error) {
+	t.Helper()
+	return transport.NewTrafficLog(filepath.Join(t.TempDir(), "traffic.log"))
+}
+
+// userStore backs the auth handlers with an in-memory user table; the
+// embedded nil Store panics on anything else, which is what we want in
+// these tests.
+type userStore struct {
+	datastore.Store
+	users []datastore.User
+}
+
+func (s *userStore) UserCount() (int, error) { return len(s.users), nil }
+
+func (s *userStore) UserByName(username string) (datastore.User, error) {
+	for _, u := range s.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return datastore.User{}, datastore.ErrNotFound
+}
+
+func (s *userStore) CreateUser(u *datastore.User) error {
+	for _, have := range s.users {
+		if have.Username == u.Username {
+			return datastore.ErrNotFound
+		}
+	}
+	u.ID = int64(len(s.users) + 1)
+	s.users = append(s.users, *u)
+	return nil
+}
+
+func (s *userStore) addUser(t *testing.T, name, password string, role datastore.Role) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.users = append(s.users, datastore.User{
+		ID: int64(len(s.users) + 1), Username: name, PasswordHash: string(hash), Role: role,
+	})
+}
+
+// do runs one request through the server's full middleware stack.
+func do(t *testing.T, srv *Server, method, path, body, user, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	return w
+}
+
+func TestBootstrapThenAnonymousCreateRejected(t *testing.T) {
+	store := &userStore{}
+	srv := New(store)
+
+	// Bootstrap: the first account may be created unauthenticated and
+	// always administers the instance.
+	w := do(t, srv, http.MethodPost, "/api/v1/users",
+		`{"username":"owner","password":"password1","role":"readonly"}`, "", "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("bootstrap create = %d: %s", w.Code, w.Body)
+	}
+	u, err := store.UserByName("owner")
+	if err != nil || u.Role != datastore.RoleAdmin {
+		t.Fatalf("bootstrap account = %+v (err %v), want admin", u, err)
+	}
+
+	// The window is closed: an anonymous create must not mint accounts
+	// once any user exists.
+	w = do(t, srv, http.MethodPost, "/api/v1/users",
+		`{"username":"intruder","password":"password1","role":"admin"}`, "", "")
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous create after bootstrap = %d, want 401", w.Code)
+	}
+	if _, err := store.UserByName("intruder"); err == nil {
+		t.Fatal("anonymous create after bootstrap created an account")
+	}
+}
+
+func TestCreateUserRequiresAdmin(t *testing.T) {
+	store := &userStore{}
+	store.addUser(t, "admin", "password1", datastore.RoleAdmin)
+	store.addUser(t, "op", "password1", datastore.RoleOperator)
+	srv := New(store)
+
+	w := do(t, srv, http.MethodPost, "/api/v1/users",
+		`{"username":"new","password":"password1"}`, "op", "password1")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("operator create = %d, want 403", w.Code)
+	}
+	w = do(t, srv, http.MethodPost, "/api/v1/users",
+		`{"username":"new","password":"password1"}`, "admin", "password1")
+	if w.Code != http.StatusCreated {
+		t.Errorf("admin create = %d: %s", w.Code, w.Body)
+	}
+	if u, err := store.UserByName("new"); err != nil || u.Role != datastore.RoleReadOnly {
+		t.Errorf("created account = %+v (err %v), want readonly default", u, err)
+	}
+}
+
+func TestAuthRejectsBadCredentials(t *testing.T) {
+	store := &userStore{}
+	store.addUser(t, "admin", "password1", datastore.RoleAdmin)
+	srv := New(store)
+
+	if w := do(t, srv, http.MethodGet, "/api/v1/can/health", "", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request = %d, want 401", w.Code)
+	}
+	if w := do(t, srv, http.MethodGet, "/api/v1/can/health", "", "admin", "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password = %d, want 401", w.Code)
+	}
+	if w := do(t, srv, http.MethodGet, "/api/v1/can/health", "", "admin", "password1"); w.Code == http.StatusUnauthorized {
+		t.Errorf("valid credentials rejected: %d", w.Code)
+	}
+}
+
+func TestOperatorEndpointsEnforceRoles(t *testing.T) {
+	store := &userStore{}
+	store.addUser(t, "admin", "password1", datastore.RoleAdmin)
+	store.addUser(t, "op", "password1", datastore.RoleOperator)
+	store.addUser(t, "viewer", "password1", datastore.RoleReadOnly)
+	srv := New(store)
+	log, err := newTestTrafficLog(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetTrafficLog(log)
+
+	body := `{"enabled":false}`
+	if w := do(t, srv, http.MethodPost, "/api/v1/debug/traffic-log", body, "viewer", "password1"); w.Code != http.StatusForbidden {
+		t.Errorf("readonly toggle = %d, want 403", w.Code)
+	}
+	if w := do(t, srv, http.MethodPost, "/api/v1/debug/traffic-log", body, "op", "password1"); w.Code != http.StatusOK {
+		t.Errorf("operator toggle = %d: %s", w.Code, w.Body)
+	}
+	if w := do(t, srv, http.MethodPost, "/api/v1/debug/traffic-log", body, "admin", "password1"); w.Code != http.StatusOK {
+		t.Errorf("admin toggle = %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestRequireRoleInOpenMode(t *testing.T) {
+	// No users at all: open single-owner mode, everything is allowed.
+	srv := New(&userStore{})
+	log, err := newTestTrafficLog(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetTrafficLog(log)
+	w := do(t, srv, http.MethodPost, "/api/v1/debug/traffic-log", `{"enabled":true}`, "", "")
+	if w.Code != http.StatusOK {
+		t.Errorf("open-mode operator action = %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestSyncBearerTokenScopedToSync(t *testing.T) {
+	store := &userStore{}
+	store.addUser(t, "admin", "password1", datastore.RoleAdmin)
+	srv := New(store)
+	srv.SetSyncToken("edge-secret")
+
+	bearer := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("[]"))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	if w := bearer("/api/v1/sync/telemetry", "edge-secret"); w.Code != http.StatusOK {
+		t.Errorf("valid token on sync = %d: %s", w.Code, w.Body)
+	}
+	if w := bearer("/api/v1/sync/telemetry", "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad token = %d, want 401", w.Code)
+	}
+	// The token authenticates uploads only — it must not open the rest
+	// of the API.
+	if w := bearer("/api/v1/can/health", "edge-secret"); w.Code != http.StatusUnauthorized {
+		t.Errorf("token outside /sync/ = %d, want 401", w.Code)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// filterTarget is one bus whose acceptance filters the API can change
+// at runtime.
+type filterTarget struct {
+	setter  transport.FilterSetter
+	current []string
+}
+
+// AddFilterTarget registers a bus that supports runtime acceptance
+// filters, recording whatever filters were applied at startup; called
+// once per capable transport.
+func (s *Server) AddFilterTarget(bus string, t transport.FilterSetter, applied []transport.Filter) {
+	if s.filterTargets == nil {
+		s.filterTargets = make(map[string]*filterTarget)
+	}
+	ft := &filterTarget{setter: t}
+	for _, f := range applied {
+		ft.current = append(ft.current, f.String())
+	}
+	s.filterTargets[bus] = ft
+}
+
+// handleCANFilters reports and changes CAN acceptance filters:
+// GET/POST /api/v1/can/filters. Operator only on POST — a wrong mask
+// silently blinds every consumer on the bus.
+func (s *Server) handleCANFilters(w http.ResponseWriter, r *http.Request) {
+	if len(s.filterTargets) == 0 {
+		writeError(w, http.StatusNotFound, "no filter-capable bus configured")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		out := make(map[string][]string, len(s.filterTargets))
+		for bus, ft := range s.filterTargets {
+			out[bus] = append([]string(nil), ft.current...)
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		if !s.requireRole(w, r, datastore.RoleOperator) {
+			return
+		}
+		var req struct {
+			Bus     string   `json:"bus"`
+			Filters []string `json:"filters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		ft, ok := s.filterTargets[req.Bus]
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown bus: "+req.Bus)
+			return
+		}
+		filters, err := transport.ParseFilters(req.Filters)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := ft.setter.SetFilters(filters); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ft.current = append([]string(nil), req.Filters...)
+		writeJSON(w, http.StatusOK, map[string][]string{req.Bus: ft.current})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anodyne74/iload-obd2/internal/canid"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// SetCANRegistry attaches the CAN ID label registry so the API can serve
+// and edit it.
+func (s *Server) SetCANRegistry(r *canid.Registry) { s.canIDs = r }
+
+// handleCANLabels serves and edits the CAN ID label registry.
+func (s *Server) handleCANLabels(w http.ResponseWriter, r *http.Request) {
+	if s.canIDs == nil {
+		writeError(w, http.StatusNotFound, "no CAN label registry configured")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.canIDs.All())
+	case http.MethodPost:
+		if !s.requireRole(w, r, datastore.RoleOperator) {
+			return
+		}
+		var req canid.Label
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		if err := s.canIDs.Set(req.ID, req.Name); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.canIDs.All())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// ObserveTransportHealth broadcasts an adapter link state change to
+// websocket clients as a "transport" message — wire it to a
+// Reconnector's OnChange hook.
+func (s *Server) ObserveTransportHealth(h transport.ConnHealth) {
+	s.Broadcast("transport", h)
+}
+
+// handleCANHealth reports the controller state of every configured bus.
+func (s *Server) handleCANHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if len(s.busMonitors) == 0 {
+		writeError(w, http.StatusNotFound, "no CAN interface configured")
+		return
+	}
+	out := make([]transport.BusHealth, 0, len(s.busMonitors))
+	for _, m := range s.busMonitors {
+		out = append(out, m.Health())
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// handleCaptureTrigger fires the event recorder manually — the "save
+// that" button on the dashboard:
+// POST /api/v1/capture/trigger {"vin": ..., "reason": "driver-report"}.
+func (s *Server) handleCaptureTrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, datastore.RoleOperator) {
+		return
+	}
+	if s.recorder == nil {
+		writeError(w, http.StatusNotFound, "event recorder not enabled")
+		return
+	}
+	var req struct {
+		VIN    string `json:"vin"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if ok, err := s.canAccess(r, req.VIN); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "manual"
+	}
+	s.recorder.Trigger(req.VIN, reason)
+	writeJSON(w, http.StatusOK, map[string]string{"triggered": reason})
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// SetTrafficLog attaches the raw traffic log so the API can toggle it
+// at runtime.
+func (s *Server) SetTrafficLog(l *transport.TrafficLog) { s.trafficLog = l }
+
+// handleTrafficLog reports and toggles raw traffic logging:
+// GET/POST /api/v1/debug/traffic-log. Operator only — the log file can
+// grow quickly on a busy bus.
+func (s *Server) handleTrafficLog(w http.ResponseWriter, r *http.Request) {
+	if s.trafficLog == nil {
+		writeError(w, http.StatusNotFound, "no traffic log configured")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]bool{"enabled": s.trafficLog.Enabled()})
+	case http.MethodPost:
+		if !s.requireRole(w, r, datastore.RoleOperator) {
+			return
+		}
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		s.trafficLog.SetEnabled(req.Enabled)
+		writeJSON(w, http.StatusOK, map[string]bool{"enabled": req.Enabled})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
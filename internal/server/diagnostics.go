@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// DTCClearer clears stored trouble codes over the attached vehicle bus
+// and re-reads what remains. Satisfied by the obd client; left nil on
+// central deployments with no bus of their own.
+type DTCClearer interface {
+	ClearDTCs() error
+	ReadDTCs() ([]string, error)
+}
+
+// SetDTCClearer attaches the bus-side DTC clearing path.
+func (s *Server) SetDTCClearer(c DTCClearer) { s.dtcClearer = c }
+
+// handleClearDTCs erases stored trouble codes (mode 04) and confirms
+// with a mode 03 re-read: POST /api/v1/dtc/clear. Admin only — clearing
+// also wipes freeze frames and resets readiness monitors.
+func (s *Server) handleClearDTCs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, datastore.RoleAdmin) {
+		return
+	}
+	if s.dtcClearer == nil {
+		writeError(w, http.StatusNotFound, "no vehicle bus attached")
+		return
+	}
+	if err := s.dtcClearer.ClearDTCs(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	remaining, err := s.dtcClearer.ReadDTCs()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "cleared, but confirmation re-read failed: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"cleared":   true,
+		"remaining": remaining,
+	})
+}
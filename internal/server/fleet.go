@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/rollup"
+)
+
+// VehicleStatus is one vehicle's entry in the fleet status response.
+type VehicleStatus struct {
+	Vehicle   datastore.Vehicle        `json:"vehicle"`
+	Telemetry *datastore.TelemetryData `json:"telemetry,omitempty"`
+	DTCs      []string                 `json:"dtcs,omitempty"`
+	Alerts    []datastore.Alert        `json:"alerts,omitempty"`
+	LastSeen  *time.Time               `json:"last_seen,omitempty"`
+}
+
+// handleFleetRanking compares vehicles by efficiency score, idle
+// percentage and harsh-event rate over recent rollup buckets:
+// GET /api/v1/fleet/ranking?period=weekly&buckets=4.
+func (s *Server) handleFleetRanking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	vehicles, err := s.visibleVehicles(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	vins := make([]string, len(vehicles))
+	for i, v := range vehicles {
+		vins[i] = v.VIN
+	}
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "weekly"
+	}
+	buckets, _ := strconv.Atoi(r.URL.Query().Get("buckets"))
+	rankings, err := rollup.Rank(s.store, vins, period, buckets)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"period":   period,
+		"rankings": rankings,
+	})
+}
+
+// handleFleetStatus returns the latest state of every vehicle in one call,
+// for fleet dashboards. Telemetry comes from the latest-telemetry cache so
+// this stays cheap even when polled frequently.
+func (s *Server) handleFleetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	vehicles, err := s.visibleVehicles(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	statuses := make([]VehicleStatus, 0, len(vehicles))
+	for _, v := range vehicles {
+		st := VehicleStatus{Vehicle: v}
+		t, err := s.store.LatestTelemetry(v.VIN)
+		switch {
+		case err == nil:
+			st.Telemetry = &t
+			st.DTCs = t.DTCs
+			// Render in the device zone so the offset is explicit.
+			ts := t.Timestamp.In(time.Local)
+			st.LastSeen = &ts
+		case !errors.Is(err, datastore.ErrNotFound):
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		alerts, err := s.store.Alerts(v.VIN, true)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		st.Alerts = alerts
+		statuses = append(statuses, st)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vehicles":     statuses,
+		"generated_at": time.Now(),
+		"timezone":     time.Local.String(),
+	})
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/obd"
+	"github.com/anodyne74/iload-obd2/internal/pipeline"
+)
+
+// Ingest runs one accepted telemetry sample through enrichment, the
+// live observers and the rate-decoupling pipeline. It is the shared
+// path behind both the local poll loop and agent uploads; callers
+// validate and register vehicles before handing samples in.
+func (s *Server) Ingest(t datastore.TelemetryData) {
+	// Enrichment stamps derived estimates onto the sample before it is
+	// broadcast or persisted.
+	if len(t.DTCs) > 0 {
+		t.DTCDetails = obd.DescribeAll(t.DTCs)
+	}
+	if s.sootWatcher != nil {
+		s.sootWatcher.Enrich(&t)
+	}
+	if s.derived != nil {
+		s.derived.Enrich(&t)
+	}
+	if s.plugins != nil {
+		for name, v := range s.plugins.Derive(t) {
+			if t.Derived == nil {
+				t.Derived = make(map[string]float64)
+			}
+			t.Derived[name] = v
+		}
+	}
+	s.pipelineFor(t.VIN).Offer(t)
+	if s.dtcWatcher != nil {
+		s.dtcWatcher.Observe(t)
+	}
+	if s.overheat != nil {
+		s.overheat.Observe(t)
+	}
+	if s.recorder != nil {
+		s.recorder.Observe(t)
+	}
+	if s.plugins != nil {
+		for _, a := range s.plugins.CheckAlert(t) {
+			alert := a
+			if err := s.store.SaveAlert(&alert); err != nil {
+				log.Printf("ingest: save plugin alert: %v", err)
+			}
+		}
+	}
+}
+
+// SetPipelineIntervals configures how often ingested samples reach the
+// dashboard broadcast and the datastore; zero intervals pass every
+// sample straight through. Safe to call while serving — a config push
+// flushes the existing pipelines and the next sample rebuilds each at
+// the new rates.
+func (s *Server) SetPipelineIntervals(broadcast, persist time.Duration) {
+	s.pipeMu.Lock()
+	if broadcast == s.broadcastEvery && persist == s.persistEvery {
+		s.pipeMu.Unlock()
+		return
+	}
+	s.broadcastEvery = broadcast
+	s.persistEvery = persist
+	old := s.pipes
+	s.pipes = nil
+	s.pipeMu.Unlock()
+	for _, p := range old {
+		p.Flush()
+	}
+}
+
+// pipelineFor returns the VIN's pipeline, building it on first sight —
+// one per vehicle, so persist aggregation never mixes vans.
+func (s *Server) pipelineFor(vin string) *pipeline.Pipeline {
+	s.pipeMu.Lock()
+	defer s.pipeMu.Unlock()
+	if s.pipes == nil {
+		s.pipes = make(map[string]*pipeline.Pipeline)
+	}
+	p, ok := s.pipes[vin]
+	if !ok {
+		p = pipeline.New(s.broadcastEvery, s.persistEvery)
+		p.OnBroadcast = func(t datastore.TelemetryData) {
+			// Live dashboards see samples at the broadcast rate.
+			s.Broadcast("telemetry", t)
+		}
+		p.OnPersist = func(t datastore.TelemetryData) {
+			if err := s.store.SaveTelemetry(t); err != nil {
+				log.Printf("ingest: save telemetry: %v", err)
+			}
+		}
+		s.pipes[vin] = p
+	}
+	return p
+}
+
+// FlushPipelines pushes any partially aggregated samples out to the
+// store — call it at shutdown or key-off so the tail of a drive isn't
+// lost to the persist window.
+func (s *Server) FlushPipelines() {
+	s.pipeMu.Lock()
+	pipes := make([]*pipeline.Pipeline, 0, len(s.pipes))
+	for _, p := range s.pipes {
+		pipes = append(pipes, p)
+	}
+	s.pipeMu.Unlock()
+	for _, p := range pipes {
+		p.Flush()
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+)
+
+// metricDescriptor tells the web UI how to render one channel — enough
+// to auto-build a gauge without the frontend hardcoding anything. Zero
+// Min/Max means the range is unknown and the UI should auto-scale.
+type metricDescriptor struct {
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit,omitempty"`
+	Group    string  `json:"group"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Decimals int     `json:"decimals"`
+}
+
+// builtinMetrics describes every polled channel ChannelValue resolves.
+// Ranges are display ranges for the iLoad, not validation bounds.
+var builtinMetrics = []metricDescriptor{
+	{Name: "rpm", Unit: "rpm", Group: "engine", Min: 0, Max: 6000},
+	{Name: "speed", Unit: "km/h", Group: "vehicle", Min: 0, Max: 180},
+	{Name: "coolant_temp", Unit: "°C", Group: "engine", Min: -40, Max: 130},
+	{Name: "engine_load", Unit: "%", Group: "engine", Min: 0, Max: 100},
+	{Name: "throttle_pos", Unit: "%", Group: "engine", Min: 0, Max: 100},
+	{Name: "intake_temp", Unit: "°C", Group: "engine", Min: -40, Max: 80},
+	{Name: "maf", Unit: "g/s", Group: "engine", Min: 0, Max: 300, Decimals: 1},
+	{Name: "fuel_level", Unit: "%", Group: "fuel", Min: 0, Max: 100},
+	{Name: "fuel_rate", Unit: "L/h", Group: "fuel", Min: 0, Max: 50, Decimals: 1},
+	{Name: "short_fuel_trim_1", Unit: "%", Group: "fuel", Min: -25, Max: 25, Decimals: 1},
+	{Name: "long_fuel_trim_1", Unit: "%", Group: "fuel", Min: -25, Max: 25, Decimals: 1},
+	{Name: "short_fuel_trim_2", Unit: "%", Group: "fuel", Min: -25, Max: 25, Decimals: 1},
+	{Name: "long_fuel_trim_2", Unit: "%", Group: "fuel", Min: -25, Max: 25, Decimals: 1},
+	{Name: "equiv_ratio", Unit: "λ", Group: "fuel", Min: 0, Max: 8, Decimals: 2},
+	{Name: "o2_lambda", Unit: "λ", Group: "fuel", Min: 0, Max: 8, Decimals: 2},
+	{Name: "o2_current", Unit: "mA", Group: "fuel", Min: -10, Max: 10, Decimals: 2},
+	{Name: "ambient_temp", Unit: "°C", Group: "environment", Min: -40, Max: 50},
+	{Name: "baro_kpa", Unit: "kPa", Group: "environment", Min: 80, Max: 110, Decimals: 1},
+	{Name: "voltage", Unit: "V", Group: "electrical", Min: 8, Max: 16, Decimals: 1},
+	{Name: "dpf_soot", Unit: "%", Group: "emissions", Min: 0, Max: 100},
+	{Name: "manifold_kpa", Unit: "kPa", Group: "engine", Min: 0, Max: 300},
+	{Name: "rail_bar", Unit: "bar", Group: "fuel", Min: 0, Max: 1800},
+	{Name: "egr_commanded", Unit: "%", Group: "emissions", Min: 0, Max: 100},
+	{Name: "egr_actual", Unit: "%", Group: "emissions", Min: 0, Max: 100},
+	{Name: "dpf_diff_kpa", Unit: "kPa", Group: "emissions", Min: 0, Max: 30, Decimals: 1},
+}
+
+// handleMetrics serves the channel catalog, including derived channels,
+// so the dashboard builds its gauges from what this instance actually
+// computes: GET /api/v1/metrics.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	metrics := make([]metricDescriptor, len(builtinMetrics))
+	copy(metrics, builtinMetrics)
+	if s.derived != nil {
+		for _, c := range s.derived.Channels() {
+			metrics = append(metrics, metricDescriptor{
+				Name:     c.Name,
+				Unit:     c.Unit,
+				Group:    "derived",
+				Decimals: 1,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"metrics": metrics})
+}
@@ -0,0 +1,95 @@
+package server
+
+import "net/http"
+
+const apiVersion = "1.0.0"
+
+// handleOpenAPI serves an OpenAPI 3 document generated from the route
+// table, so client generators work against a live instance.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	paths := map[string]map[string]interface{}{}
+	for _, rt := range s.routes {
+		ops, ok := paths[rt.Path]
+		if !ok {
+			ops = map[string]interface{}{}
+			paths[rt.Path] = ops
+		}
+		op := map[string]interface{}{
+			"summary": rt.Summary,
+			"tags":    []string{rt.Tag},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "OK",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{},
+					},
+				},
+			},
+		}
+		if rt.Method == "POST" {
+			op["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{},
+				},
+			}
+		}
+		ops[lowerMethod(rt.Method)] = op
+	}
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "iload-obd2 API",
+			"description": "CANBUS data logger for Hyundai iLoad/H-1",
+			"version":     apiVersion,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"basicAuth": map[string]interface{}{
+					"type":   "http",
+					"scheme": "basic",
+				},
+			},
+		},
+		"security": []map[string][]string{{"basicAuth": {}}},
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func lowerMethod(m string) string {
+	switch m {
+	case "GET":
+		return "get"
+	case "POST":
+		return "post"
+	case "PUT":
+		return "put"
+	case "DELETE":
+		return "delete"
+	case "PATCH":
+		return "patch"
+	}
+	return m
+}
+
+const docsHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <title>iload-obd2 API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "/api/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// handleDocs serves a Swagger UI page pointed at the generated spec.
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsHTML))
+}
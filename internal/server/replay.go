@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Replay pacing limits. Speed is a multiplier on recorded time: 1 is
+// real time, 10 plays an hour in six minutes. Gaps in the recording are
+// capped so a replay never stalls on an overnight park.
+const (
+	minReplaySpeed = 0.1
+	maxReplaySpeed = 100
+	maxReplayGap   = 5 * time.Second
+)
+
+// wsCommand is the envelope for messages a client sends on the socket.
+type wsCommand struct {
+	Cmd   string  `json:"cmd"`
+	VIN   string  `json:"vin,omitempty"`
+	From  string  `json:"from,omitempty"`
+	To    string  `json:"to,omitempty"`
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// handleWSCommand dispatches one inbound WebSocket message. r is the
+// upgrade request, kept so replay honours the caller's vehicle access.
+func (s *Server) handleWSCommand(c *wsClient, r *http.Request, raw []byte) {
+	var cmd wsCommand
+	if err := json.Unmarshal(raw, &cmd); err != nil {
+		s.sendTo(c, "error", map[string]string{"error": "invalid command"})
+		return
+	}
+	switch cmd.Cmd {
+	case "replay":
+		s.startReplay(c, r, cmd)
+	case "stop":
+		c.stopReplay()
+	default:
+		s.sendTo(c, "error", map[string]string{"error": "unknown command " + cmd.Cmd})
+	}
+}
+
+// startReplay streams stored telemetry for a VIN and time range to this
+// client only, paced by the recorded timestamps scaled by cmd.Speed. A
+// new replay command replaces any replay already running on the socket.
+func (s *Server) startReplay(c *wsClient, r *http.Request, cmd wsCommand) {
+	if ok, err := s.canAccess(r, cmd.VIN); err != nil || !ok {
+		s.sendTo(c, "error", map[string]string{"error": "no access to vehicle"})
+		return
+	}
+	from, err := time.Parse(time.RFC3339, cmd.From)
+	if err != nil {
+		s.sendTo(c, "error", map[string]string{"error": "from must be RFC3339"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, cmd.To)
+	if err != nil {
+		s.sendTo(c, "error", map[string]string{"error": "to must be RFC3339"})
+		return
+	}
+	speed := cmd.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	if speed < minReplaySpeed || speed > maxReplaySpeed {
+		s.sendTo(c, "error", map[string]string{"error": "speed out of range"})
+		return
+	}
+	samples, err := s.store.Telemetry(cmd.VIN, from, to)
+	if err != nil {
+		s.sendTo(c, "error", map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.setReplay(cancel)
+	go func() {
+		defer cancel()
+		prev := time.Time{}
+		for _, t := range samples {
+			if !prev.IsZero() {
+				gap := t.Timestamp.Sub(prev)
+				if gap > maxReplayGap {
+					gap = maxReplayGap
+				}
+				select {
+				case <-time.After(time.Duration(float64(gap) / speed)):
+				case <-ctx.Done():
+					return
+				}
+			}
+			prev = t.Timestamp
+			if !s.sendTo(c, "replay", t) {
+				return
+			}
+		}
+		s.sendTo(c, "replay_done", map[string]interface{}{
+			"vin":     cmd.VIN,
+			"samples": len(samples),
+		})
+	}()
+}
+
+// sendTo delivers a typed message to a single client in its negotiated
+// framing, reporting false when the client is gone or can't keep up.
+func (s *Server) sendTo(c *wsClient, msgType string, data interface{}) bool {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return false
+	}
+	msg := Message{Seq: s.hub.seq.Add(1), Type: msgType, Data: raw}
+	var payload []byte
+	if c.cbor {
+		payload, err = cbor.Marshal(msg)
+	} else {
+		payload, err = json.Marshal(msg)
+	}
+	if err != nil {
+		return false
+	}
+	select {
+	case c.send <- payload:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,181 @@
+// Package server implements the HTTP API exposed by the logger daemon.
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/alerts"
+	"github.com/anodyne74/iload-obd2/internal/canid"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/dbc"
+	"github.com/anodyne74/iload-obd2/internal/derived"
+	"github.com/anodyne74/iload-obd2/internal/pipeline"
+	"github.com/anodyne74/iload-obd2/internal/plugin"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+	"github.com/anodyne74/iload-obd2/internal/validate"
+)
+
+// Server routes API requests to the datastore and live pipeline.
+type Server struct {
+	store       datastore.Store
+	mux         *http.ServeMux
+	hub         *hub
+	routes      []route
+	dtcWatcher  *alerts.DTCWatcher
+	overheat    *alerts.OverheatPredictor
+	sootWatcher *alerts.SootWatcher
+	recorder    *capture.Recorder
+	validator   *validate.Validator
+	derived     *derived.Engine
+	plugins     *plugin.Engine
+	canIDs      *canid.Registry
+	busMonitors []*transport.BusMonitor
+	dtcClearer  DTCClearer
+	dbcDB       *dbc.Database
+	trafficLog  *transport.TrafficLog
+	syncToken   string
+	captureDir  string
+
+	filterTargets map[string]*filterTarget
+
+	// Per-VIN pipelines decouple broadcast and persist rates from the
+	// ingest rate; see SetPipelineIntervals.
+	pipeMu         sync.Mutex
+	pipes          map[string]*pipeline.Pipeline
+	broadcastEvery time.Duration
+	persistEvery   time.Duration
+}
+
+// AddBusMonitor attaches a CAN interface health monitor so its state is
+// queryable over the API; called once per configured bus.
+func (s *Server) AddBusMonitor(m *transport.BusMonitor) {
+	s.busMonitors = append(s.busMonitors, m)
+}
+
+// SetDTCWatcher attaches the first-appearance DTC watcher; ingested
+// telemetry is run through it.
+func (s *Server) SetDTCWatcher(w *alerts.DTCWatcher) { s.dtcWatcher = w }
+
+// SetOverheatPredictor attaches the coolant-trend predictor; ingested
+// telemetry is run through it.
+func (s *Server) SetOverheatPredictor(p *alerts.OverheatPredictor) { s.overheat = p }
+
+// SetSootWatcher attaches the DPF soot model; ingested telemetry is
+// enriched with the estimate before being stored.
+func (s *Server) SetSootWatcher(w *alerts.SootWatcher) { s.sootWatcher = w }
+
+// SetRecorder attaches the pre-trigger event recorder; ingested
+// telemetry feeds its ring and the manual trigger endpoint fires it.
+func (s *Server) SetRecorder(rec *capture.Recorder) { s.recorder = rec }
+
+// SetDerivedEngine attaches the derived channel engine; ingested
+// telemetry is stamped with computed channels before being stored.
+func (s *Server) SetDerivedEngine(e *derived.Engine) { s.derived = e }
+
+// SetPluginEngine attaches the Lua scripting engine; its derive and
+// check_alert hooks run on every ingested sample.
+func (s *Server) SetPluginEngine(e *plugin.Engine) { s.plugins = e }
+
+// SetSyncToken sets the bearer token edge agents authenticate with on
+// the sync endpoints. Empty disables token auth; agents then need basic
+// credentials like any other client.
+func (s *Server) SetSyncToken(token string) { s.syncToken = token }
+
+// SetCaptureDir names the directory uploaded capture sessions are
+// stored in. Empty disables the capture sync endpoint.
+func (s *Server) SetCaptureDir(dir string) { s.captureDir = dir }
+
+// New builds a Server on top of the given store.
+func New(store datastore.Store) *Server {
+	s := &Server{store: store, mux: http.NewServeMux(), hub: newHub(), validator: validate.New()}
+	s.registerRoutes()
+	return s
+}
+
+// route is one registered endpoint plus the metadata the OpenAPI
+// generator needs. Handlers that serve several methods register one
+// route entry per documented method.
+type route struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+}
+
+// handle registers a handler together with its documentation entries.
+func (s *Server) handle(pattern string, h http.HandlerFunc, docs ...route) {
+	s.mux.HandleFunc(pattern, h)
+	s.routes = append(s.routes, docs...)
+}
+
+func (s *Server) registerRoutes() {
+	s.handle("/api/v1/fleet/status", s.handleFleetStatus,
+		route{"GET", "/api/v1/fleet/status", "Latest state of every visible vehicle", "fleet"})
+	s.handle("/api/v1/fleet/ranking", s.handleFleetRanking,
+		route{"GET", "/api/v1/fleet/ranking", "Efficiency ranking across the fleet", "fleet"})
+	s.handle("/api/v1/users", s.handleCreateUser,
+		route{"POST", "/api/v1/users", "Create a user account", "users"})
+	s.handle("/api/v1/vehicles/share", s.handleShareVehicle,
+		route{"POST", "/api/v1/vehicles/share", "Grant or revoke another user's access to a VIN", "vehicles"})
+	s.handle("/api/v1/vehicles/", s.handleVehicle,
+		route{"GET", "/api/v1/vehicles/{vin}", "Fetch a vehicle record", "vehicles"},
+		route{"DELETE", "/api/v1/vehicles/{vin}", "Delete a vehicle (admin)", "vehicles"},
+		route{"POST", "/api/v1/vehicles/{vin}/purge", "Delete every record held for a VIN (admin)", "vehicles"},
+		route{"GET", "/api/v1/vehicles/{vin}/rollups", "Weekly or monthly trend summaries", "vehicles"},
+		route{"GET", "/api/v1/vehicles/{vin}/oil-life", "Oil life estimate since the last service", "vehicles"},
+		route{"GET", "/api/v1/vehicles/{vin}/trips", "Search trips by tag and date range", "vehicles"},
+		route{"GET", "/api/v1/vehicles/{vin}/captures", "Search capture sessions by tag and date range", "vehicles"})
+	s.handle("/api/v1/sync/telemetry", s.handleSyncTelemetry,
+		route{"POST", "/api/v1/sync/telemetry", "Ingest a telemetry batch from an edge agent", "sync"})
+	s.handle("/api/v1/sync/alerts", s.handleSyncAlerts,
+		route{"POST", "/api/v1/sync/alerts", "Ingest an alert batch from an edge agent", "sync"})
+	s.handle("/api/v1/sync/captures", s.handleSyncCaptures,
+		route{"POST", "/api/v1/sync/captures", "Ingest capture session files from an edge agent", "sync"})
+	s.handle("/api/v1/sync/stats", s.handleSyncStats,
+		route{"GET", "/api/v1/sync/stats", "Validation rejection counters for ingested telemetry", "sync"})
+	s.handle("/api/v1/can/labels", s.handleCANLabels,
+		route{"GET", "/api/v1/can/labels", "List CAN ID labels", "can"},
+		route{"POST", "/api/v1/can/labels", "Add or update a CAN ID label (operator)", "can"})
+	s.handle("/api/v1/capture/trigger", s.handleCaptureTrigger,
+		route{"POST", "/api/v1/capture/trigger", "Manually trigger an event capture (operator)", "capture"})
+	s.handle("/api/v1/dtc/clear", s.handleClearDTCs,
+		route{"POST", "/api/v1/dtc/clear", "Clear stored trouble codes on the attached bus (admin)", "diagnostics"})
+	s.handle("/api/v1/can/health", s.handleCANHealth,
+		route{"GET", "/api/v1/can/health", "CAN interface controller state and restart count", "can"})
+	s.handle("/api/v1/can/filters", s.handleCANFilters,
+		route{"GET", "/api/v1/can/filters", "Acceptance filters active on each bus", "can"},
+		route{"POST", "/api/v1/can/filters", "Replace a bus's acceptance filters (operator)", "can"})
+	s.handle("/api/v1/debug/traffic-log", s.handleTrafficLog,
+		route{"GET", "/api/v1/debug/traffic-log", "Raw traffic logging state", "debug"},
+		route{"POST", "/api/v1/debug/traffic-log", "Toggle raw traffic logging (operator)", "debug"})
+	s.handle("/api/v1/metrics", s.handleMetrics,
+		route{"GET", "/api/v1/metrics", "Channel catalog for building dashboard gauges", "meta"})
+	s.handle("/api/openapi.json", s.handleOpenAPI,
+		route{"GET", "/api/openapi.json", "This OpenAPI document", "meta"})
+	s.mux.HandleFunc("/api/docs", s.handleDocs)
+	s.mux.HandleFunc("/ws", s.handleWS)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Every route resolves auth, including user creation: withAuth lets
+	// requests through unauthenticated only while no users exist, which
+	// is exactly the bootstrap window the first account needs.
+	s.withAuth(s.mux).ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("server: encode response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
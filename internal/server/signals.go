@@ -0,0 +1,39 @@
+package server
+
+import (
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/dbc"
+)
+
+// SetDBC attaches a Vector DBC signal database; frames offered to
+// ObserveFrame are decoded through it onto the live stream.
+func (s *Server) SetDBC(db *dbc.Database) { s.dbcDB = db }
+
+// signalEvent is the websocket payload for one decoded frame.
+type signalEvent struct {
+	ID      uint32              `json:"id"`
+	Bus     string              `json:"bus,omitempty"`
+	Message string              `json:"message"`
+	Signals []dbc.DecodedSignal `json:"signals"`
+}
+
+// ObserveFrame decodes one raw bus frame through the configured DBC
+// and broadcasts the signal values to websocket subscribers as a
+// "signals" event. Frames without a definition, and all frames when no
+// DBC is configured, are dropped silently — this sits on the hot
+// receive path.
+func (s *Server) ObserveFrame(f capture.CANFrame) {
+	if s.dbcDB == nil {
+		return
+	}
+	dec, ok := s.dbcDB.Decode(f)
+	if !ok || len(dec.Signals) == 0 {
+		return
+	}
+	s.Broadcast("signals", signalEvent{
+		ID:      f.Arbitration(),
+		Bus:     f.Bus,
+		Message: dec.Message,
+		Signals: dec.Signals,
+	})
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/vin"
+	iloadpb "github.com/anodyne74/iload-obd2/proto/iload/v1"
+)
+
+// Sync ingest endpoints receive batches uploaded by edge agents running
+// in offline-first mode. Vehicles are auto-registered on first sight so
+// an agent doesn't need a provisioning step.
+
+func (s *Server) handleSyncTelemetry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var batch []datastore.TelemetryData
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	seen := map[string]bool{}
+	rejected := 0
+	for i := range batch {
+		t := batch[i]
+		// Implausible samples are dropped before they can poison alerts,
+		// storage or analysis; the validator keeps per-reason counters.
+		if ok, reason := s.validator.Check(t); !ok {
+			log.Printf("sync: rejected sample from %s: %s", t.VIN, reason)
+			rejected++
+			continue
+		}
+		if t.VIN != "" && !seen[t.VIN] {
+			seen[t.VIN] = true
+			if _, err := s.store.Vehicle(t.VIN); err != nil {
+				veh := datastore.Vehicle{VIN: t.VIN}
+				// A well-formed VIN registers with its decoded
+				// make/model/year; a malformed one still registers bare.
+				if info, err := vin.Decode(t.VIN); err == nil {
+					veh.Make, veh.Model, veh.Year = info.Make, info.Model, info.Year
+				}
+				if err := s.store.RegisterVehicle(veh); err != nil {
+					writeError(w, http.StatusInternalServerError, err.Error())
+					return
+				}
+			}
+		}
+		// Enrichment, observers and the broadcast/persist pipeline all
+		// live on the shared ingest path.
+		s.Ingest(t)
+	}
+	writeJSON(w, http.StatusOK, map[string]int{
+		"accepted": len(batch) - rejected,
+		"rejected": rejected,
+	})
+}
+
+// handleSyncStats reports how many ingested samples failed plausibility
+// validation, by reason: GET /api/v1/sync/stats.
+func (s *Server) handleSyncStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"rejected": s.validator.Counters(),
+	})
+}
+
+// SyncCapture is one uploaded capture session: its registry record plus
+// the session file's raw bytes.
+type SyncCapture struct {
+	datastore.CaptureRecord
+	Data []byte `json:"data"`
+}
+
+// handleSyncCaptures receives capture session files recorded on an edge
+// agent and files them under the central captures directory.
+func (s *Server) handleSyncCaptures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.captureDir == "" {
+		writeError(w, http.StatusNotFound, "capture sync not enabled")
+		return
+	}
+	var batch []SyncCapture
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := os.MkdirAll(s.captureDir, 0o755); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for i := range batch {
+		c := batch[i]
+		// Only the base name survives — the agent's paths are local to
+		// its own filesystem and must not steer writes here.
+		path := filepath.Join(s.captureDir, filepath.Base(c.Path))
+		if err := os.WriteFile(path, c.Data, 0o644); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rec := c.CaptureRecord
+		rec.ID = 0 // IDs are local to the agent's database
+		rec.Path = path
+		if err := s.store.RegisterCapture(&rec); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"accepted": len(batch)})
+}
+
+func (s *Server) handleSyncAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var batch []datastore.Alert
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-protobuf") {
+		// Current agents upload the canonical protobuf wire form; JSON
+		// below keeps older agents syncing.
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unreadable body")
+			return
+		}
+		var pb iloadpb.AlertBatch
+		if err := proto.Unmarshal(body, &pb); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid protobuf body")
+			return
+		}
+		batch = make([]datastore.Alert, len(pb.Alerts))
+		for i, a := range pb.Alerts {
+			batch[i] = iloadpb.AlertFromProto(a)
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	for i := range batch {
+		batch[i].ID = 0 // IDs are local to the agent's database
+		if err := s.store.SaveAlert(&batch[i]); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"accepted": len(batch)})
+}
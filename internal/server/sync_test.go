@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	iloadpb "github.com/anodyne74/iload-obd2/proto/iload/v1"
+)
+
+// captureStore records capture registrations in memory; the embedded
+// nil Store panics on anything else. UserCount of zero keeps the test
+// instance in open mode.
+type captureStore struct {
+	datastore.Store
+	recs []datastore.CaptureRecord
+}
+
+func (s *captureStore) UserCount() (int, error) { return 0, nil }
+
+func (s *captureStore) RegisterCapture(c *datastore.CaptureRecord) error {
+	c.ID = int64(len(s.recs) + 1)
+	s.recs = append(s.recs, *c)
+	return nil
+}
+
+func TestSyncCapturesStoresFileUnderCaptureDir(t *testing.T) {
+	store := &captureStore{}
+	srv := New(store)
+	dir := t.TempDir()
+	srv.SetCaptureDir(dir)
+
+	content := []byte(`{"id":"trip"}`)
+	body := fmt.Sprintf(`[{"vin":"KMFWB37HABU123456","path":"/vans/7/../../etc/trip.json","frames":3,"encoding":"json","tags":["trip"],"data":%q}]`,
+		base64.StdEncoding.EncodeToString(content))
+	w := do(t, srv, http.MethodPost, "/api/v1/sync/captures", body, "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("sync captures = %d: %s", w.Code, w.Body)
+	}
+	if len(store.recs) != 1 {
+		t.Fatalf("registered %d captures, want 1", len(store.recs))
+	}
+	rec := store.recs[0]
+	// Only the base name of the agent's path may steer the write.
+	want := filepath.Join(dir, "trip.json")
+	if rec.Path != want {
+		t.Errorf("registered path %q, want %q", rec.Path, want)
+	}
+	if data, err := os.ReadFile(want); err != nil || string(data) != string(content) {
+		t.Errorf("stored file = %q (err %v), want %q", data, err, content)
+	}
+}
+
+func TestSyncCapturesDisabledWithoutDir(t *testing.T) {
+	srv := New(&captureStore{})
+	w := do(t, srv, http.MethodPost, "/api/v1/sync/captures", "[]", "", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("capture sync without a dir = %d, want 404", w.Code)
+	}
+}
+
+// alertStore records saved alerts in memory.
+type alertStore struct {
+	datastore.Store
+	alerts []datastore.Alert
+}
+
+func (s *alertStore) UserCount() (int, error) { return 0, nil }
+
+func (s *alertStore) SaveAlert(a *datastore.Alert) error {
+	s.alerts = append(s.alerts, *a)
+	return nil
+}
+
+func TestSyncAlertsAcceptsProtobuf(t *testing.T) {
+	store := &alertStore{}
+	srv := New(store)
+
+	pb := &iloadpb.AlertBatch{Alerts: []*iloadpb.Alert{
+		iloadpb.AlertToProto(datastore.Alert{
+			ID: 9, VIN: "KMFWB37HABU123456", Type: "overheat",
+			Message: "coolant climbing", Value: 112, Threshold: 105,
+			Timestamp: time.Now().UTC(),
+		}),
+	}}
+	body, err := proto.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sync/alerts", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("protobuf alert sync = %d: %s", w.Code, w.Body)
+	}
+	if len(store.alerts) != 1 || store.alerts[0].Type != "overheat" {
+		t.Fatalf("saved alerts = %+v", store.alerts)
+	}
+	// Agent-local row IDs must not leak into the central store.
+	if store.alerts[0].ID != 0 {
+		t.Errorf("alert kept agent-local ID %d", store.alerts[0].ID)
+	}
+}
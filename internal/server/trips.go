@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// handleTrips searches a vehicle's trips:
+// GET /api/v1/vehicles/{vin}/trips?tag=turbo-issue&from=RFC3339&to=RFC3339.
+func (s *Server) handleTrips(w http.ResponseWriter, r *http.Request, vin string) {
+	tag, from, to, ok := searchParams(w, r, vin, s)
+	if !ok {
+		return
+	}
+	trips, err := s.store.SearchTrips(vin, tag, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vin":   vin,
+		"trips": trips,
+	})
+}
+
+// handleCaptures searches a vehicle's registered capture sessions with
+// the same query parameters as handleTrips.
+func (s *Server) handleCaptures(w http.ResponseWriter, r *http.Request, vin string) {
+	tag, from, to, ok := searchParams(w, r, vin, s)
+	if !ok {
+		return
+	}
+	captures, err := s.store.SearchCaptures(vin, tag, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vin":      vin,
+		"captures": captures,
+	})
+}
+
+// searchParams validates method, access and the shared tag/from/to query
+// parameters; it writes the error response itself when ok is false.
+func searchParams(w http.ResponseWriter, r *http.Request, vin string, s *Server) (tag string, from, to time.Time, ok bool) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if allowed, err := s.canAccess(r, vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !allowed {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	var err error
+	q := r.URL.Query()
+	if raw := q.Get("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			writeError(w, http.StatusBadRequest, "from must be RFC3339")
+			return
+		}
+	}
+	if raw := q.Get("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			writeError(w, http.StatusBadRequest, "to must be RFC3339")
+			return
+		}
+	}
+	return q.Get("tag"), from, to, true
+}
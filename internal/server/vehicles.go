@@ -0,0 +1,170 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/analysis"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// handleVehicle serves /api/v1/vehicles/{vin} and
+// /api/v1/vehicles/{vin}/purge.
+func (s *Server) handleVehicle(w http.ResponseWriter, r *http.Request) {
+	vin := strings.TrimPrefix(r.URL.Path, "/api/v1/vehicles/")
+	if purgeVIN, ok := strings.CutSuffix(vin, "/purge"); ok {
+		s.handlePurgeVehicle(w, r, purgeVIN)
+		return
+	}
+	if rollupVIN, ok := strings.CutSuffix(vin, "/rollups"); ok {
+		s.handleRollups(w, r, rollupVIN)
+		return
+	}
+	if oilVIN, ok := strings.CutSuffix(vin, "/oil-life"); ok {
+		s.handleOilLife(w, r, oilVIN)
+		return
+	}
+	if tripVIN, ok := strings.CutSuffix(vin, "/trips"); ok {
+		s.handleTrips(w, r, tripVIN)
+		return
+	}
+	if capVIN, ok := strings.CutSuffix(vin, "/captures"); ok {
+		s.handleCaptures(w, r, capVIN)
+		return
+	}
+	if vin == "" || strings.Contains(vin, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if ok, err := s.canAccess(r, vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		v, err := s.store.Vehicle(vin)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "no such vehicle")
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	case http.MethodDelete:
+		// Destructive: removing a vehicle requires admin.
+		if !s.requireRole(w, r, datastore.RoleAdmin) {
+			return
+		}
+		if err := s.store.DeleteVehicle(vin); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleRollups serves cached weekly/monthly summaries for a VIN:
+// GET /api/v1/vehicles/{vin}/rollups?period=weekly&limit=12.
+func (s *Server) handleRollups(w http.ResponseWriter, r *http.Request, vin string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if ok, err := s.canAccess(r, vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "weekly"
+	}
+	if period != "weekly" && period != "monthly" {
+		writeError(w, http.StatusBadRequest, "period must be weekly or monthly")
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	rollups, err := s.store.Rollups(vin, period, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vin":     vin,
+		"period":  period,
+		"rollups": rollups,
+	})
+}
+
+// handleOilLife estimates remaining oil life from telemetry since the
+// last service: GET /api/v1/vehicles/{vin}/oil-life?since=RFC3339.
+// since defaults to the vehicle's registration — pass the date of the
+// last oil change once one has happened.
+func (s *Server) handleOilLife(w http.ResponseWriter, r *http.Request, vin string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if ok, err := s.canAccess(r, vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	v, err := s.store.Vehicle(vin)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "no such vehicle")
+		return
+	}
+	since := v.Registered
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		since, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be RFC3339")
+			return
+		}
+	}
+	samples, err := s.store.Telemetry(vin, since, time.Now().UTC())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rep := analysis.AnalyzeOilLife(samples)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vin":   vin,
+		"since": since,
+		"oil":   rep,
+	})
+}
+
+// handlePurgeVehicle removes every record held for a VIN, for privacy
+// requests and vehicle sales. Admin only.
+func (s *Server) handlePurgeVehicle(w http.ResponseWriter, r *http.Request, vin string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, datastore.RoleAdmin) {
+		return
+	}
+	if ok, err := s.canAccess(r, vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !ok {
+		writeError(w, http.StatusForbidden, "no access to vehicle")
+		return
+	}
+	if err := s.store.DeleteAllData(vin); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"purged": true})
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gorilla/websocket"
+)
+
+// Message is the envelope used on the WebSocket stream. Seq increases by
+// one per broadcast so consumers can detect dropped messages after a
+// reconnect or a slow-client disconnect.
+type Message struct {
+	Seq  uint64          `json:"seq"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	// The dashboard may be served from another origin on the LAN.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// hub fans live messages out to connected WebSocket clients. Slow clients
+// are dropped rather than allowed to stall the pipeline.
+type hub struct {
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+	seq     atomic.Uint64
+}
+
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+	cbor bool // client negotiated CBOR framing via ?encoding=cbor
+
+	mu     sync.Mutex
+	cancel func() // stops a running replay, if any
+}
+
+// setReplay installs the cancel func for a new replay, stopping any
+// replay already running on this socket.
+func (c *wsClient) setReplay(cancel func()) {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.cancel = cancel
+	c.mu.Unlock()
+}
+
+func (c *wsClient) stopReplay() {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.mu.Unlock()
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[*wsClient]struct{})}
+}
+
+func (h *hub) add(c *wsClient) {
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *hub) remove(c *wsClient) {
+	h.mu.Lock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+	h.mu.Unlock()
+}
+
+func (h *hub) broadcast(jsonPayload, cborPayload []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		payload := jsonPayload
+		if c.cbor {
+			payload = cborPayload
+		}
+		select {
+		case c.send <- payload:
+		default:
+			// Client can't keep up; drop it.
+			delete(h.clients, c)
+			close(c.send)
+		}
+	}
+}
+
+// Broadcast sends a typed message to every connected WebSocket client,
+// JSON-framed by default and CBOR-framed for clients that asked for it.
+func (s *Server) Broadcast(msgType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("server: marshal broadcast %q: %v", msgType, err)
+		return
+	}
+	msg := Message{Seq: s.hub.seq.Add(1), Type: msgType, Data: raw}
+	jsonPayload, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("server: marshal envelope: %v", err)
+		return
+	}
+	cborPayload, err := cbor.Marshal(msg)
+	if err != nil {
+		log.Printf("server: cbor envelope: %v", err)
+		cborPayload = jsonPayload
+	}
+	s.hub.broadcast(jsonPayload, cborPayload)
+}
+
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("server: ws upgrade: %v", err)
+		return
+	}
+	c := &wsClient{
+		conn: conn,
+		send: make(chan []byte, 64),
+		cbor: r.URL.Query().Get("encoding") == "cbor",
+	}
+	s.hub.add(c)
+	go c.writeLoop()
+	// Read loop: inbound messages are commands (replay, stop); the loop
+	// also notices disconnects.
+	go func() {
+		defer func() {
+			c.stopReplay()
+			s.hub.remove(c)
+		}()
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			s.handleWSCommand(c, r, raw)
+		}
+	}()
+}
+
+func (c *wsClient) writeLoop() {
+	defer c.conn.Close()
+	msgType := websocket.TextMessage
+	if c.cbor {
+		msgType = websocket.BinaryMessage
+	}
+	for payload := range c.send {
+		if err := c.conn.WriteMessage(msgType, payload); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+)
+
+// BLE OBD adapters (OBDLink CX, Vgate iCar Pro BLE) expose an ELM327
+// command stream over a GATT UART service instead of RFCOMM: commands
+// are written to one characteristic and responses arrive as
+// notifications on another, chopped into MTU-sized chunks that need
+// reassembling into the usual '>'-terminated text.
+//
+// Standard Nordic UART Service identifiers; Vgate clones use their own
+// 16-bit UUIDs but the same write/notify shape.
+const (
+	BLEUARTService = "6e400001-b5a3-f393-e0a9-e50e24dcca9e"
+	BLEUARTWrite   = "6e400002-b5a3-f393-e0a9-e50e24dcca9e"
+	BLEUARTNotify  = "6e400003-b5a3-f393-e0a9-e50e24dcca9e"
+)
+
+// GATTLink is an established BLE connection with the UART service
+// discovered and notifications subscribed — the platform-specific part,
+// provided by a BlueZ or Core Bluetooth binding. Writes go to the write
+// characteristic; Notifications delivers each notify chunk until the
+// link closes.
+type GATTLink interface {
+	Write(data []byte) error
+	Notifications() <-chan []byte
+	Close() error
+}
+
+// OpenELMBLE runs the ELM327 command protocol over an established GATT
+// UART link, resetting the adapter like OpenELM.
+func OpenELMBLE(link GATTLink) (*ELMConn, error) {
+	return newELMConn(&blePort{link: link})
+}
+
+// blePort adapts a GATTLink to the elmPort contract, buffering
+// notification chunks so reads see a continuous byte stream.
+type blePort struct {
+	link    GATTLink
+	buf     []byte
+	timeout time.Duration
+}
+
+func (p *blePort) Read(b []byte) (int, error) {
+	if len(p.buf) == 0 {
+		var timer <-chan time.Time
+		if p.timeout > 0 {
+			timer = time.After(p.timeout)
+		}
+		select {
+		case chunk, ok := <-p.link.Notifications():
+			if !ok {
+				return 0, fmt.Errorf("ble: link closed")
+			}
+			p.buf = append(p.buf, chunk...)
+		case <-timer:
+			return 0, nil // same contract as a serial read timeout
+		}
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+func (p *blePort) Write(b []byte) (int, error) {
+	if err := p.link.Write(b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (p *blePort) Close() error                         { return p.link.Close() }
+func (p *blePort) SetReadTimeout(d time.Duration) error { p.timeout = d; return nil }
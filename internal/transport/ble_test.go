@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeGATT answers written ELM commands with canned responses, split
+// into small chunks like a real MTU-limited notify stream.
+type fakeGATT struct {
+	responses map[string]string
+	out       chan []byte
+}
+
+func newFakeGATT(responses map[string]string) *fakeGATT {
+	return &fakeGATT{responses: responses, out: make(chan []byte, 32)}
+}
+
+func (g *fakeGATT) Write(data []byte) error {
+	cmd := strings.TrimSuffix(string(data), "\r")
+	resp, ok := g.responses[cmd]
+	if !ok {
+		resp = "?\r>"
+	}
+	for len(resp) > 0 {
+		n := 5
+		if n > len(resp) {
+			n = len(resp)
+		}
+		g.out <- []byte(resp[:n])
+		resp = resp[n:]
+	}
+	return nil
+}
+
+func (g *fakeGATT) Notifications() <-chan []byte { return g.out }
+func (g *fakeGATT) Close() error                 { close(g.out); return nil }
+
+func TestOpenELMBLE(t *testing.T) {
+	gatt := newFakeGATT(map[string]string{
+		"ATZ":  "ELM327 v1.5\r>",
+		"ATE0": "OK\r>",
+		"010C": "41 0C 1A F8\r>",
+	})
+	conn, err := OpenELMBLE(gatt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	resp, err := conn.Command("010C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != "41 0C 1A F8" {
+		t.Errorf("response = %q", resp)
+	}
+}
+
+func TestBLERejectedCommand(t *testing.T) {
+	gatt := newFakeGATT(map[string]string{
+		"ATZ":  "ELM327 v1.5\r>",
+		"ATE0": "OK\r>",
+	})
+	conn, err := OpenELMBLE(gatt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Command("ATXYZ"); err == nil {
+		t.Error("rejected command returned no error")
+	}
+}
@@ -0,0 +1,87 @@
+//go:build linux
+
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultRFCOMMChannel is where ELM327 dongles expose their serial
+// profile; the cheap ones never move it.
+const DefaultRFCOMMChannel = 1
+
+// OpenELMBluetooth connects to a Bluetooth ELM327 over RFCOMM and
+// resets it like OpenELM. The dongle must already be paired at the OS
+// level (bluetoothctl handles that once); this only dials. Channel 0
+// uses the default.
+func OpenELMBluetooth(mac string, channel int) (*ELMConn, error) {
+	addr, err := parseBTAddr(mac)
+	if err != nil {
+		return nil, err
+	}
+	if channel == 0 {
+		channel = DefaultRFCOMMChannel
+	}
+	fd, err := unix.Socket(unix.AF_BLUETOOTH, unix.SOCK_STREAM, unix.BTPROTO_RFCOMM)
+	if err != nil {
+		return nil, fmt.Errorf("bluetooth: socket: %w", err)
+	}
+	sa := &unix.SockaddrRFCOMM{Addr: addr, Channel: uint8(channel)}
+	if err := unix.Connect(fd, sa); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("bluetooth: connect %s channel %d: %w", mac, channel, err)
+	}
+	// Non-blocking so the os.File joins the runtime poller and read
+	// deadlines work.
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "rfcomm:"+mac)
+	return newELMConn(&rfcommPort{f: f})
+}
+
+// rfcommPort adapts an RFCOMM socket to the elmPort contract: timed-out
+// reads report n == 0 rather than an error, like a serial port.
+type rfcommPort struct {
+	f       *os.File
+	timeout time.Duration
+}
+
+func (p *rfcommPort) Read(b []byte) (int, error) {
+	if p.timeout > 0 {
+		if err := p.f.SetReadDeadline(time.Now().Add(p.timeout)); err != nil {
+			return 0, err
+		}
+	}
+	n, err := p.f.Read(b)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (p *rfcommPort) Write(b []byte) (int, error)          { return p.f.Write(b) }
+func (p *rfcommPort) Close() error                         { return p.f.Close() }
+func (p *rfcommPort) SetReadTimeout(d time.Duration) error { p.timeout = d; return nil }
+
+// parseBTAddr parses "AA:BB:CC:DD:EE:FF" into the reversed byte order
+// RFCOMM sockaddrs carry.
+func parseBTAddr(mac string) ([6]byte, error) {
+	var addr [6]byte
+	var b [6]int
+	n, err := fmt.Sscanf(mac, "%02x:%02x:%02x:%02x:%02x:%02x",
+		&b[0], &b[1], &b[2], &b[3], &b[4], &b[5])
+	if err != nil || n != 6 {
+		return addr, fmt.Errorf("bluetooth: bad MAC address %q", mac)
+	}
+	for i, v := range b {
+		addr[5-i] = byte(v)
+	}
+	return addr, nil
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package transport
+
+import "testing"
+
+func TestParseBTAddr(t *testing.T) {
+	addr, err := parseBTAddr("AA:BB:CC:DD:EE:FF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sockaddr order is reversed from the printed form.
+	want := [6]byte{0xFF, 0xEE, 0xDD, 0xCC, 0xBB, 0xAA}
+	if addr != want {
+		t.Errorf("addr = %X, want %X", addr, want)
+	}
+	for _, bad := range []string{"", "AA:BB:CC", "not-a-mac", "GG:BB:CC:DD:EE:FF"} {
+		if _, err := parseBTAddr(bad); err == nil {
+			t.Errorf("parseBTAddr(%q) accepted", bad)
+		}
+	}
+}
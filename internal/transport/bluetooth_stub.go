@@ -0,0 +1,11 @@
+//go:build !linux
+
+package transport
+
+import "fmt"
+
+// OpenELMBluetooth needs the Linux RFCOMM socket family; on other
+// platforms pair the dongle as a virtual serial port and use OpenELM.
+func OpenELMBluetooth(mac string, channel int) (*ELMConn, error) {
+	return nil, fmt.Errorf("bluetooth: RFCOMM not supported on this platform")
+}
@@ -0,0 +1,175 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BusState is the CAN controller fault-confinement state.
+type BusState string
+
+const (
+	BusErrorActive  BusState = "error-active"
+	BusErrorWarning BusState = "error-warning"
+	BusErrorPassive BusState = "error-passive"
+	BusOff          BusState = "bus-off"
+	BusStopped      BusState = "stopped"
+	BusUnknown      BusState = "unknown"
+)
+
+// Healthy reports whether the bus is in a usable state.
+func (s BusState) Healthy() bool {
+	return s == BusErrorActive || s == BusErrorWarning
+}
+
+// BusHealth is a snapshot of the monitor's view of the interface.
+type BusHealth struct {
+	Interface string   `json:"interface"`
+	State     BusState `json:"state"`
+	Restarts  int      `json:"restarts"`
+	// TXErrCounter and RXErrCounter are the controller's fault-confinement
+	// error counters; climbing values mean wiring or termination trouble
+	// even before the state leaves error-active.
+	TXErrCounter int       `json:"tx_err_counter"`
+	RXErrCounter int       `json:"rx_err_counter"`
+	LastChange   time.Time `json:"last_change"`
+	LastChecked  time.Time `json:"last_checked"`
+}
+
+// BusMonitor polls the controller state of a SocketCAN interface,
+// restarts it when it goes bus-off and reports unhealthy states, so a
+// wiring or termination fault shows up as an alert instead of a silent
+// empty frame channel.
+type BusMonitor struct {
+	iface    string
+	interval time.Duration
+	// OnUnhealthy is called once per transition into an unhealthy state.
+	OnUnhealthy func(BusHealth)
+
+	mu     sync.Mutex
+	health BusHealth
+}
+
+// NewBusMonitor watches iface, polling at interval (default 5s).
+func NewBusMonitor(iface string, interval time.Duration) *BusMonitor {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &BusMonitor{
+		iface:    iface,
+		interval: interval,
+		health:   BusHealth{Interface: iface, State: BusUnknown},
+	}
+}
+
+// Health returns the current snapshot.
+func (m *BusMonitor) Health() BusHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.health
+}
+
+// Run polls until ctx is cancelled.
+func (m *BusMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+var (
+	canStateRe = regexp.MustCompile(`can state ([A-Z-]+)`)
+	berrRe     = regexp.MustCompile(`berr-counter tx (\d+) rx (\d+)`)
+)
+
+// queryState asks the kernel for the controller state and error counters
+// via iproute2; the netlink attributes aren't exposed in sysfs on the
+// kernels the Pi images ship.
+func (m *BusMonitor) queryState(ctx context.Context) (BusState, int, int) {
+	out, err := exec.CommandContext(ctx, "ip", "-details", "link", "show", m.iface).Output()
+	if err != nil {
+		return BusUnknown, 0, 0
+	}
+	txErr, rxErr := 0, 0
+	if berr := berrRe.FindSubmatch(out); berr != nil {
+		txErr, _ = strconv.Atoi(string(berr[1]))
+		rxErr, _ = strconv.Atoi(string(berr[2]))
+	}
+	return parseState(out), txErr, rxErr
+}
+
+func parseState(out []byte) BusState {
+	match := canStateRe.FindSubmatch(out)
+	if match == nil {
+		return BusUnknown
+	}
+	switch string(match[1]) {
+	case "ERROR-ACTIVE":
+		return BusErrorActive
+	case "ERROR-WARNING":
+		return BusErrorWarning
+	case "ERROR-PASSIVE":
+		return BusErrorPassive
+	case "BUS-OFF":
+		return BusOff
+	case "STOPPED":
+		return BusStopped
+	}
+	return BusUnknown
+}
+
+func (m *BusMonitor) check(ctx context.Context) {
+	state, txErr, rxErr := m.queryState(ctx)
+	now := time.Now()
+
+	m.mu.Lock()
+	prev := m.health.State
+	m.health.LastChecked = now
+	m.health.TXErrCounter = txErr
+	m.health.RXErrCounter = rxErr
+	if state != prev {
+		m.health.State = state
+		m.health.LastChange = now
+	}
+	snapshot := m.health
+	m.mu.Unlock()
+
+	if state != prev && !state.Healthy() && state != BusUnknown {
+		log.Printf("transport: %s entered %s", m.iface, state)
+		if m.OnUnhealthy != nil {
+			m.OnUnhealthy(snapshot)
+		}
+	}
+	if state == BusOff {
+		if err := m.restart(ctx); err != nil {
+			log.Printf("transport: restart %s: %v", m.iface, err)
+		} else {
+			m.mu.Lock()
+			m.health.Restarts++
+			m.mu.Unlock()
+			log.Printf("transport: restarted %s after bus-off", m.iface)
+		}
+	}
+}
+
+// restart kicks the controller out of bus-off.
+func (m *BusMonitor) restart(ctx context.Context) error {
+	out, err := exec.CommandContext(ctx, "ip", "link", "set", m.iface, "type", "can", "restart").
+		CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
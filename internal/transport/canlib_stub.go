@@ -0,0 +1,37 @@
+//go:build !windows
+
+package transport
+
+import (
+	"fmt"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// PCAN is only reachable on Windows through PCANBasic.dll; on Linux the
+// PEAK driver registers a SocketCAN interface instead.
+type PCAN struct{}
+
+// OpenPCAN fails off Windows: use the SocketCAN interface the driver
+// provides there.
+func OpenPCAN(channel string, bitrate int) (*PCAN, error) {
+	return nil, fmt.Errorf("pcan: only supported on Windows; use socketcan")
+}
+
+func (p *PCAN) Send(f capture.CANFrame) error   { return fmt.Errorf("pcan: not supported") }
+func (p *PCAN) Frames() <-chan capture.CANFrame { return nil }
+func (p *PCAN) Close() error                    { return nil }
+
+// Kvaser is only reachable on Windows through canlib32.dll; on Linux
+// Kvaser's driver also registers a SocketCAN interface.
+type Kvaser struct{}
+
+// OpenKvaser fails off Windows: use the SocketCAN interface the driver
+// provides there.
+func OpenKvaser(channel string, bitrate int) (*Kvaser, error) {
+	return nil, fmt.Errorf("kvaser: only supported on Windows; use socketcan")
+}
+
+func (k *Kvaser) Send(f capture.CANFrame) error   { return fmt.Errorf("kvaser: not supported") }
+func (k *Kvaser) Frames() <-chan capture.CANFrame { return nil }
+func (k *Kvaser) Close() error                    { return nil }
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DiscoveredAdapter is one adapter found by Discover, carrying exactly
+// what a transports: config entry needs.
+type DiscoveredAdapter struct {
+	// Type is the transport type for config ("elm" or "udp").
+	Type string `json:"type"`
+	// Address is the serial device or host:port.
+	Address string `json:"address"`
+	// Banner is the adapter's reset banner — the same identity string
+	// ATI reports — e.g. "ELM327 v1.5".
+	Banner string `json:"banner"`
+	// Version is the firmware version parsed from the banner.
+	Version string `json:"version,omitempty"`
+	// Baud is the serial rate the adapter answered at; zero for network
+	// adapters.
+	Baud int `json:"baud,omitempty"`
+}
+
+// wifiProbeAddrs are the endpoints WiFi adapters conventionally serve
+// on; probing them is cheap and catches the common dongles without
+// configuration.
+var wifiProbeAddrs = []string{"192.168.0.10:35000"}
+
+// wifiProbeTimeout bounds one UDP banner probe: short, because the
+// usual outcome off the adapter's network is silence.
+const wifiProbeTimeout = time.Second
+
+// Discover scans for adapters: every enumerated serial port is probed
+// with ATZ at the common baud rates, then the well-known WiFi endpoints
+// are tried over UDP. Bluetooth dongles pair as serial ports at the OS
+// level, so they surface through the port scan. Ports that answer
+// nothing are skipped silently — discovery is best effort.
+func Discover() ([]DiscoveredAdapter, error) {
+	ports, err := EnumeratePorts()
+	if err != nil {
+		return nil, err
+	}
+	var found []DiscoveredAdapter
+	for _, port := range ports {
+		banner, baud, err := IdentifyELM327(port)
+		if err != nil {
+			continue
+		}
+		found = append(found, DiscoveredAdapter{
+			Type:    "elm",
+			Address: port,
+			Banner:  banner,
+			Version: elmVersion(banner),
+			Baud:    baud,
+		})
+	}
+	for _, addr := range wifiProbeAddrs {
+		banner, err := probeELMUDP(addr)
+		if err != nil {
+			continue
+		}
+		found = append(found, DiscoveredAdapter{
+			Type:    "udp",
+			Address: addr,
+			Banner:  banner,
+			Version: elmVersion(banner),
+		})
+	}
+	return found, nil
+}
+
+// probeELMUDP sends ATZ to a network adapter and returns its banner.
+func probeELMUDP(addr string) (string, error) {
+	conn, err := net.DialTimeout("udp", addr, wifiProbeTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ATZ\r")); err != nil {
+		return "", err
+	}
+	conn.SetReadDeadline(time.Now().Add(wifiProbeTimeout))
+	var resp strings.Builder
+	buf := make([]byte, 256)
+	for !strings.Contains(resp.String(), ">") {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		resp.Write(buf[:n])
+	}
+	for _, line := range strings.Split(resp.String(), "\r") {
+		if strings.Contains(line, "ELM327") {
+			return strings.TrimSpace(line), nil
+		}
+	}
+	return "", fmt.Errorf("no ELM327 banner from %s", addr)
+}
+
+// elmVersion pulls the firmware version out of a reset banner:
+// "ELM327 v1.5" → "v1.5".
+func elmVersion(banner string) string {
+	for _, field := range strings.Fields(banner) {
+		if len(field) > 1 && field[0] == 'v' {
+			return field
+		}
+	}
+	return ""
+}
@@ -0,0 +1,16 @@
+package transport
+
+import "testing"
+
+func TestELMVersion(t *testing.T) {
+	for banner, want := range map[string]string{
+		"ELM327 v1.5":    "v1.5",
+		"ELM327 v2.1":    "v2.1",
+		"ELM327":         "",
+		"OBDII to RS232": "",
+	} {
+		if got := elmVersion(banner); got != want {
+			t.Errorf("elmVersion(%q) = %q, want %q", banner, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// elmCmdTimeout bounds one command-mode round trip. Bus requests can
+// take a few seconds when an ECU is slow to wake, well beyond the probe
+// timeout.
+const elmCmdTimeout = 5 * time.Second
+
+// elmReadSlice is the per-read timeout while waiting for the prompt —
+// short enough that cancellation is noticed promptly, with the command
+// timeout enforced across reads.
+const elmReadSlice = 200 * time.Millisecond
+
+// elmPort is the byte link under an ELMConn — a serial port or an
+// RFCOMM socket. SetReadTimeout applies per read; a read that times out
+// returns n == 0 with a nil error, the serial package's contract.
+type elmPort interface {
+	io.ReadWriteCloser
+	SetReadTimeout(time.Duration) error
+}
+
+// ELMConn is an open ELM327 adapter in command mode: AT commands and
+// hex OBD requests in, response text out. This is the lightweight path
+// for one-shot CLI diagnostics; the streaming transport builds on the
+// same adapter separately.
+type ELMConn struct {
+	p elmPort
+}
+
+// OpenELM opens the adapter at the given rate with default framing
+// (8N1) and resets it to a known state with echo off. Clone adapters
+// needing non-default framing go through OpenELMSerial.
+func OpenELM(port string, baud int) (*ELMConn, error) {
+	return OpenELMSerial(port, SerialConfig{Baud: baud})
+}
+
+// newELMConn resets an adapter on an already-open link to a known state
+// with echo off.
+func newELMConn(p elmPort) (*ELMConn, error) {
+	if err := p.SetReadTimeout(elmReadSlice); err != nil {
+		p.Close()
+		return nil, err
+	}
+	c := &ELMConn{p: p}
+	for _, cmd := range []string{"ATZ", "ATE0"} {
+		if _, err := c.Command(cmd); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Command sends one command and returns the adapter's response up to
+// the '>' prompt, trimmed. A "?" response — the adapter didn't
+// understand — is an error.
+func (c *ELMConn) Command(cmd string) (string, error) {
+	return c.command(context.Background(), cmd, elmCmdTimeout)
+}
+
+// CommandContext is Command bounded by ctx: cancellation or an earlier
+// deadline aborts the wait for the prompt.
+func (c *ELMConn) CommandContext(ctx context.Context, cmd string) (string, error) {
+	return c.command(ctx, cmd, elmCmdTimeout)
+}
+
+func (c *ELMConn) command(ctx context.Context, cmd string, timeout time.Duration) (string, error) {
+	if dl, ok := ctx.Deadline(); ok {
+		if until := time.Until(dl); until < timeout {
+			timeout = until
+		}
+	}
+	if _, err := c.p.Write([]byte(cmd + "\r")); err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(timeout)
+	var resp strings.Builder
+	buf := make([]byte, 64)
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := c.p.Read(buf)
+		if err != nil {
+			return "", err
+		}
+		if n == 0 {
+			continue // read slice expired; keep waiting for the prompt
+		}
+		resp.Write(buf[:n])
+		if strings.Contains(resp.String(), ">") {
+			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(resp.String()), ">"))
+			if text == "?" {
+				return "", fmt.Errorf("elm: adapter rejected %q", cmd)
+			}
+			return text, nil
+		}
+	}
+	return "", fmt.Errorf("elm: no prompt after %q within %s", cmd, timeout)
+}
+
+// Close releases the serial port.
+func (c *ELMConn) Close() error { return c.p.Close() }
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ELM327 protocol numbers (the ATSP argument) for the buses an iLoad
+// generation may speak: post-2008 vans are 11-bit CAN at 500k, earlier
+// models answer on K-line only — KWP2000 or ISO 9141-2.
+const (
+	ProtoAuto       = "0"
+	ProtoISO9141    = "3" // ISO 9141-2, 5-baud init
+	ProtoKWP2000    = "4" // ISO 14230-4 KWP, 5-baud (slow) init
+	ProtoKWPFast    = "5" // ISO 14230-4 KWP, fast init
+	ProtoCAN11At500 = "6" // ISO 15765-4 CAN, 11-bit 500 kbit
+)
+
+// protoSearchTimeout bounds an ATSP0 protocol search. The adapter
+// walks every protocol it knows, and each K-line 5-baud init alone
+// takes close to three seconds.
+const protoSearchTimeout = 30 * time.Second
+
+// protocolNames maps ATDPN digits to readable names.
+var protocolNames = map[string]string{
+	"1": "SAE J1850 PWM",
+	"2": "SAE J1850 VPW",
+	"3": "ISO 9141-2",
+	"4": "KWP2000 (5-baud init)",
+	"5": "KWP2000 (fast init)",
+	"6": "CAN 11-bit / 500k",
+	"7": "CAN 29-bit / 500k",
+	"8": "CAN 11-bit / 250k",
+	"9": "CAN 29-bit / 250k",
+}
+
+// ProtocolName renders an ELM protocol number for display.
+func ProtocolName(n string) string {
+	if name, ok := protocolNames[n]; ok {
+		return name
+	}
+	return "protocol " + n
+}
+
+// SetProtocol pins the adapter to one protocol; the next bus request
+// initialises it (including the K-line slow init where that applies).
+func (c *ELMConn) SetProtocol(proto string) error {
+	resp, err := c.Command("ATSP" + proto)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(resp, "OK") {
+		return fmt.Errorf("elm: protocol select refused: %s", resp)
+	}
+	return nil
+}
+
+// DetectProtocol lets the adapter search for whatever the vehicle
+// speaks and returns the protocol number it settled on. The probe
+// request initialises the bus, so this can take tens of seconds on
+// K-line vehicles.
+func (c *ELMConn) DetectProtocol() (string, error) {
+	if err := c.SetProtocol(ProtoAuto); err != nil {
+		return "", err
+	}
+	resp, err := c.command(context.Background(), "0100", protoSearchTimeout)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(resp, "UNABLE TO CONNECT") || strings.Contains(resp, "NO DATA") {
+		return "", fmt.Errorf("elm: no vehicle answered on any protocol")
+	}
+	num, err := c.Command("ATDPN")
+	if err != nil {
+		return "", err
+	}
+	// An 'A' prefix marks an auto-detected protocol.
+	return strings.TrimPrefix(strings.TrimSpace(num), "A"), nil
+}
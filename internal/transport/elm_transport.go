@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// ELMTransport runs an ELM327 adapter as a frame-level Transport, so
+// the uds and obd clients drive it exactly like a SocketCAN interface
+// and it can sit alongside one in a Mux. CAN auto-formatting is turned
+// off: the adapter transmits the bytes it is given and prints received
+// frames with headers, which the read loop parses back into CANFrames.
+// 11-bit addressing only — the dongles this path exists for don't do
+// 29-bit raw mode well.
+type ELMTransport struct {
+	p    elmPort
+	name string
+
+	mu         sync.Mutex
+	lastHeader uint32
+
+	out  chan capture.CANFrame
+	done chan struct{}
+	once sync.Once
+}
+
+// elmTransportInit puts an initialised adapter into raw frame mode:
+// auto-formatting off, headers and spaces on in responses, long
+// messages allowed.
+var elmTransportInit = []string{"ATCAF0", "ATH1", "ATS1", "ATAL"}
+
+// NewELMTransport switches an open adapter into raw frame mode and
+// starts the read loop. The ELMConn must not be used afterwards — the
+// transport owns the byte stream.
+func NewELMTransport(conn *ELMConn, name string) (*ELMTransport, error) {
+	for _, cmd := range elmTransportInit {
+		if _, err := conn.Command(cmd); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("elm: init %s: %w", cmd, err)
+		}
+	}
+	t := &ELMTransport{
+		p:    conn.p,
+		name: name,
+		out:  make(chan capture.CANFrame, 64),
+		done: make(chan struct{}),
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+// Send transmits one frame, re-addressing the adapter when the
+// arbitration ID changes. Adapter chatter in response ("OK", the
+// prompt) is discarded by the read loop.
+func (t *ELMTransport) Send(f capture.CANFrame) error {
+	id := f.Arbitration()
+	if id > 0x7FF {
+		return fmt.Errorf("elm: 29-bit transmit not supported")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id != t.lastHeader {
+		if _, err := t.p.Write([]byte(fmt.Sprintf("ATSH%03X\r", id))); err != nil {
+			return err
+		}
+		t.lastHeader = id
+	}
+	var sb strings.Builder
+	for _, b := range f.Payload() {
+		fmt.Fprintf(&sb, "%02X", b)
+	}
+	sb.WriteByte('\r')
+	_, err := t.p.Write([]byte(sb.String()))
+	return err
+}
+
+// SetFilters applies an acceptance filter with ATCF/ATCM. The ELM327
+// has a single filter/mask pair in hardware, so at most one filter is
+// accepted; an empty slice restores accept-everything.
+func (t *ELMTransport) SetFilters(filters []Filter) error {
+	if len(filters) > 1 {
+		return fmt.Errorf("elm: adapter supports a single filter/mask pair, got %d", len(filters))
+	}
+	f := Filter{} // mask 0 matches everything
+	if len(filters) == 1 {
+		f = filters[0]
+		if f.ID > standardFrameMax || f.Mask > standardFrameMax {
+			return fmt.Errorf("elm: 29-bit filters not supported")
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, cmd := range []string{
+		fmt.Sprintf("ATCF%03X", f.ID),
+		fmt.Sprintf("ATCM%03X", f.Mask),
+	} {
+		if _, err := t.p.Write([]byte(cmd + "\r")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Frames delivers received frames until the transport closes.
+func (t *ELMTransport) Frames() <-chan capture.CANFrame { return t.out }
+
+func (t *ELMTransport) Close() error {
+	t.once.Do(func() { close(t.done) })
+	return t.p.Close()
+}
+
+// readLoop turns the adapter's response text back into frames, line by
+// line. Anything that isn't a headers-on frame line — prompts, "OK",
+// "SEARCHING..." — is dropped.
+func (t *ELMTransport) readLoop() {
+	defer close(t.out)
+	var pending []byte
+	buf := make([]byte, 64)
+	for {
+		select {
+		case <-t.done:
+			return
+		default:
+		}
+		n, err := t.p.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			continue // read slice expired
+		}
+		pending = append(pending, buf[:n]...)
+		for {
+			i := strings.IndexAny(string(pending), "\r>")
+			if i < 0 {
+				break
+			}
+			line := string(pending[:i])
+			pending = pending[i+1:]
+			if f, ok := parseELMFrame(line); ok {
+				f.Bus = t.name
+				f.Timestamp = clock.Now().UnixNano()
+				select {
+				case t.out <- f:
+				case <-t.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// parseELMFrame parses one headers-on response line, e.g.
+// "7E8 03 41 05 5A", into a frame. Lines that aren't frames return
+// ok == false.
+func parseELMFrame(line string) (capture.CANFrame, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return capture.CANFrame{}, false
+	}
+	idField := fields[0]
+	if len(idField) != 3 && len(idField) != 8 {
+		return capture.CANFrame{}, false
+	}
+	id, err := strconv.ParseUint(idField, 16, 32)
+	if err != nil {
+		return capture.CANFrame{}, false
+	}
+	f := capture.CANFrame{ID: uint32(id)}
+	if len(idField) == 8 {
+		f.ID |= capture.EFFFlag
+	}
+	for _, field := range fields[1:] {
+		if len(field) != 2 {
+			return capture.CANFrame{}, false
+		}
+		b, err := strconv.ParseUint(field, 16, 8)
+		if err != nil {
+			return capture.CANFrame{}, false
+		}
+		if f.Length >= 8 {
+			return capture.CANFrame{}, false
+		}
+		f.Data[f.Length] = byte(b)
+		f.Length++
+	}
+	return f, true
+}
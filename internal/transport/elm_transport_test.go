@@ -0,0 +1,93 @@
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func newTestELMTransport(t *testing.T, responses map[string]string) *ELMTransport {
+	t.Helper()
+	base := map[string]string{
+		"ATZ":  "ELM327 v1.5\r>",
+		"ATE0": "OK\r>",
+	}
+	for _, cmd := range elmTransportInit {
+		base[cmd] = "OK\r>"
+	}
+	for cmd, resp := range responses {
+		base[cmd] = resp
+	}
+	conn, err := OpenELMBLE(newFakeGATT(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewELMTransport(conn, "elm0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tr.Close() })
+	return tr
+}
+
+func TestELMTransportRoundTrip(t *testing.T) {
+	tr := newTestELMTransport(t, map[string]string{
+		"ATSH7DF":          "OK\r>",
+		"0201050000000000": "7E8 03 41 05 7B \r>",
+	})
+	f := capture.CANFrame{ID: 0x7DF, Length: 8}
+	copy(f.Data[:], []byte{0x02, 0x01, 0x05})
+	if err := tr.Send(f); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-tr.Frames():
+		if resp.ID != 0x7E8 || resp.Bus != "elm0" {
+			t.Errorf("frame = %+v", resp)
+		}
+		if resp.Length != 4 || resp.Data[1] != 0x41 || resp.Data[3] != 0x7B {
+			t.Errorf("data = % X (len %d)", resp.Data[:resp.Length], resp.Length)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no response frame")
+	}
+}
+
+func TestELMTransportRejects29Bit(t *testing.T) {
+	tr := newTestELMTransport(t, nil)
+	if err := tr.Send(capture.CANFrame{ID: 0x18DB33F1 | capture.EFFFlag, Length: 8}); err == nil {
+		t.Error("29-bit send accepted")
+	}
+}
+
+func TestParseELMFrame(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		ok   bool
+		id   uint32
+		data []byte
+	}{
+		{"7E8 03 41 05 7B", true, 0x7E8, []byte{0x03, 0x41, 0x05, 0x7B}},
+		{"18DAF110 04 62 F1 90 AA", true, 0x18DAF110 | capture.EFFFlag, []byte{0x04, 0x62, 0xF1, 0x90, 0xAA}},
+		{"SEARCHING...", false, 0, nil},
+		{"OK", false, 0, nil},
+		{"", false, 0, nil},
+		{"7E8 GG", false, 0, nil},
+	} {
+		f, ok := parseELMFrame(tt.line)
+		if ok != tt.ok {
+			t.Errorf("parseELMFrame(%q) ok = %v", tt.line, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if f.ID != tt.id {
+			t.Errorf("parseELMFrame(%q) ID = %X, want %X", tt.line, f.ID, tt.id)
+		}
+		if int(f.Length) != len(tt.data) {
+			t.Errorf("parseELMFrame(%q) length = %d", tt.line, f.Length)
+		}
+	}
+}
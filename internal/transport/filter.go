@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter is one CAN acceptance filter: a frame passes when
+// received ID & Mask == ID & Mask. Pushing filters down to the
+// controller (or the ELM327's ATCF/ATCM) keeps a chatty body bus from
+// flooding the frame channel with traffic nobody reads.
+type Filter struct {
+	ID   uint32
+	Mask uint32
+}
+
+// FilterSetter is implemented by transports that can apply acceptance
+// filters; an empty slice restores accept-everything.
+type FilterSetter interface {
+	SetFilters([]Filter) error
+}
+
+// ParseFilter parses the config form "id/mask" (hex, e.g.
+// "0x7E8/0x7F8"). A bare ID gets an exact-match mask for its width.
+func ParseFilter(s string) (Filter, error) {
+	idStr, maskStr, hasMask := strings.Cut(s, "/")
+	id, err := strconv.ParseUint(strings.TrimSpace(idStr), 0, 32)
+	if err != nil {
+		return Filter{}, fmt.Errorf("transport: bad filter ID in %q: %w", s, err)
+	}
+	f := Filter{ID: uint32(id)}
+	if hasMask {
+		mask, err := strconv.ParseUint(strings.TrimSpace(maskStr), 0, 32)
+		if err != nil {
+			return Filter{}, fmt.Errorf("transport: bad filter mask in %q: %w", s, err)
+		}
+		f.Mask = uint32(mask)
+		return f, nil
+	}
+	if f.ID > standardFrameMax {
+		f.Mask = 0x1FFFFFFF
+	} else {
+		f.Mask = 0x7FF
+	}
+	return f, nil
+}
+
+// standardFrameMax is the largest 11-bit identifier.
+const standardFrameMax = 0x7FF
+
+// ParseFilters parses a config filter list.
+func ParseFilters(specs []string) ([]Filter, error) {
+	var filters []Filter
+	for _, s := range specs {
+		f, err := ParseFilter(s)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// ApplyFilterSpecs parses config filter specs and pushes them down to
+// the transport. Transports without filter support reject a non-empty
+// list rather than silently delivering everything.
+func ApplyFilterSpecs(t Transport, specs []string) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	filters, err := ParseFilters(specs)
+	if err != nil {
+		return err
+	}
+	fs, ok := t.(FilterSetter)
+	if !ok {
+		return fmt.Errorf("transport: %T does not support acceptance filters", t)
+	}
+	return fs.SetFilters(filters)
+}
+
+// String renders the filter back in config form.
+func (f Filter) String() string {
+	if f.ID > standardFrameMax || f.Mask > standardFrameMax {
+		return fmt.Sprintf("0x%08X/0x%08X", f.ID, f.Mask)
+	}
+	return fmt.Sprintf("0x%03X/0x%03X", f.ID, f.Mask)
+}
@@ -0,0 +1,43 @@
+package transport
+
+import "testing"
+
+func TestParseFilter(t *testing.T) {
+	cases := []struct {
+		spec string
+		want Filter
+	}{
+		{"0x7E8/0x7F8", Filter{ID: 0x7E8, Mask: 0x7F8}},
+		{"0x7E8", Filter{ID: 0x7E8, Mask: 0x7FF}},
+		{"0x18DAF110", Filter{ID: 0x18DAF110, Mask: 0x1FFFFFFF}},
+	}
+	for _, tc := range cases {
+		f, err := ParseFilter(tc.spec)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", tc.spec, err)
+		}
+		if f != tc.want {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tc.spec, f, tc.want)
+		}
+	}
+	if _, err := ParseFilter("7E8/7F8"); err == nil {
+		t.Error("ParseFilter accepted hex without 0x prefix")
+	}
+	if _, err := ParseFilter("0x7E8/xyz"); err == nil {
+		t.Error("ParseFilter accepted a bad mask")
+	}
+}
+
+func TestFilterString(t *testing.T) {
+	for _, tc := range []struct {
+		f    Filter
+		want string
+	}{
+		{Filter{ID: 0x7E8, Mask: 0x7F8}, "0x7E8/0x7F8"},
+		{Filter{ID: 0x18DAF110, Mask: 0x1FFFFFFF}, "0x18DAF110/0x1FFFFFFF"},
+	} {
+		if got := tc.f.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q, want %q", tc.f, got, tc.want)
+		}
+	}
+}
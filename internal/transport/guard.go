@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// ErrReadOnly is returned for any transmit attempt while the global
+// read-only mode is active.
+var ErrReadOnly = errors.New("transport: read-only mode, transmit refused")
+
+// TXPolicy controls which CAN IDs this daemon may transmit. The zero
+// value permits nothing until IDs are whitelisted, because accidentally
+// disturbing a vehicle bus is worse than a failed diagnostic request.
+type TXPolicy struct {
+	// ReadOnly refuses every transmit regardless of the lists.
+	ReadOnly bool
+	// Allow lists the only IDs that may be sent. Empty means no
+	// transmit is permitted (use Deny-only mode by listing everything
+	// is deliberately not supported).
+	Allow []uint32
+	// Deny lists IDs refused even if present in Allow.
+	Deny []uint32
+}
+
+// BlockedError reports a transmit refused by policy.
+type BlockedError struct {
+	ID uint32
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("transport: transmit of 0x%03X blocked by policy", e.ID)
+}
+
+// Check returns nil if the policy permits transmitting the given ID.
+func (p TXPolicy) Check(id uint32) error {
+	if p.ReadOnly {
+		return ErrReadOnly
+	}
+	for _, d := range p.Deny {
+		if d == id {
+			return &BlockedError{ID: id}
+		}
+	}
+	for _, a := range p.Allow {
+		if a == id {
+			return nil
+		}
+	}
+	return &BlockedError{ID: id}
+}
+
+// ParsePolicy builds a TXPolicy from the hex ID lists used in config.
+func ParsePolicy(readOnly bool, allow, deny []string) (TXPolicy, error) {
+	p := TXPolicy{ReadOnly: readOnly}
+	parse := func(list []string) ([]uint32, error) {
+		var out []uint32
+		for _, s := range list {
+			id, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(s), "0x"), 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("transport: bad CAN ID %q in policy", s)
+			}
+			out = append(out, uint32(id))
+		}
+		return out, nil
+	}
+	var err error
+	if p.Allow, err = parse(allow); err != nil {
+		return p, err
+	}
+	if p.Deny, err = parse(deny); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// guarded enforces a TXPolicy in front of a Transport. Every code path
+// that can publish onto a real bus must go through Guard rather than
+// holding the raw transport.
+type guarded struct {
+	Transport
+	policy TXPolicy
+}
+
+// Guard wraps t so transmits are checked against policy.
+func Guard(t Transport, policy TXPolicy) Transport {
+	return &guarded{Transport: t, policy: policy}
+}
+
+func (g *guarded) Send(f capture.CANFrame) error {
+	if err := g.policy.Check(f.ID); err != nil {
+		return err
+	}
+	return g.Transport.Send(f)
+}
@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+type recordingTransport struct {
+	sent []capture.CANFrame
+	ch   chan capture.CANFrame
+}
+
+func (r *recordingTransport) Send(f capture.CANFrame) error {
+	r.sent = append(r.sent, f)
+	return nil
+}
+func (r *recordingTransport) Frames() <-chan capture.CANFrame { return r.ch }
+func (r *recordingTransport) Close() error                    { return nil }
+
+func TestGuardEnforcesWhitelist(t *testing.T) {
+	raw := &recordingTransport{}
+	g := Guard(raw, TXPolicy{Allow: []uint32{0x7DF}})
+
+	if err := g.Send(capture.CANFrame{ID: 0x7DF}); err != nil {
+		t.Fatalf("whitelisted send failed: %v", err)
+	}
+	err := g.Send(capture.CANFrame{ID: 0x316})
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("unlisted send returned %v, want BlockedError", err)
+	}
+	if len(raw.sent) != 1 {
+		t.Fatalf("%d frames reached the bus, want 1", len(raw.sent))
+	}
+}
+
+func TestGuardReadOnly(t *testing.T) {
+	raw := &recordingTransport{}
+	g := Guard(raw, TXPolicy{ReadOnly: true, Allow: []uint32{0x7DF}})
+	if err := g.Send(capture.CANFrame{ID: 0x7DF}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("read-only send returned %v, want ErrReadOnly", err)
+	}
+	if len(raw.sent) != 0 {
+		t.Fatal("frame reached the bus in read-only mode")
+	}
+}
+
+func TestGuardDenyBeatsAllow(t *testing.T) {
+	g := Guard(&recordingTransport{}, TXPolicy{
+		Allow: []uint32{0x7DF},
+		Deny:  []uint32{0x7DF},
+	})
+	if err := g.Send(capture.CANFrame{ID: 0x7DF}); err == nil {
+		t.Fatal("denied ID was sent")
+	}
+}
@@ -0,0 +1,173 @@
+//go:build windows
+
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// Kvaser backend: drives a Kvaser interface through CANlib
+// (canlib32.dll, installed with Kvaser's driver bundle). Like
+// PCAN-Basic the API is polled.
+
+var (
+	canlibDLL       = windows.NewLazyDLL("canlib32.dll")
+	canlibInitLib   = canlibDLL.NewProc("canInitializeLibrary")
+	canlibOpen      = canlibDLL.NewProc("canOpenChannel")
+	canlibBusParams = canlibDLL.NewProc("canSetBusParams")
+	canlibBusOn     = canlibDLL.NewProc("canBusOn")
+	canlibBusOff    = canlibDLL.NewProc("canBusOff")
+	canlibClose     = canlibDLL.NewProc("canClose")
+	canlibRead      = canlibDLL.NewProc("canRead")
+	canlibWrite     = canlibDLL.NewProc("canWrite")
+
+	canlibInitOnce sync.Once
+)
+
+// CANlib predefined bitrate constants (canBITRATE_*).
+var kvaserBitrates = map[int]uintptr{
+	1000000: negint(-1),
+	500000:  negint(-2),
+	250000:  negint(-3),
+	125000:  negint(-4),
+}
+
+func negint(v int32) uintptr { return uintptr(uint32(v)) }
+
+// CANlib status and message flag values used here.
+const (
+	canlibOK     = 0
+	canlibMsgExt = 0x0004
+)
+
+var canlibErrNoMsg = negint(-2)
+
+// Kvaser is an open Kvaser CANlib channel.
+type Kvaser struct {
+	handle uintptr
+	name   string
+	out    chan capture.CANFrame
+	done   chan struct{}
+	once   sync.Once
+}
+
+// OpenKvaser opens CANlib channel n ("0", "1", ...) at the given
+// bitrate (0 means 500k).
+func OpenKvaser(channel string, bitrate int) (*Kvaser, error) {
+	if bitrate == 0 {
+		bitrate = 500000
+	}
+	rate, ok := kvaserBitrates[bitrate]
+	if !ok {
+		return nil, fmt.Errorf("kvaser: unsupported bitrate %d", bitrate)
+	}
+	n, err := strconv.Atoi(channel)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("kvaser: bad channel %q (want a channel number)", channel)
+	}
+	canlibInitOnce.Do(func() { canlibInitLib.Call() })
+	handle, _, _ := canlibOpen.Call(uintptr(n), 0)
+	if int32(handle) < 0 {
+		return nil, fmt.Errorf("kvaser: open channel %d: status %d", n, int32(handle))
+	}
+	if status, _, _ := canlibBusParams.Call(handle, rate, 0, 0, 0, 0, 0); status != canlibOK {
+		canlibClose.Call(handle)
+		return nil, fmt.Errorf("kvaser: set bus params: status %d", int32(status))
+	}
+	if status, _, _ := canlibBusOn.Call(handle); status != canlibOK {
+		canlibClose.Call(handle)
+		return nil, fmt.Errorf("kvaser: bus on: status %d", int32(status))
+	}
+	k := &Kvaser{
+		handle: handle,
+		name:   "kvaser-" + channel,
+		out:    make(chan capture.CANFrame, 256),
+		done:   make(chan struct{}),
+	}
+	go k.readLoop()
+	return k, nil
+}
+
+// Send publishes one frame onto the bus.
+func (k *Kvaser) Send(f capture.CANFrame) error {
+	var flags uintptr
+	if f.IsExtended() {
+		flags = canlibMsgExt
+	}
+	data := f.Payload()
+	status, _, _ := canlibWrite.Call(k.handle, uintptr(f.Arbitration()),
+		uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)), flags)
+	if status != canlibOK {
+		return fmt.Errorf("kvaser: write: status %d", int32(status))
+	}
+	return nil
+}
+
+// Frames delivers received frames until the transport closes.
+func (k *Kvaser) Frames() <-chan capture.CANFrame { return k.out }
+
+func (k *Kvaser) Close() error {
+	k.once.Do(func() {
+		close(k.done)
+		canlibBusOff.Call(k.handle)
+		canlibClose.Call(k.handle)
+	})
+	return nil
+}
+
+func (k *Kvaser) readLoop() {
+	defer close(k.out)
+	var (
+		id    uint32
+		data  [64]byte
+		dlc   uint32
+		flags uint32
+		stamp uint32
+	)
+	for {
+		select {
+		case <-k.done:
+			return
+		default:
+		}
+		status, _, _ := canlibRead.Call(k.handle,
+			uintptr(unsafe.Pointer(&id)), uintptr(unsafe.Pointer(&data[0])),
+			uintptr(unsafe.Pointer(&dlc)), uintptr(unsafe.Pointer(&flags)),
+			uintptr(unsafe.Pointer(&stamp)))
+		if status == canlibErrNoMsg {
+			time.Sleep(2 * time.Millisecond)
+			continue
+		}
+		if status != canlibOK {
+			return
+		}
+		f := capture.CANFrame{
+			ID:        id,
+			Timestamp: clock.Now().UnixNano(),
+			Bus:       k.name,
+		}
+		if flags&canlibMsgExt != 0 {
+			f.ID |= capture.EFFFlag
+		}
+		n := int(dlc)
+		if n > 8 {
+			n = 8
+		}
+		f.Length = uint8(n)
+		copy(f.Data[:], data[:n])
+		select {
+		case k.out <- f:
+		case <-k.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// Mock is a software ECU: a Transport that answers the legacy OBD
+// service set itself, so the whole daemon — poller, server, CLI — runs
+// with no vehicle attached. It speaks ISO-TP at the frame level,
+// answering functional (0x7DF) and engine-physical (0x7E0) requests
+// from the engine slot 0x7E8, including multi-frame responses gated on
+// the client's flow control. Select it with bus type "mock"; the
+// interface field may name a capture session to replay alongside the
+// simulated ECU.
+type Mock struct {
+	mu      sync.Mutex
+	rpm     float64
+	speed   float64 // km/h
+	coolant float64 // °C
+	load    float64 // percent
+	vin     string
+	dtcs    [][2]byte
+
+	out  chan capture.CANFrame
+	tx   [][8]byte // consecutive frames queued behind flow control
+	done chan struct{}
+	once sync.Once
+}
+
+// ISO-TP PCI frame types, mirrored from the uds package — the mock sits
+// below it and can't import it.
+const (
+	mockPCISingle      = 0
+	mockPCIFirst       = 1
+	mockPCIConsecutive = 2
+	mockPCIFlowControl = 3
+)
+
+const mockResponseID = 0x7E8
+
+// NewMock builds a mock transport simulating an idling D4CB with no
+// stored codes.
+func NewMock() *Mock {
+	return &Mock{
+		rpm:     820,
+		coolant: 88,
+		load:    22,
+		vin:     "KMFWB37HXAU123456",
+		out:     make(chan capture.CANFrame, 64),
+		done:    make(chan struct{}),
+	}
+}
+
+// SetLive updates the simulated live values returned by mode 01.
+func (m *Mock) SetLive(rpm, speedKPH, coolantC, loadPct float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rpm, m.speed, m.coolant, m.load = rpm, speedKPH, coolantC, loadPct
+}
+
+// AddDTC stores one raw two-byte trouble code; it appears in mode 03
+// and lights the MIL until a mode 04 clear.
+func (m *Mock) AddDTC(hi, lo byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dtcs = append(m.dtcs, [2]byte{hi, lo})
+}
+
+// Replay streams a recorded session's frames onto the receive channel
+// at their original relative timing, interleaved with the simulated
+// ECU's responses — background traffic for exercising the capture and
+// decode paths offline.
+func (m *Mock) Replay(s *capture.Session) {
+	go func() {
+		start := time.Now()
+		for _, f := range s.Frames {
+			wait := time.Duration(f.Timestamp-s.StartTime) - time.Since(start)
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-m.done:
+					return
+				}
+			}
+			select {
+			case m.out <- f:
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Frames delivers simulated responses and any replayed traffic.
+func (m *Mock) Frames() <-chan capture.CANFrame { return m.out }
+
+func (m *Mock) Close() error {
+	m.once.Do(func() {
+		close(m.done)
+		close(m.out)
+	})
+	return nil
+}
+
+// Send accepts a request frame the way an ECU would: single-frame
+// requests are answered immediately, flow control releases any queued
+// consecutive frames. Frames for other addresses fall through silently,
+// like a bus nobody else is listening on.
+func (m *Mock) Send(f capture.CANFrame) error {
+	id := f.Arbitration()
+	if id != 0x7DF && id != 0x7E0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch f.Data[0] >> 4 {
+	case mockPCISingle:
+		n := int(f.Data[0] & 0x0F)
+		if n == 0 || n > 7 {
+			return nil
+		}
+		m.respond(m.handle(f.Data[1 : 1+n]))
+	case mockPCIFlowControl:
+		for _, fr := range m.tx {
+			m.emit(fr)
+		}
+		m.tx = nil
+	}
+	return nil
+}
+
+// handle answers one reassembled request, returning the response
+// payload or nil for silence.
+func (m *Mock) handle(req []byte) []byte {
+	switch req[0] {
+	case 0x01:
+		if len(req) < 2 {
+			return nil
+		}
+		return m.liveData(req[1])
+	case 0x03:
+		resp := []byte{0x43, byte(len(m.dtcs))}
+		for _, c := range m.dtcs {
+			resp = append(resp, c[0], c[1])
+		}
+		return resp
+	case 0x04:
+		m.dtcs = nil
+		return []byte{0x44}
+	case 0x07:
+		return []byte{0x47, 0x00}
+	case 0x0A:
+		return []byte{0x4A, 0x00}
+	case 0x09:
+		if len(req) < 2 {
+			return nil
+		}
+		switch req[1] {
+		case 0x00:
+			// Only the VIN (PID 02) is available.
+			return []byte{0x49, 0x00, 0x40, 0x00, 0x00, 0x00}
+		case 0x02:
+			resp := []byte{0x49, 0x02, 0x01}
+			return append(resp, m.vin...)
+		}
+	}
+	return []byte{0x7F, req[0], 0x12} // subfunction not supported
+}
+
+// Mode 01 PIDs the mock answers; the support bitmap is derived from
+// this list.
+var mockLivePIDs = []byte{0x01, 0x04, 0x05, 0x0C, 0x0D}
+
+func (m *Mock) liveData(pid byte) []byte {
+	switch pid {
+	case 0x00:
+		var bitmap [4]byte
+		for _, p := range mockLivePIDs {
+			bitmap[(p-1)/8] |= 1 << (7 - (p-1)%8)
+		}
+		return append([]byte{0x41, 0x00}, bitmap[:]...)
+	case 0x01:
+		mil := byte(len(m.dtcs))
+		if mil > 0 {
+			mil |= 0x80
+		}
+		// Continuous monitors complete; diesel engine with the PM
+		// filter and exhaust gas sensor monitors still running.
+		return []byte{0x41, 0x01, mil, 0x0F, 0x6B, 0x60}
+	case 0x04:
+		return []byte{0x41, 0x04, byte(m.load * 255 / 100)}
+	case 0x05:
+		return []byte{0x41, 0x05, byte(m.coolant + 40)}
+	case 0x0C:
+		raw := uint16(m.rpm * 4)
+		return []byte{0x41, 0x0C, byte(raw >> 8), byte(raw)}
+	case 0x0D:
+		return []byte{0x41, 0x0D, byte(m.speed)}
+	}
+	return []byte{0x7F, 0x01, 0x12}
+}
+
+// respond frames one response payload: single-frame when it fits,
+// otherwise a first frame now and consecutive frames queued behind the
+// client's flow control.
+func (m *Mock) respond(resp []byte) {
+	if resp == nil {
+		return
+	}
+	if len(resp) <= 7 {
+		var d [8]byte
+		d[0] = byte(len(resp))
+		copy(d[1:], resp)
+		m.emit(d)
+		return
+	}
+	var first [8]byte
+	first[0] = byte(mockPCIFirst<<4 | len(resp)>>8)
+	first[1] = byte(len(resp))
+	copy(first[2:], resp[:6])
+	m.emit(first)
+	rest := resp[6:]
+	for seq := byte(1); len(rest) > 0; seq = (seq + 1) & 0x0F {
+		var d [8]byte
+		d[0] = mockPCIConsecutive<<4 | seq
+		n := copy(d[1:], rest)
+		rest = rest[n:]
+		m.tx = append(m.tx, d)
+	}
+}
+
+func (m *Mock) emit(d [8]byte) {
+	f := capture.CANFrame{ID: mockResponseID, Length: 8, Data: d, Timestamp: clock.Now().UnixNano(), Bus: "mock"}
+	select {
+	case m.out <- f:
+	case <-m.done:
+	}
+}
+
+// openMock builds a mock transport, replaying the capture session at
+// path when one is given.
+func openMock(path string) (*Mock, error) {
+	m := NewMock()
+	if path != "" {
+		s, err := capture.LoadSession(path)
+		if err != nil {
+			return nil, fmt.Errorf("transport: mock replay: %w", err)
+		}
+		m.Replay(s)
+	}
+	return m, nil
+}
@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// Mux merges several named transports into one, so multi-bus vehicles
+// (powertrain on can0, body on can1) are captured concurrently. Frames
+// from each bus carry its name; Send routes on the frame's Bus field,
+// defaulting to the first bus added.
+type Mux struct {
+	mu     sync.Mutex
+	order  []string
+	buses  map[string]Transport
+	out    chan capture.CANFrame
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewMux returns an empty Mux; add buses before reading Frames.
+func NewMux() *Mux {
+	return &Mux{
+		buses: make(map[string]Transport),
+		out:   make(chan capture.CANFrame, 64),
+	}
+}
+
+// Add attaches a named bus and starts forwarding its frames.
+func (m *Mux) Add(name string, t Transport) {
+	m.mu.Lock()
+	m.order = append(m.order, name)
+	m.buses[name] = t
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for f := range t.Frames() {
+			f.Bus = name
+			m.out <- f
+		}
+	}()
+}
+
+// Send transmits on the bus named in f.Bus, or the first bus when unset.
+func (m *Mux) Send(f capture.CANFrame) error {
+	m.mu.Lock()
+	name := f.Bus
+	if name == "" && len(m.order) > 0 {
+		name = m.order[0]
+	}
+	t, ok := m.buses[name]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("transport: no bus named %q", name)
+	}
+	return t.Send(f)
+}
+
+// Frames returns the merged stream across every bus.
+func (m *Mux) Frames() <-chan capture.CANFrame { return m.out }
+
+// Close closes every bus and, once their streams drain, the merged channel.
+func (m *Mux) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	buses := make([]Transport, 0, len(m.buses))
+	for _, t := range m.buses {
+		buses = append(buses, t)
+	}
+	m.mu.Unlock()
+
+	var first error
+	for _, t := range buses {
+		if err := t.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	go func() {
+		m.wg.Wait()
+		close(m.out)
+	}()
+	return first
+}
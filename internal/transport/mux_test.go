@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func TestMuxTagsAndMerges(t *testing.T) {
+	a := &recordingTransport{ch: make(chan capture.CANFrame, 1)}
+	b := &recordingTransport{ch: make(chan capture.CANFrame, 1)}
+	m := NewMux()
+	m.Add("powertrain", a)
+	m.Add("body", b)
+
+	a.ch <- capture.CANFrame{ID: 0x316}
+	b.ch <- capture.CANFrame{ID: 0x2A0}
+
+	seen := make(map[uint32]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case f := <-m.Frames():
+			seen[f.ID] = f.Bus
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for merged frames")
+		}
+	}
+	if seen[0x316] != "powertrain" || seen[0x2A0] != "body" {
+		t.Fatalf("bus tags = %v", seen)
+	}
+}
+
+func TestMuxSendRoutesByBus(t *testing.T) {
+	a := &recordingTransport{ch: make(chan capture.CANFrame)}
+	b := &recordingTransport{ch: make(chan capture.CANFrame)}
+	m := NewMux()
+	m.Add("powertrain", a)
+	m.Add("body", b)
+
+	if err := m.Send(capture.CANFrame{ID: 0x7DF}); err != nil {
+		t.Fatalf("default-bus send: %v", err)
+	}
+	if err := m.Send(capture.CANFrame{ID: 0x2A0, Bus: "body"}); err != nil {
+		t.Fatalf("named-bus send: %v", err)
+	}
+	if len(a.sent) != 1 || len(b.sent) != 1 {
+		t.Fatalf("routing wrong: %d on powertrain, %d on body", len(a.sent), len(b.sent))
+	}
+	if err := m.Send(capture.CANFrame{Bus: "chassis"}); err == nil {
+		t.Fatal("send to unknown bus succeeded")
+	}
+}
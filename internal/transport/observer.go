@@ -0,0 +1,46 @@
+package transport
+
+import "github.com/anodyne74/iload-obd2/capture"
+
+// ObserveFrames wraps t so fn sees every received frame before it is
+// forwarded on the wrapped transport's Frames channel. Passive
+// consumers — capture rings, the pcap bridge, signal decoders — hook in
+// here instead of draining Frames() themselves, which would race the
+// diagnostic clients for their own responses. fn runs on the forwarding
+// goroutine, so it must not block.
+func ObserveFrames(t Transport, fn func(capture.CANFrame)) Transport {
+	ot := &observedTransport{t: t, fn: fn, out: make(chan capture.CANFrame, 64)}
+	go ot.forward()
+	return ot
+}
+
+type observedTransport struct {
+	t   Transport
+	fn  func(capture.CANFrame)
+	out chan capture.CANFrame
+}
+
+func (o *observedTransport) Send(f capture.CANFrame) error   { return o.t.Send(f) }
+func (o *observedTransport) Frames() <-chan capture.CANFrame { return o.out }
+func (o *observedTransport) Close() error                    { return o.t.Close() }
+
+func (o *observedTransport) forward() {
+	defer close(o.out)
+	for f := range o.t.Frames() {
+		o.fn(f)
+		select {
+		case o.out <- f:
+		default:
+			// Nobody is mid-exchange, so shed the stalest frame; the
+			// buffer then holds the freshest traffic when a read starts.
+			select {
+			case <-o.out:
+			default:
+			}
+			select {
+			case o.out <- f:
+			default:
+			}
+		}
+	}
+}
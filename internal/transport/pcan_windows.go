@@ -0,0 +1,156 @@
+//go:build windows
+
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// PCAN-Basic backend: drives a PEAK PCAN-USB interface through the
+// vendor's PCANBasic.dll, which ships with their Windows driver. The
+// API is polled, so a read loop turns it into the usual frame channel.
+
+var (
+	pcanDLL        = windows.NewLazyDLL("PCANBasic.dll")
+	pcanInitialize = pcanDLL.NewProc("CAN_Initialize")
+	pcanUninit     = pcanDLL.NewProc("CAN_Uninitialize")
+	pcanRead       = pcanDLL.NewProc("CAN_Read")
+	pcanWrite      = pcanDLL.NewProc("CAN_Write")
+)
+
+// PCAN-Basic channel handles for the USB interfaces.
+const pcanUSBBus1 = 0x51
+
+// PCAN-Basic BTR0/BTR1 codes for the bitrates the iLoad buses use.
+var pcanBitrates = map[int]uintptr{
+	1000000: 0x0014,
+	500000:  0x001C,
+	250000:  0x011C,
+	125000:  0x031C,
+}
+
+// pcanStatus values that matter here.
+const (
+	pcanErrOK        = 0x00000
+	pcanErrQRcvEmpty = 0x00020
+)
+
+// pcanMsg mirrors TPCANMsg.
+type pcanMsg struct {
+	ID      uint32
+	MsgType uint8
+	Len     uint8
+	Data    [8]uint8
+}
+
+// pcanMsgType flag for extended (29-bit) frames.
+const pcanMsgExtended = 0x02
+
+// PCAN is a PEAK PCAN-USB channel.
+type PCAN struct {
+	channel uintptr
+	name    string
+	out     chan capture.CANFrame
+	done    chan struct{}
+	once    sync.Once
+}
+
+// OpenPCAN opens a PCAN-USB channel ("usb1" .. "usb8") at the given
+// bitrate (0 means 500k).
+func OpenPCAN(channel string, bitrate int) (*PCAN, error) {
+	if bitrate == 0 {
+		bitrate = 500000
+	}
+	btr, ok := pcanBitrates[bitrate]
+	if !ok {
+		return nil, fmt.Errorf("pcan: unsupported bitrate %d", bitrate)
+	}
+	var n int
+	if _, err := fmt.Sscanf(strings.ToLower(channel), "usb%d", &n); err != nil || n < 1 || n > 8 {
+		return nil, fmt.Errorf("pcan: bad channel %q (want usb1..usb8)", channel)
+	}
+	ch := uintptr(pcanUSBBus1 + n - 1)
+	status, _, _ := pcanInitialize.Call(ch, btr, 0, 0, 0)
+	if status != pcanErrOK {
+		return nil, fmt.Errorf("pcan: initialize %s: status 0x%X", channel, status)
+	}
+	p := &PCAN{
+		channel: ch,
+		name:    "pcan-" + channel,
+		out:     make(chan capture.CANFrame, 256),
+		done:    make(chan struct{}),
+	}
+	go p.readLoop()
+	return p, nil
+}
+
+// Send publishes one frame onto the bus.
+func (p *PCAN) Send(f capture.CANFrame) error {
+	msg := pcanMsg{ID: f.Arbitration(), Len: f.Length}
+	if f.IsExtended() {
+		msg.MsgType = pcanMsgExtended
+	}
+	copy(msg.Data[:], f.Payload())
+	status, _, _ := pcanWrite.Call(p.channel, uintptr(unsafe.Pointer(&msg)))
+	if status != pcanErrOK {
+		return fmt.Errorf("pcan: write: status 0x%X", status)
+	}
+	return nil
+}
+
+// Frames delivers received frames until the transport closes.
+func (p *PCAN) Frames() <-chan capture.CANFrame { return p.out }
+
+func (p *PCAN) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		pcanUninit.Call(p.channel)
+	})
+	return nil
+}
+
+func (p *PCAN) readLoop() {
+	defer close(p.out)
+	var msg pcanMsg
+	var stamp [12]byte // TPCANTimestamp; the host clock is used instead
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+		status, _, _ := pcanRead.Call(p.channel,
+			uintptr(unsafe.Pointer(&msg)), uintptr(unsafe.Pointer(&stamp)))
+		if status == pcanErrQRcvEmpty {
+			time.Sleep(2 * time.Millisecond)
+			continue
+		}
+		if status != pcanErrOK {
+			return
+		}
+		f := capture.CANFrame{
+			ID:        msg.ID,
+			Length:    msg.Len,
+			Timestamp: clock.Now().UnixNano(),
+			Bus:       p.name,
+		}
+		if msg.MsgType&pcanMsgExtended != 0 {
+			f.ID |= capture.EFFFlag
+		}
+		copy(f.Data[:], msg.Data[:])
+		select {
+		case p.out <- f:
+		case <-p.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// elmBaudRates are tried in order when probing a port: 38400 is the
+// ELM327 default, 115200 is common on clone adapters, 9600 on very old
+// ones.
+var elmBaudRates = []int{38400, 115200, 9600}
+
+// elmProbeTimeout bounds one ATZ round trip; a real ELM327 answers its
+// reset inside a second.
+const elmProbeTimeout = 2 * time.Second
+
+// PortInfo describes one enumerated serial port and, when a probe was
+// requested, what answered on it.
+type PortInfo struct {
+	Name string `json:"name"`
+	// ELM is the adapter's ATZ banner (e.g. "ELM327 v1.5") when an
+	// ELM327 answered, empty otherwise.
+	ELM string `json:"elm,omitempty"`
+	// Baud is the rate the adapter answered at.
+	Baud int `json:"baud,omitempty"`
+}
+
+// EnumeratePorts lists the system's serial ports, cross-platform —
+// /dev/ttyUSB* and friends on Linux, COMn on Windows, /dev/cu.* on
+// macOS.
+func EnumeratePorts() ([]string, error) {
+	ports, err := serial.GetPortsList()
+	if err != nil {
+		return nil, fmt.Errorf("enumerate serial ports: %w", err)
+	}
+	return ports, nil
+}
+
+// IdentifyELM327 probes a port for an ELM327 adapter, trying the common
+// baud rates. It returns the adapter's banner and the rate it answered
+// at, or an error if nothing ELM-like responded.
+func IdentifyELM327(port string) (banner string, baud int, err error) {
+	for _, rate := range elmBaudRates {
+		banner, err = probeELM(port, rate)
+		if err == nil {
+			return banner, rate, nil
+		}
+	}
+	return "", 0, fmt.Errorf("no ELM327 on %s: %w", port, err)
+}
+
+// probeELM opens the port, sends ATZ and reads until the '>' prompt.
+func probeELM(port string, baud int) (string, error) {
+	p, err := serial.Open(port, &serial.Mode{BaudRate: baud})
+	if err != nil {
+		return "", err
+	}
+	defer p.Close()
+	if err := p.SetReadTimeout(elmProbeTimeout); err != nil {
+		return "", err
+	}
+	if _, err := p.Write([]byte("ATZ\r")); err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(elmProbeTimeout)
+	var resp strings.Builder
+	buf := make([]byte, 64)
+	for time.Now().Before(deadline) {
+		n, err := p.Read(buf)
+		if err != nil {
+			return "", err
+		}
+		if n == 0 {
+			break // read timeout
+		}
+		resp.Write(buf[:n])
+		if strings.Contains(resp.String(), ">") {
+			break
+		}
+	}
+	for _, line := range strings.Split(resp.String(), "\r") {
+		if strings.Contains(line, "ELM327") {
+			return strings.TrimSpace(line), nil
+		}
+	}
+	return "", fmt.Errorf("no ELM327 banner at %d baud", baud)
+}
+
+// AutoDetectELM327 scans every enumerated port and returns the first one
+// an ELM327 answers on.
+func AutoDetectELM327() (PortInfo, error) {
+	ports, err := EnumeratePorts()
+	if err != nil {
+		return PortInfo{}, err
+	}
+	for _, port := range ports {
+		if banner, baud, err := IdentifyELM327(port); err == nil {
+			return PortInfo{Name: port, ELM: banner, Baud: baud}, nil
+		}
+	}
+	return PortInfo{}, fmt.Errorf("no ELM327 adapter found on %d port(s)", len(ports))
+}
@@ -0,0 +1,227 @@
+package transport
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// ConnState is the reconnector's view of the adapter link.
+type ConnState string
+
+const (
+	ConnConnected    ConnState = "connected"
+	ConnReconnecting ConnState = "reconnecting"
+)
+
+// ConnHealth is a snapshot of the link state, broadcast to websocket
+// clients as a "transport" message so the dashboard can show a dropped
+// dongle instead of silently stale gauges.
+type ConnHealth struct {
+	Name     string    `json:"name"`
+	State    ConnState `json:"state"`
+	Attempts int       `json:"attempts,omitempty"`
+	LastErr  string    `json:"last_error,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+const (
+	reconnectMinDelay = 500 * time.Millisecond
+	reconnectMaxDelay = 30 * time.Second
+	// pendingLimit bounds the frames buffered while the link is down;
+	// beyond it the oldest are dropped — a stale poll request is worth
+	// less than a fresh one.
+	pendingLimit = 64
+)
+
+// Reconnector wraps a dialable transport and keeps it alive: when the
+// serial or TCP link drops — the frame channel closes or a send fails —
+// it redials with exponential backoff instead of letting every poll
+// fail. Frames sent while disconnected are buffered and flushed once
+// the link returns.
+type Reconnector struct {
+	name string
+	dial func() (Transport, error)
+	// OnChange is called on every state transition; wire it to the
+	// server's ObserveTransportHealth to surface link state live.
+	OnChange func(ConnHealth)
+
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	mu      sync.Mutex
+	cur     Transport
+	pending []capture.CANFrame
+	filters []Filter
+	health  ConnHealth
+
+	out  chan capture.CANFrame
+	done chan struct{}
+	once sync.Once
+}
+
+// NewReconnector builds a reconnector for the named link. Call Start to
+// begin dialing; set OnChange first to observe every transition.
+func NewReconnector(name string, dial func() (Transport, error)) *Reconnector {
+	return &Reconnector{
+		name:     name,
+		dial:     dial,
+		minDelay: reconnectMinDelay,
+		maxDelay: reconnectMaxDelay,
+		health:   ConnHealth{Name: name, State: ConnReconnecting, Since: time.Now()},
+		out:      make(chan capture.CANFrame, 64),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the dial loop.
+func (r *Reconnector) Start() { go r.run() }
+
+// Health returns the current link state.
+func (r *Reconnector) Health() ConnHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.health
+}
+
+// Frames delivers frames from whichever underlying link is current.
+func (r *Reconnector) Frames() <-chan capture.CANFrame { return r.out }
+
+// Send forwards onto the live link, or buffers the frame for the next
+// one. A send error tears the link down so the dial loop replaces it.
+func (r *Reconnector) Send(f capture.CANFrame) error {
+	r.mu.Lock()
+	t := r.cur
+	if t == nil {
+		r.buffer(f)
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+	if err := t.Send(f); err != nil {
+		t.Close() // closes its frame channel; run() redials
+		r.mu.Lock()
+		r.buffer(f)
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// SetFilters stores the acceptance filter set and applies it to the
+// live link; every redial reapplies it, so filters survive a dropped
+// adapter.
+func (r *Reconnector) SetFilters(filters []Filter) error {
+	r.mu.Lock()
+	r.filters = append([]Filter(nil), filters...)
+	t := r.cur
+	r.mu.Unlock()
+	if fs, ok := t.(FilterSetter); ok {
+		return fs.SetFilters(filters)
+	}
+	return nil
+}
+
+// buffer queues a frame for the next link, dropping the oldest when
+// full. Callers hold mu.
+func (r *Reconnector) buffer(f capture.CANFrame) {
+	if len(r.pending) >= pendingLimit {
+		r.pending = r.pending[1:]
+	}
+	r.pending = append(r.pending, f)
+}
+
+func (r *Reconnector) Close() error {
+	r.once.Do(func() {
+		close(r.done)
+		r.mu.Lock()
+		if r.cur != nil {
+			r.cur.Close()
+		}
+		r.mu.Unlock()
+	})
+	return nil
+}
+
+func (r *Reconnector) run() {
+	defer close(r.out)
+	delay := r.minDelay
+	attempts := 0
+	for {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+		t, err := r.dial()
+		if err != nil {
+			attempts++
+			r.setState(ConnReconnecting, attempts, err)
+			select {
+			case <-time.After(delay):
+			case <-r.done:
+				return
+			}
+			if delay *= 2; delay > r.maxDelay {
+				delay = r.maxDelay
+			}
+			continue
+		}
+		delay, attempts = r.minDelay, 0
+		r.mu.Lock()
+		r.cur = t
+		pending := r.pending
+		r.pending = nil
+		filters := r.filters
+		r.mu.Unlock()
+		if len(filters) > 0 {
+			if fs, ok := t.(FilterSetter); ok {
+				fs.SetFilters(filters)
+			}
+		}
+		r.setState(ConnConnected, 0, nil)
+		for _, f := range pending {
+			t.Send(f)
+		}
+		r.forward(t)
+		r.mu.Lock()
+		r.cur = nil
+		r.mu.Unlock()
+	}
+}
+
+// forward copies frames from the live link until it closes.
+func (r *Reconnector) forward(t Transport) {
+	for {
+		select {
+		case f, ok := <-t.Frames():
+			if !ok {
+				return
+			}
+			select {
+			case r.out <- f:
+			case <-r.done:
+				t.Close()
+				return
+			}
+		case <-r.done:
+			t.Close()
+			return
+		}
+	}
+}
+
+func (r *Reconnector) setState(state ConnState, attempts int, err error) {
+	r.mu.Lock()
+	h := ConnHealth{Name: r.name, State: state, Attempts: attempts, Since: time.Now()}
+	if err != nil {
+		h.LastErr = err.Error()
+	}
+	changed := h.State != r.health.State || h.Attempts != r.health.Attempts
+	r.health = h
+	fn := r.OnChange
+	r.mu.Unlock()
+	if changed && fn != nil {
+		fn(h)
+	}
+}
@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// flakyLink is a transport whose frame channel can be torn down to
+// simulate a dropped adapter.
+type flakyLink struct {
+	mu   sync.Mutex
+	sent []capture.CANFrame
+	out  chan capture.CANFrame
+	once sync.Once
+}
+
+func newFlakyLink() *flakyLink {
+	return &flakyLink{out: make(chan capture.CANFrame, 8)}
+}
+
+func (l *flakyLink) Send(f capture.CANFrame) error {
+	l.mu.Lock()
+	l.sent = append(l.sent, f)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *flakyLink) Frames() <-chan capture.CANFrame { return l.out }
+func (l *flakyLink) Close() error                    { l.once.Do(func() { close(l.out) }); return nil }
+
+func (l *flakyLink) sentCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.sent)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReconnectorRedialsWithBackoff(t *testing.T) {
+	var mu sync.Mutex
+	var links []*flakyLink
+	fails := 2
+	r := NewReconnector("elm", func() (Transport, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fails > 0 {
+			fails--
+			return nil, fmt.Errorf("no such device")
+		}
+		l := newFlakyLink()
+		links = append(links, l)
+		return l, nil
+	})
+	r.minDelay, r.maxDelay = time.Millisecond, 4*time.Millisecond
+	var states []ConnHealth
+	r.OnChange = func(h ConnHealth) {
+		mu.Lock()
+		states = append(states, h)
+		mu.Unlock()
+	}
+	r.Start()
+	defer r.Close()
+
+	waitFor(t, "connect", func() bool { return r.Health().State == ConnConnected })
+	mu.Lock()
+	if len(states) != 3 || states[0].Attempts != 1 || states[1].Attempts != 2 ||
+		states[2].State != ConnConnected {
+		t.Errorf("states = %+v", states)
+	}
+	if states[0].LastErr != "no such device" {
+		t.Errorf("LastErr = %q", states[0].LastErr)
+	}
+	link := links[0]
+	mu.Unlock()
+
+	// Drop the link: the reconnector must dial a replacement.
+	link.Close()
+	waitFor(t, "redial", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(links) == 2
+	})
+}
+
+func TestReconnectorBuffersWhileDown(t *testing.T) {
+	var mu sync.Mutex
+	var link *flakyLink
+	ready := false
+	r := NewReconnector("elm", func() (Transport, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !ready {
+			return nil, fmt.Errorf("not yet")
+		}
+		link = newFlakyLink()
+		return link, nil
+	})
+	r.minDelay, r.maxDelay = time.Millisecond, time.Millisecond
+	r.Start()
+	defer r.Close()
+
+	// Sends while disconnected are held, not lost.
+	for i := 0; i < 3; i++ {
+		if err := r.Send(capture.CANFrame{ID: 0x7DF, Length: 8}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	ready = true
+	mu.Unlock()
+	waitFor(t, "flush", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return link != nil && link.sentCount() == 3
+	})
+
+	// Connected sends pass straight through, and received frames are
+	// forwarded.
+	if err := r.Send(capture.CANFrame{ID: 0x7E0, Length: 8}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "send", func() bool { return link.sentCount() == 4 })
+	link.out <- capture.CANFrame{ID: 0x7E8, Length: 8}
+	select {
+	case f := <-r.Frames():
+		if f.ID != 0x7E8 {
+			t.Errorf("forwarded ID = %X", f.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no forwarded frame")
+	}
+}
+
+// filterableLink records the acceptance filters applied to it.
+type filterableLink struct {
+	*flakyLink
+	mu      sync.Mutex
+	applied [][]Filter
+}
+
+func (l *filterableLink) SetFilters(f []Filter) error {
+	l.mu.Lock()
+	l.applied = append(l.applied, f)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *filterableLink) appliedCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.applied)
+}
+
+func TestReconnectorReappliesFiltersOnRedial(t *testing.T) {
+	var mu sync.Mutex
+	var links []*filterableLink
+	r := NewReconnector("elm", func() (Transport, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		l := &filterableLink{flakyLink: newFlakyLink()}
+		links = append(links, l)
+		return l, nil
+	})
+	r.minDelay, r.maxDelay = time.Millisecond, time.Millisecond
+	r.Start()
+	defer r.Close()
+
+	waitFor(t, "connect", func() bool { return r.Health().State == ConnConnected })
+	if err := r.SetFilters([]Filter{{ID: 0x7E8, Mask: 0x7F8}}); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	first := links[0]
+	mu.Unlock()
+	waitFor(t, "apply", func() bool { return first.appliedCount() == 1 })
+
+	// Drop the link: the replacement gets the same filters without the
+	// API being asked again.
+	first.Close()
+	waitFor(t, "reapply", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(links) == 2 && links[1].appliedCount() == 1
+	})
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"sync"
+	"time"
+)
+
+// Priority orders queued bus work. Live data wins over diagnostics,
+// which win over bulk jobs like map dumps — a telemetry poll should
+// never stall behind a 512-byte memory read.
+type Priority int
+
+const (
+	PriorityLive Priority = iota
+	PriorityDiagnostic
+	PriorityBackground
+	numPriorities
+)
+
+// Scheduler serialises exclusive use of one bus between competing
+// consumers. The ISO-TP layer owns the transport's receive stream for a
+// whole exchange, so telemetry polling, DTC reads and map dumps must
+// take turns rather than interleave; jobs queue here and run one at a
+// time, highest priority first, FIFO within a priority. An optional
+// inter-job gap keeps slow adapters (ELM327s choke on back-to-back
+// commands) from dropping requests.
+type Scheduler struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	// queues hold ticket numbers; tickets order jobs FIFO within a
+	// priority level.
+	queues      [numPriorities][]uint64
+	nextTicket  uint64
+	busy        bool
+	gap         time.Duration
+	lastRelease time.Time
+}
+
+// NewScheduler builds a scheduler enforcing gap between consecutive
+// jobs; zero means no pacing.
+func NewScheduler(gap time.Duration) *Scheduler {
+	s := &Scheduler{gap: gap}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Do runs fn with exclusive bus access at the given priority, blocking
+// until it is this job's turn, and returns fn's error.
+func (s *Scheduler) Do(p Priority, fn func() error) error {
+	s.mu.Lock()
+	ticket := s.nextTicket
+	s.nextTicket++
+	s.queues[p] = append(s.queues[p], ticket)
+	for s.busy || s.head() != ticket {
+		s.cond.Wait()
+	}
+	s.queues[p] = s.queues[p][1:]
+	s.busy = true
+	wait := s.gap - time.Since(s.lastRelease)
+	s.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	err := fn()
+
+	s.mu.Lock()
+	s.busy = false
+	s.lastRelease = time.Now()
+	s.cond.Broadcast()
+	s.mu.Unlock()
+	return err
+}
+
+// Pending returns the number of jobs waiting for the bus.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, q := range s.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// head returns the ticket that runs next; callers hold s.mu.
+func (s *Scheduler) head() uint64 {
+	for _, q := range s.queues {
+		if len(q) > 0 {
+			return q[0]
+		}
+	}
+	return s.nextTicket // empty: no ticket matches
+}
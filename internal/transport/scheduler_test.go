@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerPriorityOrder(t *testing.T) {
+	s := NewScheduler(0)
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	ran := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+	hold := make(chan struct{})
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Do(PriorityLive, func() error { close(started); <-hold; return nil })
+	}()
+	<-started
+	// Queue a background job first, then a live one, while the bus is
+	// held; the live job must still run first.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Do(PriorityBackground, func() error { ran("background"); return nil })
+	}()
+	for s.Pending() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Do(PriorityLive, func() error { ran("live"); return nil })
+	}()
+	for s.Pending() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(hold)
+	wg.Wait()
+	if len(order) != 2 || order[0] != "live" || order[1] != "background" {
+		t.Errorf("ran in order %v, want [live background]", order)
+	}
+}
+
+func TestSchedulerSerialises(t *testing.T) {
+	s := NewScheduler(0)
+	var (
+		wg     sync.WaitGroup
+		active int
+		mu     sync.Mutex
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Do(PriorityDiagnostic, func() error {
+				mu.Lock()
+				active++
+				if active != 1 {
+					t.Errorf("%d jobs on the bus at once", active)
+				}
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				active--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSchedulerGapPacing(t *testing.T) {
+	s := NewScheduler(20 * time.Millisecond)
+	s.Do(PriorityLive, func() error { return nil })
+	start := time.Now()
+	s.Do(PriorityLive, func() error { return nil })
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("second job ran after %s, want the 20ms gap enforced", elapsed)
+	}
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// SerialConfig holds the port parameters for an ELM327 serial link.
+// Genuine adapters are happy with the 38400-8N1 default, but clones
+// ship at 9600 or 115200 and the odd one wants non-default framing.
+// Zero values select the defaults noted per field. RTS/CTS flow control
+// is not supported — no ELM dongle uses it, and neither does the serial
+// backend.
+type SerialConfig struct {
+	Baud     int    // default 38400
+	DataBits int    // 5–8, default 8
+	Parity   string // "none" (default), "odd" or "even"
+	StopBits int    // 1 (default) or 2
+	// ReadTimeout overrides the per-read slice used while waiting for
+	// the adapter's prompt; shorter values notice cancellation faster at
+	// the cost of more wakeups.
+	ReadTimeout time.Duration
+}
+
+// mode translates the config into the serial package's terms.
+func (c SerialConfig) mode() (*serial.Mode, error) {
+	m := &serial.Mode{BaudRate: c.Baud, DataBits: c.DataBits}
+	if m.BaudRate == 0 {
+		m.BaudRate = elmBaudRates[0]
+	}
+	if m.DataBits == 0 {
+		m.DataBits = 8
+	}
+	switch c.Parity {
+	case "", "none":
+		m.Parity = serial.NoParity
+	case "odd":
+		m.Parity = serial.OddParity
+	case "even":
+		m.Parity = serial.EvenParity
+	default:
+		return nil, fmt.Errorf("serial: unknown parity %q", c.Parity)
+	}
+	switch c.StopBits {
+	case 0, 1:
+		m.StopBits = serial.OneStopBit
+	case 2:
+		m.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("serial: %d stop bits not supported", c.StopBits)
+	}
+	return m, nil
+}
+
+// OpenELMSerial opens the adapter at port with explicit link
+// parameters, then resets it like OpenELM.
+func OpenELMSerial(port string, cfg SerialConfig) (*ELMConn, error) {
+	mode, err := cfg.mode()
+	if err != nil {
+		return nil, err
+	}
+	p, err := serial.Open(port, mode)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", port, err)
+	}
+	c, err := newELMConn(p)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ReadTimeout > 0 {
+		if err := p.SetReadTimeout(cfg.ReadTimeout); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"testing"
+
+	"go.bug.st/serial"
+)
+
+func TestSerialConfigMode(t *testing.T) {
+	m, err := SerialConfig{}.mode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BaudRate != 38400 || m.DataBits != 8 || m.Parity != serial.NoParity || m.StopBits != serial.OneStopBit {
+		t.Errorf("default mode = %+v", m)
+	}
+
+	m, err = SerialConfig{Baud: 9600, DataBits: 7, Parity: "even", StopBits: 2}.mode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BaudRate != 9600 || m.DataBits != 7 || m.Parity != serial.EvenParity || m.StopBits != serial.TwoStopBits {
+		t.Errorf("mode = %+v", m)
+	}
+
+	if _, err := (SerialConfig{Parity: "mark"}).mode(); err == nil {
+		t.Error("unknown parity accepted")
+	}
+	if _, err := (SerialConfig{StopBits: 3}).mode(); err == nil {
+		t.Error("3 stop bits accepted")
+	}
+}
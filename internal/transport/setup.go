@@ -0,0 +1,35 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// SetupInterface configures and brings up a SocketCAN interface with
+// iproute2, so the daemon works on a fresh image without a manual
+// `ip link` incantation. bitrate is in bit/s; listenOnly puts the
+// controller in a mode that never ACKs frames on the bus.
+func SetupInterface(ctx context.Context, iface string, bitrate int, listenOnly bool) error {
+	if bitrate <= 0 {
+		bitrate = 500000
+	}
+	// Down first: bitrate changes are refused while the link is up.
+	if out, err := exec.CommandContext(ctx, "ip", "link", "set", iface, "down").
+		CombinedOutput(); err != nil {
+		return fmt.Errorf("transport: down %s: %v: %s", iface, err, out)
+	}
+	args := []string{"link", "set", iface, "type", "can", "bitrate", strconv.Itoa(bitrate)}
+	if listenOnly {
+		args = append(args, "listen-only", "on")
+	}
+	if out, err := exec.CommandContext(ctx, "ip", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("transport: configure %s: %v: %s", iface, err, out)
+	}
+	if out, err := exec.CommandContext(ctx, "ip", "link", "set", iface, "up").
+		CombinedOutput(); err != nil {
+		return fmt.Errorf("transport: up %s: %v: %s", iface, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+//go:build linux
+
+package transport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+)
+
+// Raw SocketCAN frame records: 16 bytes for classic can_frame, 72 for
+// canfd_frame. The kernel hands both over the same socket once
+// CAN_RAW_FD_FRAMES is enabled, distinguished by read length — the same
+// convention the pcap writer uses. Identifiers are host byte order;
+// every platform this daemon targets (Pi, x86) is little-endian.
+const (
+	canFrameLen   = 16
+	canfdFrameLen = 72
+
+	canfdFlagBRS = 0x01
+	canfdFlagESI = 0x02
+)
+
+// SocketCAN is a native Linux CAN transport: a raw AF_CAN socket on one
+// interface, no adapter firmware in the way. Compared to an ELM327 it
+// sees every frame, keeps hardware timestamping latency out of the
+// picture and handles CAN-FD.
+type SocketCAN struct {
+	fd    int
+	name  string
+	out   chan capture.CANFrame
+	close sync.Once
+}
+
+// OpenSocketCAN binds a raw CAN socket to the named interface (e.g.
+// "can0"). The interface must already be up — AutoSetup or `ip link`
+// handles bitrate; a bound raw socket has no say in it.
+func OpenSocketCAN(iface string) (*SocketCAN, error) {
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("socketcan: %s: %w", iface, err)
+	}
+	fd, err := unix.Socket(unix.AF_CAN, unix.SOCK_RAW, unix.CAN_RAW)
+	if err != nil {
+		return nil, fmt.Errorf("socketcan: socket: %w", err)
+	}
+	// Accept FD frames too; readers that only speak classic CAN still
+	// work, they just see the 8-byte mirror.
+	if err := unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FD_FRAMES, 1); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("socketcan: enable FD frames: %w", err)
+	}
+	// Ask the kernel for error frames too — without an error filter it
+	// delivers none, and the wiring diagnostics downstream see nothing.
+	if err := unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_ERR_FILTER, unix.CAN_ERR_MASK); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("socketcan: enable error frames: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrCAN{Ifindex: ifi.Index}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("socketcan: bind %s: %w", iface, err)
+	}
+	s := &SocketCAN{fd: fd, name: iface, out: make(chan capture.CANFrame, 256)}
+	go s.readLoop()
+	return s, nil
+}
+
+// Send publishes one frame onto the bus.
+func (s *SocketCAN) Send(f capture.CANFrame) error {
+	var rec []byte
+	payload := f.Payload()
+	if f.FD {
+		rec = make([]byte, canfdFrameLen)
+		rec[4] = byte(len(payload))
+		if f.BRS {
+			rec[5] |= canfdFlagBRS
+		}
+		if f.ESI {
+			rec[5] |= canfdFlagESI
+		}
+	} else {
+		rec = make([]byte, canFrameLen)
+		rec[4] = f.Length
+	}
+	binary.LittleEndian.PutUint32(rec[0:4], f.ID)
+	copy(rec[8:], payload)
+	if _, err := unix.Write(s.fd, rec); err != nil {
+		return fmt.Errorf("socketcan: send on %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// SetFilters pushes acceptance filters into the kernel so unwanted
+// frames never cross the socket; an empty slice restores
+// accept-everything.
+func (s *SocketCAN) SetFilters(filters []Filter) error {
+	kf := make([]unix.CanFilter, len(filters))
+	for i, f := range filters {
+		kf[i] = unix.CanFilter{Id: f.ID, Mask: f.Mask}
+	}
+	if len(kf) == 0 {
+		kf = []unix.CanFilter{{Id: 0, Mask: 0}} // matches everything
+	}
+	if err := unix.SetsockoptCanRawFilter(s.fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FILTER, kf); err != nil {
+		return fmt.Errorf("socketcan: set filters on %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// Frames delivers received frames until the transport closes.
+func (s *SocketCAN) Frames() <-chan capture.CANFrame { return s.out }
+
+// Close shuts the socket down; the frame channel closes once the read
+// loop drains out.
+func (s *SocketCAN) Close() error {
+	var err error
+	s.close.Do(func() { err = unix.Close(s.fd) })
+	return err
+}
+
+func (s *SocketCAN) readLoop() {
+	defer close(s.out)
+	buf := make([]byte, canfdFrameLen)
+	for {
+		n, err := unix.Read(s.fd, buf)
+		if err != nil {
+			return // socket closed or interface gone
+		}
+		if n != canFrameLen && n != canfdFrameLen {
+			continue
+		}
+		f := capture.CANFrame{
+			ID:        binary.LittleEndian.Uint32(buf[0:4]),
+			Timestamp: clock.Now().UnixNano(),
+			Bus:       s.name,
+		}
+		length := int(buf[4])
+		if n == canfdFrameLen {
+			f.FD = true
+			f.BRS = buf[5]&canfdFlagBRS != 0
+			f.ESI = buf[5]&canfdFlagESI != 0
+			if length > 64 {
+				length = 64
+			}
+		} else if length > 8 {
+			length = 8
+		}
+		f.SetPayload(append([]byte(nil), buf[8:8+length]...))
+		s.out <- f
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package transport
+
+import (
+	"fmt"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// SocketCAN is only available on Linux; this stub keeps the package
+// compiling for cross-platform tooling builds.
+type SocketCAN struct{}
+
+// OpenSocketCAN fails on non-Linux platforms: AF_CAN is a Linux-only
+// socket family.
+func OpenSocketCAN(iface string) (*SocketCAN, error) {
+	return nil, fmt.Errorf("socketcan: not supported on this platform")
+}
+
+func (s *SocketCAN) Send(f capture.CANFrame) error   { return fmt.Errorf("socketcan: not supported") }
+func (s *SocketCAN) SetFilters([]Filter) error       { return fmt.Errorf("socketcan: not supported") }
+func (s *SocketCAN) Frames() <-chan capture.CANFrame { return nil }
+func (s *SocketCAN) Close() error                    { return nil }
@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// TrafficLog tees raw transport traffic to a timestamped hex-dump file,
+// for pinning down protocol trouble with flaky adapters. It starts
+// enabled and can be switched off and on at runtime — the API exposes
+// the toggle — without reopening the file.
+type TrafficLog struct {
+	mu      sync.Mutex
+	f       *os.File
+	enabled atomic.Bool
+}
+
+// NewTrafficLog opens (appending) the log file at path.
+func NewTrafficLog(path string) (*TrafficLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("traffic log: %w", err)
+	}
+	l := &TrafficLog{f: f}
+	l.enabled.Store(true)
+	return l, nil
+}
+
+// SetEnabled switches logging on or off.
+func (l *TrafficLog) SetEnabled(on bool) { l.enabled.Store(on) }
+
+// Enabled reports whether traffic is currently being written.
+func (l *TrafficLog) Enabled() bool { return l.enabled.Load() }
+
+// Log writes one direction-tagged record: dir is "TX" or "RX", label
+// names the link, data is dumped as hex.
+func (l *TrafficLog) Log(dir, label string, data []byte) {
+	if !l.enabled.Load() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.f, "%s %s %s % X\n",
+		time.Now().Format("2006-01-02T15:04:05.000"), dir, label, data)
+}
+
+func (l *TrafficLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// LogFrames wraps a transport so every frame in either direction lands
+// in the log, tagged with the bus label.
+func LogFrames(t Transport, label string, log *TrafficLog) Transport {
+	lt := &loggedTransport{t: t, label: label, log: log, out: make(chan capture.CANFrame, 64)}
+	go lt.forward()
+	return lt
+}
+
+type loggedTransport struct {
+	t     Transport
+	label string
+	log   *TrafficLog
+	out   chan capture.CANFrame
+}
+
+func (lt *loggedTransport) Send(f capture.CANFrame) error {
+	lt.log.Log("TX", lt.label, frameBytes(f))
+	return lt.t.Send(f)
+}
+
+func (lt *loggedTransport) Frames() <-chan capture.CANFrame { return lt.out }
+func (lt *loggedTransport) Close() error                    { return lt.t.Close() }
+
+// SetFilters forwards to the wrapped transport, so a logged link stays
+// adjustable through the filter API.
+func (lt *loggedTransport) SetFilters(f []Filter) error {
+	if fs, ok := lt.t.(FilterSetter); ok {
+		return fs.SetFilters(f)
+	}
+	return fmt.Errorf("transport: %T does not support acceptance filters", lt.t)
+}
+
+func (lt *loggedTransport) forward() {
+	defer close(lt.out)
+	for f := range lt.t.Frames() {
+		lt.log.Log("RX", lt.label, frameBytes(f))
+		lt.out <- f
+	}
+}
+
+// frameBytes renders a frame as the bytes it carries on the wire: the
+// arbitration ID big-endian, then the payload.
+func frameBytes(f capture.CANFrame) []byte {
+	id := f.Arbitration()
+	b := []byte{byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id)}
+	return append(b, f.Payload()...)
+}
+
+// LogTraffic tees every raw byte crossing this adapter link into the
+// given traffic log, tagged with label. Call it straight after opening;
+// a transport built on the connection afterwards inherits the tee.
+func (c *ELMConn) LogTraffic(label string, log *TrafficLog) {
+	c.p = LogPort(c.p, label, log)
+}
+
+// LogPort wraps an ELM byte link so every raw byte in either direction
+// lands in the log — one level below LogFrames, for adapters that
+// garble the command protocol itself.
+func LogPort(p elmPort, label string, log *TrafficLog) elmPort {
+	return &loggedPort{p: p, label: label, log: log}
+}
+
+type loggedPort struct {
+	p     elmPort
+	label string
+	log   *TrafficLog
+}
+
+func (lp *loggedPort) Read(b []byte) (int, error) {
+	n, err := lp.p.Read(b)
+	if n > 0 {
+		lp.log.Log("RX", lp.label, b[:n])
+	}
+	return n, err
+}
+
+func (lp *loggedPort) Write(b []byte) (int, error) {
+	lp.log.Log("TX", lp.label, b)
+	return lp.p.Write(b)
+}
+
+func (lp *loggedPort) Close() error                         { return lp.p.Close() }
+func (lp *loggedPort) SetReadTimeout(d time.Duration) error { return lp.p.SetReadTimeout(d) }
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+func TestTrafficLogTeesFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.log")
+	log, err := NewTrafficLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	inner := newFlakyLink()
+	tr := LogFrames(inner, "can0", log)
+	f := capture.CANFrame{ID: 0x7DF, Length: 8}
+	copy(f.Data[:], []byte{0x02, 0x01, 0x0C})
+	if err := tr.Send(f); err != nil {
+		t.Fatal(err)
+	}
+	inner.out <- capture.CANFrame{ID: 0x7E8, Length: 8, Data: [8]byte{0x04, 0x41, 0x0C, 0x1A, 0xF8}}
+	select {
+	case <-tr.Frames():
+	case <-time.After(time.Second):
+		t.Fatal("no forwarded frame")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log lines = %q", lines)
+	}
+	if !strings.Contains(lines[0], "TX can0 00 00 07 DF 02 01 0C") {
+		t.Errorf("TX line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "RX can0 00 00 07 E8 04 41 0C 1A F8") {
+		t.Errorf("RX line = %q", lines[1])
+	}
+}
+
+func TestTrafficLogToggle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.log")
+	log, err := NewTrafficLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	log.SetEnabled(false)
+	log.Log("TX", "can0", []byte{0x01})
+	if log.Enabled() {
+		t.Error("Enabled() after SetEnabled(false)")
+	}
+	log.SetEnabled(true)
+	log.Log("RX", "can0", []byte{0x02})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if strings.Contains(out, "TX") || !strings.Contains(out, "RX") {
+		t.Errorf("log = %q", out)
+	}
+}
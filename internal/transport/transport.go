@@ -0,0 +1,53 @@
+// Package transport abstracts the physical links to the vehicle —
+// ELM327 dongles, SocketCAN interfaces and test doubles — behind one
+// interface, and enforces transmit safety policy on anything that
+// publishes onto a real bus.
+package transport
+
+import (
+	"fmt"
+
+	"github.com/anodyne74/iload-obd2/capture"
+)
+
+// Transport is a bidirectional CAN link.
+type Transport interface {
+	// Send publishes one frame onto the bus.
+	Send(f capture.CANFrame) error
+	// Frames delivers received frames until the transport closes.
+	Frames() <-chan capture.CANFrame
+	Close() error
+}
+
+// Open builds the transport a bus config selects by type. An empty type
+// means "socketcan" — the native path on the Pi. ELM-backed types take
+// the adapter's address in place of an interface name: a serial device
+// for "elm", host:port for "udp".
+func Open(typ, iface string) (Transport, error) {
+	switch typ {
+	case "", "socketcan":
+		return OpenSocketCAN(iface)
+	case "elm":
+		conn, err := OpenELM(iface, elmBaudRates[0])
+		if err != nil {
+			return nil, err
+		}
+		return NewELMTransport(conn, iface)
+	case "udp":
+		conn, err := OpenELMUDP(iface)
+		if err != nil {
+			return nil, err
+		}
+		return NewELMTransport(conn, iface)
+	case "pcan":
+		// Channel like "usb1"; callers needing a non-500k bitrate use
+		// OpenPCAN directly.
+		return OpenPCAN(iface, 0)
+	case "kvaser":
+		return OpenKvaser(iface, 0)
+	case "mock":
+		// iface optionally names a capture session to replay.
+		return openMock(iface)
+	}
+	return nil, fmt.Errorf("transport: unknown type %q", typ)
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// WiFi ELM327 adapters serve the command stream on a TCP or UDP socket,
+// conventionally 192.168.0.10:35000. The UDP flavour needs two things a
+// plain dial doesn't give: responses arrive as datagrams that must be
+// reassembled into the '>'-terminated text stream, and the adapter
+// forgets idle peers, so the link is kept warm with empty probe
+// datagrams.
+
+// udpKeepaliveInterval is how often an idle link is probed. Adapters
+// drop peer state after roughly 30 seconds of silence.
+const udpKeepaliveInterval = 10 * time.Second
+
+// OpenELMUDP connects to a WiFi adapter speaking UDP at addr
+// (host:port) and resets it to a known state like OpenELM.
+func OpenELMUDP(addr string) (*ELMConn, error) {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("wifi: %w", err)
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, fmt.Errorf("wifi: %w", err)
+	}
+	p := newUDPPort(conn, udpKeepaliveInterval)
+	return newELMConn(p)
+}
+
+// udpPort adapts a UDP socket to the elmPort contract: datagrams are
+// buffered so reads see a continuous byte stream, and a background
+// prober sends empty datagrams while the link is idle.
+type udpPort struct {
+	conn *net.UDPConn
+	buf  []byte
+
+	mu        sync.Mutex
+	timeout   time.Duration
+	lastWrite time.Time
+
+	done chan struct{}
+	once sync.Once
+}
+
+func newUDPPort(conn *net.UDPConn, keepalive time.Duration) *udpPort {
+	p := &udpPort{
+		conn:      conn,
+		lastWrite: time.Now(),
+		done:      make(chan struct{}),
+	}
+	go p.keepalive(keepalive)
+	return p
+}
+
+// keepalive probes the adapter with an empty datagram whenever the link
+// has been idle for a full interval. Empty datagrams keep the adapter's
+// peer state (and any NAT entry) alive without the ELM interpreting
+// them — a bare CR would make it repeat the last command.
+func (p *udpPort) keepalive(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			p.mu.Lock()
+			idle := time.Since(p.lastWrite) >= interval
+			p.mu.Unlock()
+			if idle {
+				p.conn.Write(nil)
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *udpPort) Read(b []byte) (int, error) {
+	if len(p.buf) == 0 {
+		p.mu.Lock()
+		timeout := p.timeout
+		p.mu.Unlock()
+		if timeout > 0 {
+			if err := p.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+				return 0, err
+			}
+		}
+		pkt := make([]byte, 1500)
+		n, err := p.conn.Read(pkt)
+		if err != nil {
+			if os.IsTimeout(err) {
+				return 0, nil // same contract as a serial read timeout
+			}
+			return 0, err
+		}
+		p.buf = append(p.buf, pkt[:n]...)
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+func (p *udpPort) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	p.lastWrite = time.Now()
+	p.mu.Unlock()
+	return p.conn.Write(b)
+}
+
+func (p *udpPort) Close() error {
+	p.once.Do(func() { close(p.done) })
+	return p.conn.Close()
+}
+
+func (p *udpPort) SetReadTimeout(d time.Duration) error {
+	p.mu.Lock()
+	p.timeout = d
+	p.mu.Unlock()
+	return nil
+}
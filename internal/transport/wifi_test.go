@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeUDPAdapter answers ELM commands on a local UDP socket, splitting
+// each response across two datagrams to exercise reassembly. Empty
+// datagrams (keepalive probes) are counted and ignored.
+type fakeUDPAdapter struct {
+	conn      net.PacketConn
+	responses map[string]string
+	probes    chan struct{}
+}
+
+func newFakeUDPAdapter(t *testing.T, responses map[string]string) *fakeUDPAdapter {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &fakeUDPAdapter{conn: conn, responses: responses, probes: make(chan struct{}, 8)}
+	t.Cleanup(func() { conn.Close() })
+	go a.serve()
+	return a
+}
+
+func (a *fakeUDPAdapter) serve() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			a.probes <- struct{}{}
+			continue
+		}
+		cmd := strings.TrimSuffix(string(buf[:n]), "\r")
+		resp, ok := a.responses[cmd]
+		if !ok {
+			resp = "?\r>"
+		}
+		half := len(resp) / 2
+		a.conn.WriteTo([]byte(resp[:half]), addr)
+		a.conn.WriteTo([]byte(resp[half:]), addr)
+	}
+}
+
+func TestOpenELMUDP(t *testing.T) {
+	a := newFakeUDPAdapter(t, map[string]string{
+		"ATZ":  "ELM327 v1.5\r>",
+		"ATE0": "OK\r>",
+		"010C": "41 0C 1A F8\r>",
+	})
+	conn, err := OpenELMUDP(a.conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	resp, err := conn.Command("010C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != "41 0C 1A F8" {
+		t.Errorf("response = %q", resp)
+	}
+}
+
+func TestUDPKeepaliveProbes(t *testing.T) {
+	a := newFakeUDPAdapter(t, nil)
+	raddr, err := net.ResolveUDPAddr("udp", a.conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := newUDPPort(conn, 5*time.Millisecond)
+	defer p.Close()
+
+	select {
+	case <-a.probes:
+	case <-time.After(time.Second):
+		t.Fatal("no keepalive probe on an idle link")
+	}
+}
@@ -0,0 +1,213 @@
+package uds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// ISO-TP (ISO 15765-2) segmentation: UDS messages longer than seven
+// bytes are split across CAN frames with a one-byte protocol control
+// header, paced by flow-control frames from the receiver. The
+// normal-addressing classic-CAN variant is implemented, over either
+// 11-bit or 29-bit identifiers — the iLoad's diagnostic bus speaks the
+// 11-bit form, but ISO 15765-4 also defines 29-bit OBD addressing.
+
+// PCI frame types, upper nibble of the first payload byte.
+const (
+	pciSingle      = 0x0
+	pciFirst       = 0x1
+	pciConsecutive = 0x2
+	pciFlowControl = 0x3
+)
+
+const (
+	// responseTimeout is the UDS P2 client timeout: how long to wait for
+	// the first frame of a response.
+	responseTimeout = time.Second
+	// pendingTimeout is the extended P2* wait applied after the ECU
+	// answers "response pending".
+	pendingTimeout = 5 * time.Second
+	// padByte fills unused frame bytes; many ECUs require full-length
+	// frames on the diagnostic bus.
+	padByte = 0x00
+)
+
+// isotp exchanges segmented messages with one ECU over a raw transport.
+// It assumes exclusive use of the transport's receive stream for the
+// duration of an exchange; concurrent requesters must arbitrate above
+// this layer.
+type isotp struct {
+	bus    transport.Transport
+	reqID  uint32
+	respID uint32
+	// extended selects 29-bit identifiers; transmitted frames carry the
+	// EFF flag and responses match on the arbitration ID either way.
+	extended bool
+}
+
+// fromECU reports whether a received frame is this ECU's response.
+func (tp *isotp) fromECU(f capture.CANFrame) bool {
+	return f.Arbitration() == tp.respID
+}
+
+func (tp *isotp) frame(data []byte) capture.CANFrame {
+	id := tp.reqID
+	if tp.extended {
+		id |= capture.EFFFlag
+	}
+	f := capture.CANFrame{ID: id, Length: 8, Timestamp: clock.Now().UnixNano()}
+	copy(f.Data[:], data)
+	for i := len(data); i < 8; i++ {
+		f.Data[i] = padByte
+	}
+	return f
+}
+
+// send transmits one message, segmenting into first/consecutive frames
+// and honouring the receiver's flow control when it doesn't fit a
+// single frame.
+func (tp *isotp) send(ctx context.Context, msg []byte) error {
+	if len(msg) <= 7 {
+		buf := append([]byte{byte(pciSingle<<4 | len(msg))}, msg...)
+		return tp.bus.Send(tp.frame(buf))
+	}
+	if len(msg) > 0xFFF {
+		return fmt.Errorf("uds: message of %d bytes exceeds ISO-TP limit", len(msg))
+	}
+	first := append([]byte{byte(pciFirst<<4 | len(msg)>>8), byte(len(msg))}, msg[:6]...)
+	if err := tp.bus.Send(tp.frame(first)); err != nil {
+		return err
+	}
+	stMin, err := tp.awaitFlowControl(ctx)
+	if err != nil {
+		return err
+	}
+	rest := msg[6:]
+	for seq := byte(1); len(rest) > 0; seq = (seq + 1) & 0x0F {
+		n := len(rest)
+		if n > 7 {
+			n = 7
+		}
+		buf := append([]byte{pciConsecutive<<4 | seq}, rest[:n]...)
+		if err := tp.bus.Send(tp.frame(buf)); err != nil {
+			return err
+		}
+		rest = rest[n:]
+		if stMin > 0 {
+			time.Sleep(stMin)
+		}
+	}
+	return nil
+}
+
+// awaitFlowControl waits for the receiver's clear-to-send and returns
+// the minimum separation time it asked for between consecutive frames.
+func (tp *isotp) awaitFlowControl(ctx context.Context) (time.Duration, error) {
+	deadline := time.After(responseTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case f, ok := <-tp.bus.Frames():
+			if !ok {
+				return 0, fmt.Errorf("uds: transport closed awaiting flow control")
+			}
+			if !tp.fromECU(f) || f.Data[0]>>4 != pciFlowControl {
+				continue
+			}
+			if fs := f.Data[0] & 0x0F; fs == 2 {
+				return 0, fmt.Errorf("uds: receiver overflow")
+			}
+			st := f.Data[2]
+			if st > 0x7F {
+				st = 1 // 100-900µs encodings: round up to a millisecond
+			}
+			return time.Duration(st) * time.Millisecond, nil
+		case <-deadline:
+			return 0, fmt.Errorf("uds: no flow control within %s", responseTimeout)
+		}
+	}
+}
+
+// receive reassembles one message from the ECU, answering its first
+// frame with flow control.
+func (tp *isotp) receive(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	var (
+		buf    []byte
+		want   int
+		seq    byte = 1
+		opened bool
+	)
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case f, ok := <-tp.bus.Frames():
+			if !ok {
+				return nil, fmt.Errorf("uds: transport closed awaiting response")
+			}
+			if !tp.fromECU(f) {
+				continue
+			}
+			switch f.Data[0] >> 4 {
+			case pciSingle:
+				n := int(f.Data[0] & 0x0F)
+				if n > 7 {
+					// A single frame carries at most 7 data bytes; the
+					// bus is untrusted input, so drop garbage instead
+					// of slicing past the frame.
+					continue
+				}
+				return append([]byte(nil), f.Data[1:1+n]...), nil
+			case pciFirst:
+				want = int(f.Data[0]&0x0F)<<8 | int(f.Data[1])
+				buf = append(buf, f.Data[2:8]...)
+				opened = true
+				// Clear to send, no block limit, no separation time.
+				if err := tp.bus.Send(tp.frame([]byte{pciFlowControl << 4, 0, 0})); err != nil {
+					return nil, err
+				}
+			case pciConsecutive:
+				if !opened {
+					continue
+				}
+				if got := f.Data[0] & 0x0F; got != seq {
+					return nil, fmt.Errorf("uds: consecutive frame %d out of order (want %d)", got, seq)
+				}
+				seq = (seq + 1) & 0x0F
+				buf = append(buf, f.Data[1:8]...)
+				if len(buf) >= want {
+					return buf[:want], nil
+				}
+			}
+		case <-deadline:
+			return nil, fmt.Errorf("uds: no response within %s", timeout)
+		}
+	}
+}
+
+// exchange performs one request/response round trip, waiting out any
+// "response pending" negatives the ECU sends while it works.
+func (tp *isotp) exchange(ctx context.Context, req []byte) ([]byte, error) {
+	if err := tp.send(ctx, req); err != nil {
+		return nil, err
+	}
+	timeout := responseTimeout
+	for {
+		resp, err := tp.receive(ctx, timeout)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) == 3 && resp[0] == negativeResponseSID && resp[2] == nrcResponsePending {
+			timeout = pendingTimeout
+			continue
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,80 @@
+package uds
+
+import (
+	"testing"
+)
+
+// memoryECU serves ReadMemoryByAddress requests from a byte array based
+// at memBase, optionally corrupting the first read it answers.
+const memBase = 0x00084000
+
+func newMemoryECU(mem []byte, corruptFirst bool) *fakeECU {
+	reads := 0
+	return newFakeECU(func(req []byte) [][]byte {
+		if len(req) != 8 || req[0] != 0x23 || req[1] != 0x24 {
+			return [][]byte{{0x7F, req[0], 0x11}}
+		}
+		addr := uint32(req[2])<<24 | uint32(req[3])<<16 | uint32(req[4])<<8 | uint32(req[5])
+		size := int(req[6])<<8 | int(req[7])
+		off := int(addr - memBase)
+		if off < 0 || off+size > len(mem) {
+			return [][]byte{{0x7F, req[0], 0x31}}
+		}
+		chunk := append([]byte(nil), mem[off:off+size]...)
+		reads++
+		if corruptFirst && reads == 1 {
+			chunk[0] ^= 0xFF
+		}
+		return [][]byte{append([]byte{0x63}, chunk...)}
+	})
+}
+
+func testMem(n int) []byte {
+	mem := make([]byte, n)
+	for i := range mem {
+		mem[i] = byte(i)
+	}
+	return mem
+}
+
+func TestReadBlockChunksAndVerifies(t *testing.T) {
+	mem := testMem(0x200)
+	c := NewClient(newMemoryECU(mem, false), 0, 0)
+	block, err := c.ReadBlock(memBase, len(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range block {
+		if b != mem[i] {
+			t.Fatalf("block[%d] = %#x, want %#x", i, b, mem[i])
+		}
+	}
+}
+
+func TestReadBlockRetriesCorruption(t *testing.T) {
+	mem := testMem(0x80)
+	c := NewClient(newMemoryECU(mem, true), 0, 0)
+	block, err := c.ReadBlock(memBase, len(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block[0] != mem[0] {
+		t.Errorf("corrupted read survived verification: %#x", block[0])
+	}
+}
+
+func TestReadMapDimensions(t *testing.T) {
+	r := MapRegion{Name: "test", Addr: memBase, Rows: 4, Cols: 8, Scale: 0.5, Offset: -10}
+	c := NewClient(newMemoryECU(testMem(r.Size()), false), 0, 0)
+	m, err := c.ReadMap(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Values) != 4 || len(m.Values[0]) != 8 {
+		t.Fatalf("map is %dx%d, want 4x8", len(m.Values), len(m.Values[0]))
+	}
+	// First cell: raw 0x0001 -> 0.5 - 10.
+	if m.Values[0][0] != -9.5 {
+		t.Errorf("cell 0,0 = %v, want -9.5", m.Values[0][0])
+	}
+}
@@ -0,0 +1,75 @@
+package uds
+
+import "errors"
+
+// Negative response codes (ISO 14229 annex A) the client tells apart.
+// Anything not listed still surfaces as a NegativeResponse; it just
+// prints as a bare hex code.
+const (
+	nrcGeneralReject           = 0x10
+	nrcServiceNotSupported     = 0x11
+	nrcSubFunctionNotSupported = 0x12
+	nrcIncorrectLength         = 0x13
+	nrcBusyRepeatRequest       = 0x21
+	nrcConditionsNotCorrect    = 0x22
+	nrcRequestSequenceError    = 0x24
+	nrcRequestOutOfRange       = 0x31
+	nrcSecurityAccessDenied    = 0x33
+	nrcInvalidKey              = 0x35
+	nrcExceededAttempts        = 0x36
+	nrcDelayNotExpired         = 0x37
+	nrcSubFunctionNotInSession = 0x7E
+	nrcServiceNotInSession     = 0x7F
+)
+
+var nrcNames = map[byte]string{
+	nrcGeneralReject:           "general reject",
+	nrcServiceNotSupported:     "service not supported",
+	nrcSubFunctionNotSupported: "sub-function not supported",
+	nrcIncorrectLength:         "incorrect message length",
+	nrcBusyRepeatRequest:       "busy, repeat request",
+	nrcConditionsNotCorrect:    "conditions not correct",
+	nrcRequestSequenceError:    "request sequence error",
+	nrcRequestOutOfRange:       "request out of range",
+	nrcSecurityAccessDenied:    "security access denied",
+	nrcInvalidKey:              "invalid key",
+	nrcExceededAttempts:        "exceeded number of attempts",
+	nrcDelayNotExpired:         "required time delay not expired",
+	nrcResponsePending:         "response pending",
+	nrcSubFunctionNotInSession: "sub-function not supported in active session",
+	nrcServiceNotInSession:     "service not supported in active session",
+}
+
+// NRCName returns the human-readable name for a negative response code,
+// or "" for codes this package doesn't know.
+func NRCName(code byte) string { return nrcNames[code] }
+
+// NotSupported reports whether the refusal means the ECU simply doesn't
+// implement what was asked — there is no point retrying, and pollers
+// should drop the channel rather than count a failure.
+func (e *NegativeResponse) NotSupported() bool {
+	switch e.Code {
+	case nrcServiceNotSupported, nrcSubFunctionNotSupported, nrcRequestOutOfRange,
+		nrcSubFunctionNotInSession, nrcServiceNotInSession:
+		return true
+	}
+	return false
+}
+
+// Busy reports whether the ECU refused only because it is occupied;
+// the same request is expected to succeed shortly.
+func (e *NegativeResponse) Busy() bool {
+	return e.Code == nrcBusyRepeatRequest || e.Code == nrcResponsePending
+}
+
+// IsNotSupported reports whether err is an ECU saying "I don't do that".
+func IsNotSupported(err error) bool {
+	var neg *NegativeResponse
+	return errors.As(err, &neg) && neg.NotSupported()
+}
+
+// IsBusy reports whether err is an ECU saying "not right now".
+func IsBusy(err error) bool {
+	var neg *NegativeResponse
+	return errors.As(err, &neg) && neg.Busy()
+}
@@ -0,0 +1,48 @@
+package uds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBusyRepeatIsRetried(t *testing.T) {
+	calls := 0
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		calls++
+		if calls == 1 {
+			return [][]byte{{0x7F, req[0], 0x21}} // busyRepeatRequest
+		}
+		return [][]byte{{0x50, 0x03}}
+	})
+	if err := NewClient(ecu, 0, 0).DiagnosticSessionControl(SessionExtended); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("ECU saw %d requests, want 2", calls)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{{0x7F, req[0], 0x11}} // serviceNotSupported
+	})
+	_, err := NewClient(ecu, 0, 0).ReadDataByIdentifier(0xF190)
+	if !IsNotSupported(err) {
+		t.Errorf("IsNotSupported(%v) = false", err)
+	}
+	if IsBusy(err) {
+		t.Errorf("IsBusy(%v) = true", err)
+	}
+	if !strings.Contains(err.Error(), "service not supported") {
+		t.Errorf("error lacks NRC name: %v", err)
+	}
+}
+
+func TestNRCName(t *testing.T) {
+	if got := NRCName(0x33); got != "security access denied" {
+		t.Errorf("NRCName(0x33) = %q", got)
+	}
+	if got := NRCName(0x99); got != "" {
+		t.Errorf("NRCName(0x99) = %q, want empty", got)
+	}
+}
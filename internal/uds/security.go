@@ -0,0 +1,75 @@
+package uds
+
+import (
+	"fmt"
+	"sync"
+)
+
+// KeyProvider computes the SecurityAccess key for a seed. Hyundai never
+// published the D4CB algorithms, so beyond the trivial built-in this is
+// a plug-in point: implementations come from user code or a Lua
+// script's compute_key hook.
+type KeyProvider interface {
+	ComputeKey(level byte, seed []byte) ([]byte, error)
+}
+
+// KeyFunc adapts a plain function to KeyProvider.
+type KeyFunc func(level byte, seed []byte) ([]byte, error)
+
+func (f KeyFunc) ComputeKey(level byte, seed []byte) ([]byte, error) { return f(level, seed) }
+
+var (
+	keyAlgoMu sync.RWMutex
+	keyAlgos  = map[string]KeyProvider{
+		// xor_ff is the placeholder transform several Korean-market ECUs
+		// of the era actually use: each seed byte inverted.
+		"xor_ff": KeyFunc(func(_ byte, seed []byte) ([]byte, error) {
+			key := make([]byte, len(seed))
+			for i, b := range seed {
+				key[i] = b ^ 0xFF
+			}
+			return key, nil
+		}),
+	}
+)
+
+// RegisterKeyAlgorithm makes a provider selectable by name in the
+// security config section. Registering an existing name replaces it.
+func RegisterKeyAlgorithm(name string, p KeyProvider) {
+	keyAlgoMu.Lock()
+	defer keyAlgoMu.Unlock()
+	keyAlgos[name] = p
+}
+
+// KeyAlgorithm looks up a registered provider by name.
+func KeyAlgorithm(name string) (KeyProvider, bool) {
+	keyAlgoMu.RLock()
+	defer keyAlgoMu.RUnlock()
+	p, ok := keyAlgos[name]
+	return p, ok
+}
+
+// Unlock runs the full SecurityAccess handshake at the given (odd) seed
+// level: request the seed, compute the key with p, send it back. An
+// all-zero seed means the level is already unlocked and nothing is sent.
+func (c *Client) Unlock(level byte, p KeyProvider) error {
+	seed, err := c.RequestSeed(level)
+	if err != nil {
+		return err
+	}
+	unlocked := true
+	for _, b := range seed {
+		if b != 0 {
+			unlocked = false
+			break
+		}
+	}
+	if unlocked {
+		return nil
+	}
+	key, err := p.ComputeKey(level, seed)
+	if err != nil {
+		return fmt.Errorf("uds: compute key for level 0x%02X: %w", level, err)
+	}
+	return c.SendKey(level+1, key)
+}
@@ -0,0 +1,60 @@
+package uds
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnlock(t *testing.T) {
+	var sentKey []byte
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		switch {
+		case bytes.Equal(req, []byte{0x27, 0x01}):
+			return [][]byte{{0x67, 0x01, 0xDE, 0xAD}}
+		case len(req) == 4 && req[0] == 0x27 && req[1] == 0x02:
+			sentKey = append([]byte(nil), req[2:]...)
+			return [][]byte{{0x67, 0x02}}
+		}
+		return [][]byte{{0x7F, req[0], 0x35}} // invalidKey
+	})
+	p, ok := KeyAlgorithm("xor_ff")
+	if !ok {
+		t.Fatal("xor_ff not registered")
+	}
+	if err := NewClient(ecu, 0, 0).Unlock(0x01, p); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sentKey, []byte{0x21, 0x52}) {
+		t.Errorf("key = % X, want 21 52", sentKey)
+	}
+}
+
+func TestUnlockSkipsKeyWhenAlreadyUnlocked(t *testing.T) {
+	keySent := false
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		if bytes.Equal(req, []byte{0x27, 0x01}) {
+			return [][]byte{{0x67, 0x01, 0x00, 0x00}} // all-zero seed
+		}
+		keySent = true
+		return [][]byte{{0x7F, req[0], 0x24}}
+	})
+	p, _ := KeyAlgorithm("xor_ff")
+	if err := NewClient(ecu, 0, 0).Unlock(0x01, p); err != nil {
+		t.Fatal(err)
+	}
+	if keySent {
+		t.Error("key sent despite all-zero seed")
+	}
+}
+
+func TestRegisterKeyAlgorithm(t *testing.T) {
+	RegisterKeyAlgorithm("test_algo", KeyFunc(func(_ byte, seed []byte) ([]byte, error) {
+		return seed, nil
+	}))
+	if _, ok := KeyAlgorithm("test_algo"); !ok {
+		t.Error("registered algorithm not found")
+	}
+	if _, ok := KeyAlgorithm("nonexistent"); ok {
+		t.Error("unknown algorithm found")
+	}
+}
@@ -0,0 +1,260 @@
+// Package uds speaks ISO 14229 (Unified Diagnostic Services) to a
+// single ECU over ISO-TP. Legacy OBD-II modes only expose the
+// standardised emissions set; the interesting iLoad data — injector
+// trims, calibration identifiers, engine maps — lives behind UDS data
+// identifiers and memory reads, some of them gated by a security
+// handshake.
+package uds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// Physical request/response pair for the engine ECU on the iLoad
+// diagnostic bus.
+const (
+	DefaultRequestID  = 0x7E0
+	DefaultResponseID = 0x7E8
+)
+
+// 29-bit OBD addressing (ISO 15765-4): DA is physical, DB functional;
+// the tester is node 0xF1, the engine ECU node 0x10. Vehicles answer on
+// one format or the other, never both.
+const (
+	DefaultRequestID29    = 0x18DA10F1
+	DefaultResponseID29   = 0x18DAF110
+	FunctionalRequestID29 = 0x18DB33F1
+)
+
+// standardIDMax is the largest classic 11-bit identifier; anything
+// above it selects extended addressing.
+const standardIDMax = 0x7FF
+
+// Service identifiers. A positive response echoes the service with 0x40
+// added.
+const (
+	sidSessionControl = 0x10
+	sidSecurityAccess = 0x27
+	sidTesterPresent  = 0x3E
+	sidReadDataByID   = 0x22
+	sidReadMemory     = 0x23
+
+	positiveOffset      = 0x40
+	negativeResponseSID = 0x7F
+	nrcResponsePending  = 0x78
+)
+
+// Session is a diagnostic session level for DiagnosticSessionControl.
+type Session byte
+
+const (
+	SessionDefault     Session = 0x01
+	SessionProgramming Session = 0x02
+	SessionExtended    Session = 0x03
+)
+
+// NegativeResponse is an ECU's refusal of a service request, carrying
+// the negative response code from ISO 14229 annex A.
+type NegativeResponse struct {
+	Service byte
+	Code    byte
+}
+
+func (e *NegativeResponse) Error() string {
+	if name := NRCName(e.Code); name != "" {
+		return fmt.Sprintf("uds: service 0x%02X rejected: %s (NRC 0x%02X)", e.Service, name, e.Code)
+	}
+	return fmt.Sprintf("uds: service 0x%02X rejected: NRC 0x%02X", e.Service, e.Code)
+}
+
+// Client issues UDS requests to one ECU. It owns the transport's
+// receive stream while a request is in flight, so share a bus between
+// consumers via a scheduler, not by calling Client concurrently.
+type Client struct {
+	tp *isotp
+	// ctx bounds every request this client issues; see WithContext.
+	ctx context.Context
+	// sched, when set, queues every exchange at prio; see WithScheduler.
+	sched *transport.Scheduler
+	prio  transport.Priority
+}
+
+// NewClient builds a client for the ECU at the given physical
+// request/response pair. Zero IDs use the engine ECU defaults; IDs
+// above the 11-bit range select 29-bit extended addressing on the wire.
+func NewClient(bus transport.Transport, reqID, respID uint32) *Client {
+	if reqID == 0 {
+		reqID = DefaultRequestID
+	}
+	if respID == 0 {
+		respID = DefaultResponseID
+	}
+	reqID &^= capture.EFFFlag
+	respID &^= capture.EFFFlag
+	return &Client{
+		tp: &isotp{
+			bus:      bus,
+			reqID:    reqID,
+			respID:   respID,
+			extended: reqID > standardIDMax || respID > standardIDMax,
+		},
+		ctx: context.Background(),
+	}
+}
+
+// WithContext returns a client whose requests are bounded by ctx on top
+// of the protocol timeouts: cancellation or a deadline aborts the wait
+// for a response mid-flight. The receiver is unchanged, so a shutdown
+// context can be bound once and per-call deadlines derived from it.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// WithScheduler returns a client whose exchanges queue on s at the
+// given priority. Consumers sharing one bus — the telemetry poller,
+// DTC reads, map dumps — must take turns, because an ISO-TP exchange
+// owns the transport's receive stream from request to final frame; the
+// scheduler makes them queue instead of interleave. The receiver is
+// unchanged.
+func (c *Client) WithScheduler(s *transport.Scheduler, p transport.Priority) *Client {
+	clone := *c
+	clone.sched = s
+	clone.prio = p
+	return &clone
+}
+
+// busyRetries bounds the automatic re-sends after "busy, repeat
+// request"; busyRetryDelay gives the ECU room before each one.
+// Response-pending is different — the ECU answers in its own time, so
+// the ISO-TP layer just waits longer rather than re-sending.
+const (
+	busyRetries    = 3
+	busyRetryDelay = 100 * time.Millisecond
+)
+
+// request round-trips one service request through the scheduler when
+// one is attached.
+func (c *Client) request(req []byte) ([]byte, error) {
+	if c.sched == nil {
+		return c.exchangeRequest(req)
+	}
+	var resp []byte
+	err := c.sched.Do(c.prio, func() error {
+		var err error
+		resp, err = c.exchangeRequest(req)
+		return err
+	})
+	return resp, err
+}
+
+// exchangeRequest round-trips one service request and validates the
+// response echoes the expected service. Busy refusals are retried a few
+// times before surfacing.
+func (c *Client) exchangeRequest(req []byte) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.tp.exchange(c.ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) >= 3 && resp[0] == negativeResponseSID {
+			neg := &NegativeResponse{Service: resp[1], Code: resp[2]}
+			if neg.Code == nrcBusyRepeatRequest && attempt < busyRetries {
+				select {
+				case <-time.After(busyRetryDelay):
+				case <-c.ctx.Done():
+					return nil, c.ctx.Err()
+				}
+				continue
+			}
+			return nil, neg
+		}
+		if len(resp) < 1 || resp[0] != req[0]+positiveOffset {
+			return nil, fmt.Errorf("uds: unexpected response 0x%02X to service 0x%02X", resp[0], req[0])
+		}
+		return resp[1:], nil
+	}
+}
+
+// Request issues a raw service request and returns the positive
+// response payload with the service echo stripped. It carries services
+// without a typed wrapper here — including the legacy OBD-II modes,
+// which share UDS framing and negative responses.
+func (c *Client) Request(req []byte) ([]byte, error) { return c.request(req) }
+
+// DiagnosticSessionControl switches the ECU into the given session.
+// Extended or programming sessions unlock services the default session
+// refuses; most ECUs drop back to default after a few seconds without
+// TesterPresent.
+func (c *Client) DiagnosticSessionControl(s Session) error {
+	_, err := c.request([]byte{sidSessionControl, byte(s)})
+	return err
+}
+
+// TesterPresent keeps a non-default session alive.
+func (c *Client) TesterPresent() error {
+	_, err := c.request([]byte{sidTesterPresent, 0x00})
+	return err
+}
+
+// RequestSeed starts a SecurityAccess handshake at the given level (an
+// odd subfunction) and returns the ECU's seed. An all-zero seed means
+// the level is already unlocked.
+func (c *Client) RequestSeed(level byte) ([]byte, error) {
+	if level%2 == 0 {
+		return nil, fmt.Errorf("uds: security access seed level 0x%02X must be odd", level)
+	}
+	resp, err := c.request([]byte{sidSecurityAccess, level})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 1 || resp[0] != level {
+		return nil, fmt.Errorf("uds: seed response for wrong level 0x%02X", resp[0])
+	}
+	return resp[1:], nil
+}
+
+// SendKey completes a SecurityAccess handshake with the key computed
+// from the seed; the key level is the seed level plus one.
+func (c *Client) SendKey(level byte, key []byte) error {
+	if level%2 == 1 {
+		return fmt.Errorf("uds: security access key level 0x%02X must be even", level)
+	}
+	_, err := c.request(append([]byte{sidSecurityAccess, level}, key...))
+	return err
+}
+
+// ReadDataByIdentifier reads one DID's record.
+func (c *Client) ReadDataByIdentifier(did uint16) ([]byte, error) {
+	resp, err := c.request([]byte{sidReadDataByID, byte(did >> 8), byte(did)})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 || uint16(resp[0])<<8|uint16(resp[1]) != did {
+		return nil, fmt.Errorf("uds: response for wrong DID")
+	}
+	return resp[2:], nil
+}
+
+// ReadMemoryByAddress reads size bytes from the ECU's address space,
+// using 4-byte addresses and 2-byte sizes (format 0x24) — what the
+// iLoad's 32-bit ECUs expect.
+func (c *Client) ReadMemoryByAddress(addr uint32, size uint16) ([]byte, error) {
+	req := []byte{sidReadMemory, 0x24,
+		byte(addr >> 24), byte(addr >> 16), byte(addr >> 8), byte(addr),
+		byte(size >> 8), byte(size)}
+	data, err := c.request(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != int(size) {
+		return nil, fmt.Errorf("uds: read %d bytes at 0x%08X, want %d", len(data), addr, size)
+	}
+	return data, nil
+}
@@ -0,0 +1,284 @@
+package uds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+)
+
+// fakeECU is an in-memory transport that speaks just enough ISO-TP to
+// answer the client: requests are reassembled and passed to handle,
+// which returns the responses to emit, in order.
+type fakeECU struct {
+	handle func(req []byte) [][]byte
+
+	// reqID and respID are the raw wire IDs (flag bits included); 29-bit
+	// tests override the 11-bit defaults.
+	reqID  uint32
+	respID uint32
+	out    chan capture.CANFrame
+	rxBuf  []byte
+	rxLen  int
+	// tx holds consecutive frames queued behind the client's flow control.
+	tx [][8]byte
+}
+
+func newFakeECU(handle func(req []byte) [][]byte) *fakeECU {
+	return &fakeECU{
+		handle: handle,
+		reqID:  DefaultRequestID,
+		respID: DefaultResponseID,
+		out:    make(chan capture.CANFrame, 64),
+	}
+}
+
+func (e *fakeECU) Frames() <-chan capture.CANFrame { return e.out }
+func (e *fakeECU) Close() error                    { close(e.out); return nil }
+
+func (e *fakeECU) Send(f capture.CANFrame) error {
+	if f.ID != e.reqID {
+		return nil
+	}
+	switch f.Data[0] >> 4 {
+	case pciSingle:
+		n := int(f.Data[0] & 0x0F)
+		e.respond(append([]byte(nil), f.Data[1:1+n]...))
+	case pciFirst:
+		e.rxLen = int(f.Data[0]&0x0F)<<8 | int(f.Data[1])
+		e.rxBuf = append([]byte(nil), f.Data[2:8]...)
+		e.emit([8]byte{pciFlowControl << 4, 0, 0})
+	case pciConsecutive:
+		e.rxBuf = append(e.rxBuf, f.Data[1:8]...)
+		if len(e.rxBuf) >= e.rxLen {
+			e.respond(e.rxBuf[:e.rxLen])
+		}
+	case pciFlowControl:
+		for _, fr := range e.tx {
+			e.out <- capture.CANFrame{ID: e.respID, Length: 8, Data: fr}
+		}
+		e.tx = nil
+	}
+	return nil
+}
+
+func (e *fakeECU) respond(req []byte) {
+	for _, resp := range e.handle(req) {
+		if len(resp) <= 7 {
+			var d [8]byte
+			d[0] = byte(len(resp))
+			copy(d[1:], resp)
+			e.emit(d)
+			continue
+		}
+		var first [8]byte
+		first[0] = byte(pciFirst<<4 | len(resp)>>8)
+		first[1] = byte(len(resp))
+		copy(first[2:], resp[:6])
+		e.emit(first)
+		rest := resp[6:]
+		for seq := byte(1); len(rest) > 0; seq = (seq + 1) & 0x0F {
+			var d [8]byte
+			d[0] = pciConsecutive<<4 | seq
+			n := copy(d[1:], rest)
+			rest = rest[n:]
+			e.tx = append(e.tx, d)
+		}
+	}
+}
+
+func (e *fakeECU) emit(d [8]byte) {
+	e.out <- capture.CANFrame{ID: e.respID, Length: 8, Data: d}
+}
+
+func TestReadDataByIdentifier(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		if !bytes.Equal(req, []byte{0x22, 0xF1, 0x90}) {
+			t.Errorf("request = % X", req)
+		}
+		return [][]byte{{0x62, 0xF1, 0x90, 'V', 'I', 'N'}}
+	})
+	c := NewClient(ecu, 0, 0)
+	data, err := c.ReadDataByIdentifier(0xF190)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "VIN" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestExtendedAddressing(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		if !bytes.Equal(req, []byte{0x22, 0xF1, 0x90}) {
+			t.Errorf("request = % X", req)
+		}
+		return [][]byte{{0x62, 0xF1, 0x90, 'V'}}
+	})
+	// The wire carries the EFF flag on both directions; the client is
+	// handed plain 29-bit numbers.
+	ecu.reqID = DefaultRequestID29 | capture.EFFFlag
+	ecu.respID = DefaultResponseID29 | capture.EFFFlag
+	data, err := NewClient(ecu, DefaultRequestID29, DefaultResponseID29).ReadDataByIdentifier(0xF190)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "V" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestMultiFrameResponseReassembly(t *testing.T) {
+	record := make([]byte, 100)
+	for i := range record {
+		record[i] = byte(i)
+	}
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{append([]byte{0x62, 0x01, 0x23}, record...)}
+	})
+	data, err := NewClient(ecu, 0, 0).ReadDataByIdentifier(0x0123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, record) {
+		t.Errorf("reassembled %d bytes, want %d intact", len(data), len(record))
+	}
+}
+
+func TestNegativeResponse(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{{0x7F, req[0], 0x31}} // requestOutOfRange
+	})
+	_, err := NewClient(ecu, 0, 0).ReadDataByIdentifier(0xFFFF)
+	var neg *NegativeResponse
+	if !errors.As(err, &neg) {
+		t.Fatalf("err = %v, want NegativeResponse", err)
+	}
+	if neg.Service != 0x22 || neg.Code != 0x31 {
+		t.Errorf("negative response = %+v", neg)
+	}
+}
+
+func TestResponsePendingIsWaitedOut(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{
+			{0x7F, req[0], 0x78}, // responsePending
+			{0x50, 0x03},
+		}
+	})
+	if err := NewClient(ecu, 0, 0).DiagnosticSessionControl(SessionExtended); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSecurityAccessHandshake(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		switch {
+		case bytes.Equal(req, []byte{0x27, 0x01}):
+			return [][]byte{{0x67, 0x01, 0xDE, 0xAD}}
+		case bytes.Equal(req, []byte{0x27, 0x02, 0x21, 0x52}): // seed ^ 0xFF
+			return [][]byte{{0x67, 0x02}}
+		}
+		return [][]byte{{0x7F, req[0], 0x35}} // invalidKey
+	})
+	c := NewClient(ecu, 0, 0)
+	seed, err := c.RequestSeed(0x01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, len(seed))
+	for i, b := range seed {
+		key[i] = b ^ 0xFF
+	}
+	if err := c.SendKey(0x02, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RequestSeed(0x02); err == nil {
+		t.Error("even seed level accepted")
+	}
+}
+
+func TestReadMemoryByAddress(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		want := []byte{0x23, 0x24, 0x00, 0x01, 0x02, 0x03, 0x00, 0x04}
+		if !bytes.Equal(req, want) {
+			t.Errorf("request = % X, want % X", req, want)
+		}
+		return [][]byte{{0x63, 0xAA, 0xBB, 0xCC, 0xDD}}
+	})
+	data, err := NewClient(ecu, 0, 0).ReadMemoryByAddress(0x00010203, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0xAA, 0xBB, 0xCC, 0xDD}) {
+		t.Errorf("data = % X", data)
+	}
+}
+
+func TestMultiFrameRequestSegmentation(t *testing.T) {
+	long := make([]byte, 20)
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		if !bytes.Equal(req, append([]byte{0x27, 0x02}, long...)) {
+			t.Errorf("reassembled request = % X", req)
+		}
+		return [][]byte{{0x67, 0x02}}
+	})
+	if err := NewClient(ecu, 0, 0).SendKey(0x02, long); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextCancelsRequest(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return nil // silent ECU: the client would wait out the full P2
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewClient(ecu, 0, 0).WithContext(ctx).Request([]byte{0x22, 0xF1, 0x90})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("cancellation took %s", elapsed)
+	}
+}
+
+func TestMalformedSingleFrameIgnored(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{{0x62, 0xF1, 0x90, 'O', 'K'}}
+	})
+	// Garbage already on the bus ahead of the answer: a single frame
+	// claiming 15 data bytes must be dropped, not slice past the frame.
+	ecu.emit([8]byte{0x0F, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF, 0x11})
+	c := NewClient(ecu, 0, 0)
+	data, err := c.ReadDataByIdentifier(0xF190)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "OK" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestScheduledRequests(t *testing.T) {
+	ecu := newFakeECU(func(req []byte) [][]byte {
+		return [][]byte{{0x62, 0xF1, 0x90, 'O', 'K'}}
+	})
+	sched := transport.NewScheduler(0)
+	c := NewClient(ecu, 0, 0).WithScheduler(sched, transport.PriorityLive)
+	data, err := c.ReadDataByIdentifier(0xF190)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "OK" {
+		t.Errorf("data = %q", data)
+	}
+	if n := sched.Pending(); n != 0 {
+		t.Errorf("%d jobs still queued after the exchange", n)
+	}
+}
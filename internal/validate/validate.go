@@ -0,0 +1,104 @@
+// Package validate screens incoming telemetry for physically implausible
+// values — a failed OBD read decoding as -40°C coolant, a speed that
+// jumps 0→200 km/h in one tick — so garbage never reaches alerts,
+// storage or analysis. Rejections are counted per reason so a flaky
+// adapter shows up in the numbers instead of in the charts.
+package validate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Plausibility bounds for the iLoad. Range limits catch sensor sentinel
+// values (the OBD temperature encoding bottoms out at -40 when a read
+// fails); rate limits catch single-tick jumps no vehicle can produce.
+const (
+	maxRPM          = 6000 // D4CB redline is ~4500; leave margin for overrun
+	maxSpeed        = 220  // km/h
+	minTemp         = -39  // -40 is the failed-read sentinel
+	maxCoolant      = 135  // °C
+	maxSpeedJump    = 15   // m/s² — beyond any braking the van can manage
+	maxCoolantRamp  = 5    // °C/s — thermal mass rules out faster swings
+	maxRateCheckGap = 10 * time.Second
+)
+
+// Validator screens telemetry samples and counts rejections by reason.
+// It is safe for concurrent use.
+type Validator struct {
+	mu       sync.Mutex
+	prev     map[string]datastore.TelemetryData // per VIN, for rate checks
+	rejected map[string]int
+}
+
+// New builds a validator with empty counters.
+func New() *Validator {
+	return &Validator{
+		prev:     make(map[string]datastore.TelemetryData),
+		rejected: make(map[string]int),
+	}
+}
+
+// Check screens one sample. It returns ok=false and the rejection reason
+// for implausible samples; accepted samples become the rate-check
+// baseline for their VIN.
+func (v *Validator) Check(t datastore.TelemetryData) (ok bool, reason string) {
+	if reason = v.check(t); reason != "" {
+		v.mu.Lock()
+		v.rejected[reason]++
+		v.mu.Unlock()
+		return false, reason
+	}
+	v.mu.Lock()
+	v.prev[t.VIN] = t
+	v.mu.Unlock()
+	return true, ""
+}
+
+func (v *Validator) check(t datastore.TelemetryData) string {
+	switch {
+	case t.RPM < 0 || t.RPM > maxRPM:
+		return "rpm_range"
+	case t.Speed < 0 || t.Speed > maxSpeed:
+		return "speed_range"
+	case t.CoolantTemp != 0 && (t.CoolantTemp < minTemp || t.CoolantTemp > maxCoolant):
+		return "coolant_range"
+	case t.IntakeTemp != 0 && t.IntakeTemp < minTemp:
+		return "intake_range"
+	case t.AmbientTemp != 0 && t.AmbientTemp < minTemp:
+		return "ambient_range"
+	}
+
+	v.mu.Lock()
+	prev, have := v.prev[t.VIN]
+	v.mu.Unlock()
+	if !have {
+		return ""
+	}
+	dt := t.Timestamp.Sub(prev.Timestamp).Seconds()
+	if dt <= 0 || dt > maxRateCheckGap.Seconds() {
+		return ""
+	}
+	if accel := (t.Speed - prev.Speed) / 3.6 / dt; accel > maxSpeedJump || accel < -maxSpeedJump {
+		return "speed_jump"
+	}
+	if prev.CoolantTemp != 0 && t.CoolantTemp != 0 {
+		if ramp := (t.CoolantTemp - prev.CoolantTemp) / dt; ramp > maxCoolantRamp || ramp < -maxCoolantRamp {
+			return "coolant_jump"
+		}
+	}
+	return ""
+}
+
+// Counters returns a copy of the per-reason rejection counts.
+func (v *Validator) Counters() map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make(map[string]int, len(v.rejected))
+	for reason, n := range v.rejected {
+		out[reason] = n
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+func TestCheckRejectsSentinelTemperature(t *testing.T) {
+	v := New()
+	ok, reason := v.Check(datastore.TelemetryData{
+		VIN: "TESTVIN", Timestamp: time.Now(), RPM: 800, CoolantTemp: -40,
+	})
+	if ok || reason != "coolant_range" {
+		t.Errorf("got ok=%v reason=%q, want rejection for coolant_range", ok, reason)
+	}
+	if v.Counters()["coolant_range"] != 1 {
+		t.Errorf("counter = %d, want 1", v.Counters()["coolant_range"])
+	}
+}
+
+func TestCheckRejectsSpeedJump(t *testing.T) {
+	v := New()
+	base := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	if ok, _ := v.Check(datastore.TelemetryData{VIN: "TESTVIN", Timestamp: base, Speed: 0}); !ok {
+		t.Fatal("baseline sample rejected")
+	}
+	ok, reason := v.Check(datastore.TelemetryData{
+		VIN: "TESTVIN", Timestamp: base.Add(time.Second), Speed: 200,
+	})
+	if ok || reason != "speed_jump" {
+		t.Errorf("got ok=%v reason=%q, want rejection for speed_jump", ok, reason)
+	}
+	// A rejected sample must not become the rate baseline: the next
+	// plausible sample continues from 0 km/h.
+	if ok, _ := v.Check(datastore.TelemetryData{
+		VIN: "TESTVIN", Timestamp: base.Add(2 * time.Second), Speed: 10,
+	}); !ok {
+		t.Error("plausible follow-up sample rejected")
+	}
+}
+
+func TestCheckAcceptsNormalDriving(t *testing.T) {
+	v := New()
+	base := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		ok, reason := v.Check(datastore.TelemetryData{
+			VIN:         "TESTVIN",
+			Timestamp:   base.Add(time.Duration(i) * time.Second),
+			RPM:         1500 + float64(i)*100,
+			Speed:       float64(i) * 8,
+			CoolantTemp: 85 + float64(i)*0.2,
+		})
+		if !ok {
+			t.Fatalf("sample %d rejected: %s", i, reason)
+		}
+	}
+	if n := len(v.Counters()); n != 0 {
+		t.Errorf("got %d rejection reasons, want 0", n)
+	}
+}
@@ -0,0 +1,61 @@
+package vin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeILoad(t *testing.T) {
+	info, err := Decode("KMFWB37HXAU123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.WMI != "KMF" || info.VDS != "WB37HX" || info.VIS != "AU123456" {
+		t.Errorf("sections = %+v", info)
+	}
+	if info.Make != "Hyundai" || info.Model != "iLoad/H-1" {
+		t.Errorf("make/model = %q/%q", info.Make, info.Model)
+	}
+	if info.Year != 2010 {
+		t.Errorf("year = %d, want 2010", info.Year)
+	}
+}
+
+func TestDecodeUnknownManufacturer(t *testing.T) {
+	info, err := Decode("WVWZZZ7HZ8H123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Make != "" || info.Model != "" {
+		t.Errorf("unknown WMI produced %q/%q", info.Make, info.Model)
+	}
+	if info.Year != 2008 {
+		t.Errorf("year = %d, want 2008", info.Year)
+	}
+}
+
+func TestDecodeRejectsMalformed(t *testing.T) {
+	if _, err := Decode("KMFWB37HXAU12345"); err == nil {
+		t.Error("16-character VIN accepted")
+	}
+	if _, err := Decode("KMFWB37HXOU123456"); err == nil {
+		t.Error("forbidden letter O accepted")
+	}
+}
+
+func TestYearCycleDisambiguation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// X reads as 2029, which is in the future, so it must mean 1999.
+	if y := yearOf('X', now); y != 1999 {
+		t.Errorf("X = %d, want 1999", y)
+	}
+	if y := yearOf('T', now); y != 2026 {
+		t.Errorf("T = %d, want 2026", y)
+	}
+	if y := yearOf('5', now); y != 2005 {
+		t.Errorf("5 = %d, want 2005", y)
+	}
+	if y := yearOf('?', now); y != 0 {
+		t.Errorf("invalid code = %d, want 0", y)
+	}
+}
@@ -0,0 +1,491 @@
+// Command iload-obd2 is a CANBUS/OBD-II data logger daemon for the
+// Hyundai iLoad/H-1. It polls the vehicle, persists telemetry and serves
+// a fleet dashboard API.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/anodyne74/iload-obd2/capture"
+	"github.com/anodyne74/iload-obd2/internal/agent"
+	"github.com/anodyne74/iload-obd2/internal/alerts"
+	"github.com/anodyne74/iload-obd2/internal/canid"
+	"github.com/anodyne74/iload-obd2/internal/clock"
+	"github.com/anodyne74/iload-obd2/internal/config"
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+	"github.com/anodyne74/iload-obd2/internal/dbc"
+	"github.com/anodyne74/iload-obd2/internal/derived"
+	"github.com/anodyne74/iload-obd2/internal/gps"
+	"github.com/anodyne74/iload-obd2/internal/i18n"
+	"github.com/anodyne74/iload-obd2/internal/mdns"
+	"github.com/anodyne74/iload-obd2/internal/obd"
+	"github.com/anodyne74/iload-obd2/internal/pcap"
+	"github.com/anodyne74/iload-obd2/internal/plugin"
+	"github.com/anodyne74/iload-obd2/internal/poll"
+	"github.com/anodyne74/iload-obd2/internal/power"
+	"github.com/anodyne74/iload-obd2/internal/report"
+	"github.com/anodyne74/iload-obd2/internal/rollup"
+	"github.com/anodyne74/iload-obd2/internal/sdnotify"
+	"github.com/anodyne74/iload-obd2/internal/server"
+	"github.com/anodyne74/iload-obd2/internal/transport"
+	"github.com/anodyne74/iload-obd2/internal/uds"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.yaml", "path to configuration file")
+		listen     = flag.String("listen", "", "HTTP listen address (overrides config)")
+		dbPath     = flag.String("db", "", "path to SQLite database (overrides config)")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if os.IsNotExist(err) {
+		cfg = config.Default()
+	} else if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	// Env overrides the file, flags override both — a container needs no
+	// config.yaml at all.
+	if err := cfg.ApplyEnv(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *listen != "" {
+		cfg.Listen = *listen
+	}
+	if *dbPath != "" {
+		cfg.Database = *dbPath
+	}
+
+	if cfg.I18n.Dir != "" {
+		if err := i18n.Default().LoadDir(cfg.I18n.Dir); err != nil {
+			log.Fatalf("load translations: %v", err)
+		}
+	}
+	if cfg.I18n.Locale != "" {
+		i18n.Default().SetLocale(cfg.I18n.Locale)
+	}
+
+	if cfg.DTC.DescriptionsFile != "" {
+		if err := obd.LoadDescriptions(cfg.DTC.DescriptionsFile); err != nil {
+			log.Fatalf("load DTC descriptions: %v", err)
+		}
+	}
+
+	store, err := datastore.OpenSQLite(cfg.Database)
+	if err != nil {
+		log.Fatalf("open datastore: %v", err)
+	}
+	defer store.Close()
+
+	mgr := config.NewManager(cfg)
+	if cfg.Remote.URL != "" {
+		go config.NewFetcher(cfg.Remote, mgr).Run(context.Background())
+	}
+
+	if cfg.Mode == "agent" {
+		if cfg.Agent.CentralURL == "" {
+			log.Fatal("agent mode requires agent.central_url")
+		}
+		log.Printf("agent mode: syncing to %s", cfg.Agent.CentralURL)
+		agent.New(store, cfg.Agent.CentralURL, cfg.Agent.Token, cfg.Agent.SyncInterval.Std()).
+			Run(context.Background())
+		return
+	}
+
+	cached := datastore.WithLatestCache(store)
+	srv := server.New(cached)
+	srv.SetPipelineIntervals(cfg.Poll.BroadcastInterval.Std(), cfg.Poll.PersistInterval.Std())
+	srv.SetSyncToken(cfg.Sync.Token)
+	srv.SetCaptureDir(cfg.Sync.CapturesDir)
+
+	var gpsRx *gps.Receiver
+	if cfg.GPS.Device != "" {
+		receiver, err := gps.Open(cfg.GPS.Device, cfg.GPS.Baud)
+		if err != nil {
+			log.Printf("gps: %v (continuing without position data)", err)
+		} else {
+			receiver.OnTime = clock.Default().CorrectGPS
+			go receiver.Run()
+			defer receiver.Close()
+			gpsRx = receiver
+		}
+	}
+
+	var trafficLog *transport.TrafficLog
+	if cfg.Debug.TrafficLog != "" {
+		trafficLog, err = transport.NewTrafficLog(cfg.Debug.TrafficLog)
+		if err != nil {
+			log.Fatalf("traffic log: %v", err)
+		}
+		defer trafficLog.Close()
+		srv.SetTrafficLog(trafficLog)
+	}
+
+	var monitors []*transport.BusMonitor
+	for _, bus := range cfg.Buses() {
+		if bus.Type != "" && bus.Type != "socketcan" {
+			continue // controller monitoring only makes sense for SocketCAN
+		}
+		if bus.AutoSetup {
+			err := transport.SetupInterface(context.Background(),
+				bus.Interface, bus.Bitrate, bus.ListenOnly)
+			if err != nil {
+				log.Printf("CAN setup %s: %v (continuing, interface may already be up)", bus.Interface, err)
+			}
+		}
+		monitor := transport.NewBusMonitor(bus.Interface, 0)
+		monitor.OnUnhealthy = func(h transport.BusHealth) {
+			a := &datastore.Alert{
+				Type:    "can_bus",
+				Message: i18n.T("alert.can_bus", h.Interface, h.State),
+			}
+			if err := cached.SaveAlert(a); err != nil {
+				log.Printf("save bus health alert: %v", err)
+			}
+		}
+		srv.AddBusMonitor(monitor)
+		monitors = append(monitors, monitor)
+		go monitor.Run(context.Background())
+	}
+	// Open every configured link — the primary bus, can.extra and the
+	// transports: list — and merge them into one Mux. Frame consumers and
+	// the diagnostic pollers all share this link. A bus that fails to
+	// open is logged and skipped so a missing adapter doesn't take down
+	// the API.
+	bus := transport.NewMux()
+	for _, b := range cfg.Buses() {
+		// Adapter links — serial ELM dongles and WiFi bridges — drop
+		// mid-drive, so they live behind the reconnector: redial with
+		// backoff, buffer in-flight sends, surface the link state to
+		// websocket clients. SocketCAN is a local interface; a missing
+		// one is a config problem, not a flaky cable.
+		if b.Type == "elm" || b.Type == "udp" {
+			b := b
+			rc := transport.NewReconnector(b.Name, func() (transport.Transport, error) {
+				t, err := transport.Open(b.Type, b.Interface)
+				if err != nil {
+					return nil, err
+				}
+				if err := transport.ApplyFilterSpecs(t, b.Filters); err != nil {
+					log.Printf("bus %s filters: %v (continuing unfiltered)", b.Name, err)
+				}
+				if trafficLog != nil {
+					t = transport.LogFrames(t, b.Name, trafficLog)
+				}
+				return t, nil
+			})
+			rc.OnChange = srv.ObserveTransportHealth
+			applied, _ := transport.ParseFilters(b.Filters)
+			srv.AddFilterTarget(b.Name, rc, applied)
+			rc.Start()
+			bus.Add(b.Name, rc)
+			continue
+		}
+		t, err := transport.Open(b.Type, b.Interface)
+		if err != nil {
+			log.Printf("open bus %s: %v (continuing without it)", b.Name, err)
+			continue
+		}
+		if err := transport.ApplyFilterSpecs(t, b.Filters); err != nil {
+			log.Printf("bus %s filters: %v (continuing unfiltered)", b.Name, err)
+			b.Filters = nil
+		}
+		// Filter-capable buses stay adjustable over the API, whether or
+		// not the config applied any at startup.
+		if fs, ok := t.(transport.FilterSetter); ok {
+			applied, _ := transport.ParseFilters(b.Filters)
+			srv.AddFilterTarget(b.Name, fs, applied)
+		}
+		if trafficLog != nil {
+			t = transport.LogFrames(t, b.Name, trafficLog)
+		}
+		bus.Add(b.Name, t)
+	}
+	defer bus.Close()
+	txPolicy, err := transport.ParsePolicy(cfg.TX.ReadOnly, cfg.TX.Allow, cfg.TX.Deny)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	var bridge *pcap.Bridge
+	if cfg.CAN.PcapListen != "" {
+		bridge = pcap.NewBridge(cfg.CAN.PcapListen)
+		go func() {
+			if err := bridge.Run(context.Background()); err != nil {
+				log.Printf("pcap bridge: %v", err)
+			}
+		}()
+	}
+	dtcWatcher := alerts.NewDTCWatcher(cached, cfg.Webhooks.DTCURL, cfg.Webhooks.BaseURL)
+	var recorder *capture.Recorder
+	if cfg.Capture.EventsDir != "" {
+		ring := capture.NewRing(cfg.Capture.Pre.Std())
+		recorder = capture.NewRecorder(ring, cfg.Capture.EventsDir, cfg.Capture.Post.Std(), cached)
+		dtcWatcher.OnDTC = func(vin, code string) { recorder.Trigger(vin, "dtc_"+code) }
+		var rules []capture.Rule
+		for _, t := range cfg.CaptureTriggers {
+			rule, err := capture.CompileRule(t.Name, t.Condition)
+			if err != nil {
+				log.Fatalf("capture trigger: %v", err)
+			}
+			rules = append(rules, rule)
+		}
+		recorder.SetRules(rules)
+		srv.SetRecorder(recorder)
+	}
+	var trip *capture.TripRecorder
+	if cfg.Capture.SessionsDir != "" {
+		enc, err := capture.ParseEncoding(cfg.Capture.Encoding)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		// Salvage anything a power cut left mid-recording before new
+		// trips start landing in the same directory.
+		recovered, err := capture.RecoverJournals(cfg.Capture.SessionsDir, enc)
+		if err != nil {
+			log.Printf("capture: recover journals: %v", err)
+		}
+		for _, p := range recovered {
+			log.Printf("capture: recovered interrupted recording %s", p)
+		}
+		trip = capture.NewTripRecorder(cfg.Capture.SessionsDir, enc, cached)
+		if cfg.Capture.Stream.Enabled {
+			trip.SetStream(capture.StreamOptions{
+				Gzip:     cfg.Capture.Stream.Gzip,
+				MaxBytes: int64(cfg.Capture.Stream.MaxMB) << 20,
+				MaxAge:   cfg.Capture.Stream.MaxAge.Std(),
+			})
+		}
+		defer trip.Close()
+	}
+	// Tee received frames out to every passive consumer — the Wireshark
+	// bridge, the capture recorders and the DBC signal decoder feeding
+	// the websocket stream — while the diagnostic clients keep the
+	// forwarded channel to themselves.
+	observed := transport.ObserveFrames(bus, func(f capture.CANFrame) {
+		if bridge != nil {
+			bridge.Publish(f)
+		}
+		if recorder != nil {
+			recorder.ObserveFrame(f)
+		}
+		if trip != nil {
+			trip.ObserveFrame(f)
+		}
+		srv.ObserveFrame(f)
+	})
+	// Everything that can publish onto a real bus holds the guarded
+	// handle, never the raw mux.
+	guarded := transport.Guard(observed, txPolicy)
+	// One scheduler serialises the bus between its consumers: live
+	// polling outranks diagnostics, which outrank bulk reads. ELM
+	// adapters also get a breather between jobs — clones drop
+	// back-to-back commands.
+	schedGap := time.Duration(0)
+	for _, b := range cfg.Buses() {
+		if b.Type == "elm" || b.Type == "udp" {
+			schedGap = 50 * time.Millisecond
+		}
+	}
+	sched := transport.NewScheduler(schedGap)
+	diagClient := obd.NewClient(guarded).WithScheduler(sched, transport.PriorityDiagnostic)
+	srv.SetDTCClearer(diagClient)
+	// A fresh code pulls the ECU's mode 02 snapshot so the stored event
+	// carries the conditions at the moment of fault.
+	dtcWatcher.FetchFreezeFrame = func() (*datastore.FreezeFrame, error) {
+		return diagClient.ReadFreezeFrame(0)
+	}
+	srv.SetDTCWatcher(dtcWatcher)
+	known := map[string]bool{}
+	for _, c := range derived.Builtins() {
+		known[c.Name] = true
+	}
+	var extraChannels []derived.Channel
+	for _, d := range cfg.Derived {
+		if err := derived.ValidateRefs(d.Expr, known); err != nil {
+			log.Fatalf("derived channel %s: %v", d.Name, err)
+		}
+		ch, err := derived.Compile(d.Name, d.Unit, d.Expr)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		extraChannels = append(extraChannels, ch)
+		known[d.Name] = true
+	}
+	srv.SetDerivedEngine(derived.NewEngine(extraChannels...))
+	if cfg.Plugins.Dir != "" {
+		plugins, err := plugin.Load(cfg.Plugins.Dir)
+		if err != nil {
+			log.Fatalf("load plugins: %v", err)
+		}
+		defer plugins.Close()
+		srv.SetPluginEngine(plugins)
+		// A script's compute_key hook becomes selectable as
+		// security.algorithm: "plugin".
+		uds.RegisterKeyAlgorithm("plugin", plugins)
+	}
+	if alg := cfg.Security.Algorithm; alg != "" {
+		if _, ok := uds.KeyAlgorithm(alg); !ok {
+			log.Fatalf("security: unknown key algorithm %q", alg)
+		}
+	}
+	srv.SetOverheatPredictor(alerts.NewOverheatPredictor(cached, cfg.Overheat.Threshold, cfg.Overheat.Horizon.Std()))
+	srv.SetSootWatcher(alerts.NewSootWatcher(cached, cfg.DPF.WarnPct))
+	if cfg.CAN.DBCFile != "" {
+		db, err := dbc.Load(cfg.CAN.DBCFile)
+		if err != nil {
+			log.Fatalf("load DBC: %v", err)
+		}
+		srv.SetDBC(db)
+	}
+	if cfg.CAN.LabelsFile != "" {
+		reg, err := canid.Load(cfg.CAN.LabelsFile)
+		if err != nil {
+			log.Fatalf("load CAN labels: %v", err)
+		}
+		srv.SetCANRegistry(reg)
+	}
+	if cfg.MDNS.Enabled {
+		_, portStr, err := net.SplitHostPort(cfg.Listen)
+		if err != nil {
+			log.Fatalf("mdns: cannot derive port from listen address %q: %v", cfg.Listen, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			log.Fatalf("mdns: cannot derive port from listen address %q: %v", cfg.Listen, err)
+		}
+		go func() {
+			if err := mdns.Advertise(context.Background(), cfg.MDNS.Instance, port); err != nil {
+				log.Printf("mdns: %v", err)
+			}
+		}()
+	}
+	// The live poll loop reads the vehicle over the guarded bus and
+	// feeds the shared ingest path; broadcast and persist pacing happen
+	// in the server's per-VIN pipelines.
+	poller := poll.New(obd.NewClient(guarded).WithScheduler(sched, transport.PriorityLive),
+		obd.DefaultCatalog(), cfg.Poll.PIDs, cfg.Poll.Interval.Std())
+	poller.StatusEvery = cfg.Poll.StatusInterval.Std()
+	// Per-ECU attribution broadcasts on the functional address, so it
+	// takes a diagnostic scheduler slot instead of riding the live
+	// client's priority.
+	poller.ReadByECU = func() ([]obd.ECUDTCs, error) {
+		var out []obd.ECUDTCs
+		err := sched.Do(transport.PriorityDiagnostic, func() error {
+			var err error
+			out, err = obd.ReadDTCsByECU(guarded)
+			return err
+		})
+		return out, err
+	}
+	poller.OnSample = func(t datastore.TelemetryData) {
+		// The latest GPS fix rides along on every sample that has none
+		// of its own.
+		if gpsRx != nil {
+			gpsRx.Annotate(&t)
+		}
+		if trip != nil {
+			if t.VIN != "" {
+				trip.SetVIN(t.VIN)
+			}
+			if t.Location != nil {
+				trip.ObserveLocation(t.Location.Latitude, t.Location.Longitude, t.Timestamp.UnixNano())
+			}
+		}
+		srv.Ingest(t)
+	}
+	// Engine-off detection idles the loop between drives; key-off also
+	// flushes anything the capture and persist windows still hold.
+	powerMon := power.NewMonitor(cfg.Power.OffDelay.Std(), cfg.Power.WakeCheck.Std())
+	powerMon.OnSleep = func() {
+		log.Printf("power: engine off, dropping to wake-check polling")
+		if recorder != nil {
+			recorder.Flush()
+		}
+		if trip != nil {
+			trip.Close()
+		}
+		srv.FlushPipelines()
+	}
+	powerMon.OnWake = func() {
+		log.Printf("power: engine running, resuming full-rate polling")
+		// Once per drive, pull the mode 09 in-use performance counters
+		// behind everything else on the bus; the stored series trends
+		// monitor health across drive cycles.
+		go func() {
+			err := sched.Do(transport.PriorityBackground, func() error {
+				perf, err := obd.NewClient(guarded).ReadInUsePerformance()
+				if err != nil {
+					return err
+				}
+				return cached.SaveInUsePerformance(*perf)
+			})
+			if err != nil {
+				log.Printf("in-use performance: %v", err)
+			}
+		}()
+	}
+	poller.Power = powerMon
+	go poller.Run(context.Background())
+	// Remotely fetched configs hot-apply to the running subsystems: the
+	// poll set and pacing, alert thresholds and capture trigger rules.
+	// Bus topology and listen addresses still take a restart.
+	mgr.Subscribe(func(c *config.Config) {
+		poller.Update(c.Poll.PIDs, c.Poll.Interval.Std())
+		srv.SetPipelineIntervals(c.Poll.BroadcastInterval.Std(), c.Poll.PersistInterval.Std())
+		srv.SetOverheatPredictor(alerts.NewOverheatPredictor(cached, c.Overheat.Threshold, c.Overheat.Horizon.Std()))
+		srv.SetSootWatcher(alerts.NewSootWatcher(cached, c.DPF.WarnPct))
+		if recorder != nil {
+			rules := make([]capture.Rule, 0, len(c.CaptureTriggers))
+			bad := false
+			for _, t := range c.CaptureTriggers {
+				rule, err := capture.CompileRule(t.Name, t.Condition)
+				if err != nil {
+					// A bad pushed rule keeps the previous set rather
+					// than killing a running daemon.
+					log.Printf("config: capture trigger %s: %v (keeping current rules)", t.Name, err)
+					bad = true
+					break
+				}
+				rules = append(rules, rule)
+			}
+			if !bad {
+				recorder.SetRules(rules)
+			}
+		}
+		log.Printf("config: applied pushed configuration")
+	})
+
+	go rollup.NewJob(cached, time.Hour).Run(context.Background())
+	if cfg.Reports.Enabled {
+		go report.NewScheduler(cached, cfg.Reports).Run(context.Background())
+	}
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		log.Fatalf("http server: %v", err)
+	}
+	log.Printf("listening on %s", cfg.Listen)
+	// Under systemd (Type=notify) signal readiness once we're accepting,
+	// and feed the watchdog only while every configured bus is usable.
+	sdnotify.Ready()
+	go sdnotify.RunWatchdog(context.Background(), func() bool {
+		for _, m := range monitors {
+			h := m.Health()
+			if h.State != transport.BusUnknown && !h.State.Healthy() {
+				return false
+			}
+		}
+		return true
+	})
+	if err := http.Serve(ln, srv); err != nil {
+		log.Fatalf("http server: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+// Package proto holds the canonical wire schema for telemetry, frames,
+// alerts and trips shared with downstream consumers in other languages.
+//
+// Regenerate the Go bindings with:
+//
+//	go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+//	go generate ./proto
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative iload/v1/iload.proto
@@ -0,0 +1,42 @@
+package iloadpb
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/anodyne74/iload-obd2/internal/datastore"
+)
+
+// Conversions between the wire messages and the datastore structs. Only
+// types that actually travel a sync path have converters; add more as
+// endpoints adopt the binary encoding.
+
+// AlertToProto converts one stored alert to its wire form.
+func AlertToProto(a datastore.Alert) *Alert {
+	return &Alert{
+		Id:           a.ID,
+		Vin:          a.VIN,
+		Type:         a.Type,
+		Message:      a.Message,
+		Value:        a.Value,
+		Threshold:    a.Threshold,
+		Timestamp:    timestamppb.New(a.Timestamp),
+		Acknowledged: a.Acknowledged,
+	}
+}
+
+// AlertFromProto converts a wire alert back to the stored form.
+func AlertFromProto(a *Alert) datastore.Alert {
+	out := datastore.Alert{
+		ID:           a.GetId(),
+		VIN:          a.GetVin(),
+		Type:         a.GetType(),
+		Message:      a.GetMessage(),
+		Value:        a.GetValue(),
+		Threshold:    a.GetThreshold(),
+		Acknowledged: a.GetAcknowledged(),
+	}
+	if ts := a.GetTimestamp(); ts != nil {
+		out.Timestamp = ts.AsTime()
+	}
+	return out
+}